@@ -2,30 +2,70 @@ package main
 
 import (
 	"app/internal/application"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
 	// env
-	// ...
+	// - config: defaults, config file, environment variables and flags, see application.LoadConfig
+	cs, err := application.LoadConfig(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if cs.PrintConfig {
+		err = cs.Print(os.Stdout)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// app
-	// - config
-	cfg := &application.ConfigApplicationDefault{
-		ServerAddress: ":8080",
-		LoaderFilePath: "docs/db/vehicles_100.json",
+	app := application.NewApplicationDefault(&cs.Config)
+	// - setup: it releases what it opened when it fails
+	err = app.SetUp()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	// - run: until SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errRun := make(chan error, 1)
+	go func() {
+		errRun <- app.Run()
+	}()
+	select {
+	case err = <-errRun:
+		// the server could not start, e.g. the address is in use: release the dependencies without draining
+		fmt.Println(err)
+		ctxNow, cancel := context.WithCancel(context.Background())
+		cancel()
+		app.Shutdown(ctxNow)
+		os.Exit(1)
+	case <-ctx.Done():
+		stop()
 	}
-	app := application.NewApplicationDefault(cfg)
-	// - setup
-	err := app.SetUp()
+	// - shutdown: a second signal cuts the drain and the in-flight requests short
+	ctxForce, stopForce := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopForce()
+	err = app.Shutdown(ctxForce)
 	if err != nil {
 		fmt.Println(err)
-		return
 	}
-	// - run
-	err = app.Run()
+	err = <-errRun
 	if err != nil {
 		fmt.Println(err)
-		return
 	}
 }
\ No newline at end of file
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/repository"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// main imports a vehicles file into a SQLite database, replacing the vehicles it already has
+func main() {
+	// flags
+	dataPath := flag.String("data", "docs/db/vehicles_100.json", "path to the vehicles file")
+	format := flag.String("format", "", "format of the vehicles file: json, json-stream, ndjson or csv (default: from the extension)")
+	policy := flag.String("policy", "", "policy for invalid vehicles: fail, skip or warn (default: warn)")
+	dbPath := flag.String("db", "vehicles.db", "path to the SQLite database file")
+	normalizedMatch := flag.Bool("normalized-match", false, "store the texts trimmed and in Unicode NFC, for servers that use normalized matching")
+	flag.Parse()
+
+	match := internal.TextMatchExact
+	if *normalizedMatch {
+		match = internal.TextMatchNormalized
+	}
+	err := run(*dataPath, *format, *policy, *dbPath, match)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// run is a function that migrates the database and imports the vehicles file into it
+func run(dataPath, format, policy, dbPath string, match internal.TextMatch) (err error) {
+	// dependencies
+	// - loader: loader for vehicles
+	// - no domain rules: the file is imported as is, the server reports them on its own load
+	ld, err := loader.NewLoaderVehicle(dataPath, format, internal.LoadPolicy(policy), nil, match)
+	if err != nil {
+		return
+	}
+	// - db: sqlite database
+	db, err := sql.Open(repository.SQLiteDriver, dbPath)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+	// - repository: repository for vehicles
+	rp := repository.NewRepositoryVehicleSQLite(db, match)
+	err = rp.Migrate()
+	if err != nil {
+		return
+	}
+
+	// import
+	r, err := rp.Import(ld)
+	if err != nil {
+		return
+	}
+	fmt.Printf("vehicles imported into %s: %d added, %d changed, %d removed\n", dbPath, r.Added, r.Changed, r.Removed)
+	return
+}
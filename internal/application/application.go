@@ -1,9 +1,14 @@
 package application
 
+import "context"
+
 // Application is an interface that represents an application
 type Application interface {
 	// SetUp is a method that sets up the application
 	SetUp() (err error)
 	// Run is a method that runs the application
+	// - returns nil once the application is stopped by Shutdown
 	Run() (err error)
+	// Shutdown is a method that stops the application gracefully, waiting for the work in progress until ctx is done
+	Shutdown(ctx context.Context) (err error)
 }
\ No newline at end of file
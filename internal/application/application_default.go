@@ -1,42 +1,237 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
 	"app/internal/loader"
 	"app/internal/repository"
 	"app/internal/service"
+	"app/platform/buildinfo"
+	"app/platform/web/request"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// RepositoryBackendMap keeps the vehicles in memory, loaded from the vehicles file on every start
+	RepositoryBackendMap = "map"
+	// RepositoryBackendSQLite persists the vehicles in a SQLite file, imported once from the vehicles file
+	RepositoryBackendSQLite = "sqlite"
+)
+
+var (
+	// ErrApplicationUnknownBackend is returned when the repository backend is not supported
+	ErrApplicationUnknownBackend = errors.New("application: unknown repository backend")
+	// ErrApplicationInvalidConfig is returned when a configuration value is not valid
+	ErrApplicationInvalidConfig = errors.New("application: invalid config")
+)
+
+// repositoryVehicle is an interface that represents the repository the application is built on
+type repositoryVehicle interface {
+	internal.RepositoryVehicle
+	internal.RepositoryReplaceVehicle
+}
+
 // ConfigApplicationDefault is a struct that represents the configuration for ApplicationDefault
 type ConfigApplicationDefault struct {
 	// ServerAddress is the address where the server will be listening
 	ServerAddress string
+	// ServerReadTimeout is the maximum duration for reading a whole request, body included
+	// - zero means 15 seconds
+	ServerReadTimeout time.Duration
+	// ServerReadHeaderTimeout is the maximum duration for reading the headers of a request
+	// - zero means 5 seconds
+	ServerReadHeaderTimeout time.Duration
+	// ServerWriteTimeout is the maximum duration before timing out the writes of a response
+	// - zero means 30 seconds
+	ServerWriteTimeout time.Duration
+	// ServerIdleTimeout is the maximum duration a keep-alive connection waits for its next request
+	// - zero means 60 seconds
+	ServerIdleTimeout time.Duration
+	// ServerDrainPeriod is how long readiness reports not ready on shutdown before the server stops accepting requests,
+	// so that load balancers stop routing to it first
+	// - zero disables the drain, the server stops accepting requests right away
+	ServerDrainPeriod time.Duration
+	// ServerShutdownTimeout bounds the whole shutdown, drain period included, in-flight requests still running are cut
+	// - zero means 30 seconds
+	ServerShutdownTimeout time.Duration
 	// LoaderFilePath is the path to the file that contains the vehicles
 	LoaderFilePath string
+	// LoaderFormat is the format of the file that contains the vehicles: json, json-stream, ndjson or csv
+	// - empty means it is inferred from the file extension
+	LoaderFormat string
+	// LoaderPolicy is the policy applied to the vehicles that do not pass validation: fail, skip or warn
+	// - empty means warn
+	LoaderPolicy string
+	// LoaderWatchInterval is the interval used to poll the vehicles file for changes
+	// - zero disables watching, the dataset can still be reloaded with POST /admin/reload
+	// - a change is not reloaded if vehicles were written since the last load, POST /admin/reload?force=true discards them
+	// - ignored by the sqlite backend, whose database is never reloaded from the file
+	LoaderWatchInterval time.Duration
+	// RepositoryBackend is the storage of the vehicles: map or sqlite
+	// - empty means map
+	RepositoryBackend string
+	// RepositorySQLitePath is the path to the SQLite database file, used by the sqlite backend
+	// - the vehicles file is imported into it only when it has no vehicles
+	RepositorySQLitePath string
+	// RepositoryNormalizedMatch stores texts trimmed and in Unicode NFC, and compares them case folded
+	// - false means the text criteria of the finds are compared byte by byte and texts are stored as given
+	RepositoryNormalizedMatch bool
+	// AverageCapacityRounding is the rounding mode of the average capacity: none, half_up, half_even, down or up
+	// - empty means none, the exact average
+	AverageCapacityRounding string
+	// AverageCapacityPlaces is the number of decimal places kept by the rounding of the average capacity
+	AverageCapacityPlaces int
+	// AverageCapacityFormat is the format of the average capacity when the request does not choose one: float or integer
+	// - empty means float, integer keeps the truncated average of former clients
+	AverageCapacityFormat string
+	// ValidatorRegistrationFormats are the regular expressions a registration must match one of, by country code
+	// - empty means any non blank registration is valid
+	ValidatorRegistrationFormats map[string]string
+	// ValidatorDuplicateRegistrations accepts a registration already used by another vehicle
+	// - false means registrations must be unique, loads report the duplicates and writes reject them
+	ValidatorDuplicateRegistrations bool
+	// ValidatorYearMin is the oldest plausible fabrication year
+	// - zero means 1886
+	ValidatorYearMin int
+	// ValidatorYearMax is the newest plausible fabrication year
+	// - zero means the year after the current one
+	ValidatorYearMax int
+	// ValidatorFuelTypes are the known fuel types, compared case insensitively
+	// - empty means gas, gasoline, diesel, biodiesel, electric and hybrid
+	ValidatorFuelTypes []string
+	// ValidatorTransmissions are the known transmissions, compared case insensitively
+	// - empty means manual, automatic and semi-automatic
+	ValidatorTransmissions []string
+}
+
+// DefaultConfigApplicationDefault is a function that returns the values ApplicationDefault uses for the fields of its configuration left empty
+func DefaultConfigApplicationDefault() *ConfigApplicationDefault {
+	return &ConfigApplicationDefault{
+		ServerAddress: ":8080",
+		ServerReadTimeout: 15 * time.Second,
+		ServerReadHeaderTimeout: 5 * time.Second,
+		ServerWriteTimeout: 30 * time.Second,
+		ServerIdleTimeout: 60 * time.Second,
+		ServerShutdownTimeout: 30 * time.Second,
+		RepositoryBackend: RepositoryBackendMap,
+		RepositorySQLitePath: "vehicles.db",
+		AverageCapacityRounding: string(internal.RoundingNone),
+		AverageCapacityFormat: string(handler.AverageCapacityFormatFloat),
+		ValidatorYearMin: 1886,
+		ValidatorYearMax: time.Now().Year() + 1,
+		ValidatorFuelTypes: []string{"gas", "gasoline", "diesel", "biodiesel", "electric", "hybrid"},
+		ValidatorTransmissions: []string{"manual", "automatic", "semi-automatic"},
+	}
 }
 
 // NewApplicationDefault is a function that returns a new instance of ApplicationDefault
 func NewApplicationDefault(cfg *ConfigApplicationDefault) *ApplicationDefault {
 	// default values
 	defaultRouter := gin.New()
-	defaultConfig := &ConfigApplicationDefault{
-		ServerAddress: ":8080",
-	}
+	defaultConfig := DefaultConfigApplicationDefault()
 	if cfg != nil {
 		if cfg.ServerAddress != "" {
 			defaultConfig.ServerAddress = cfg.ServerAddress
 		}
+		if cfg.ServerReadTimeout > 0 {
+			defaultConfig.ServerReadTimeout = cfg.ServerReadTimeout
+		}
+		if cfg.ServerReadHeaderTimeout > 0 {
+			defaultConfig.ServerReadHeaderTimeout = cfg.ServerReadHeaderTimeout
+		}
+		if cfg.ServerWriteTimeout > 0 {
+			defaultConfig.ServerWriteTimeout = cfg.ServerWriteTimeout
+		}
+		if cfg.ServerIdleTimeout > 0 {
+			defaultConfig.ServerIdleTimeout = cfg.ServerIdleTimeout
+		}
+		if cfg.ServerDrainPeriod > 0 {
+			defaultConfig.ServerDrainPeriod = cfg.ServerDrainPeriod
+		}
+		if cfg.ServerShutdownTimeout > 0 {
+			defaultConfig.ServerShutdownTimeout = cfg.ServerShutdownTimeout
+		}
 		if cfg.LoaderFilePath != "" {
 			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
 		}
+		if cfg.LoaderFormat != "" {
+			defaultConfig.LoaderFormat = cfg.LoaderFormat
+		}
+		if cfg.LoaderPolicy != "" {
+			defaultConfig.LoaderPolicy = cfg.LoaderPolicy
+		}
+		if cfg.LoaderWatchInterval > 0 {
+			defaultConfig.LoaderWatchInterval = cfg.LoaderWatchInterval
+		}
+		if cfg.RepositoryBackend != "" {
+			defaultConfig.RepositoryBackend = cfg.RepositoryBackend
+		}
+		if cfg.RepositorySQLitePath != "" {
+			defaultConfig.RepositorySQLitePath = cfg.RepositorySQLitePath
+		}
+		defaultConfig.RepositoryNormalizedMatch = cfg.RepositoryNormalizedMatch
+		if cfg.AverageCapacityRounding != "" {
+			defaultConfig.AverageCapacityRounding = cfg.AverageCapacityRounding
+		}
+		if cfg.AverageCapacityPlaces != 0 {
+			defaultConfig.AverageCapacityPlaces = cfg.AverageCapacityPlaces
+		}
+		if cfg.AverageCapacityFormat != "" {
+			defaultConfig.AverageCapacityFormat = cfg.AverageCapacityFormat
+		}
+		defaultConfig.ValidatorRegistrationFormats = cfg.ValidatorRegistrationFormats
+		defaultConfig.ValidatorDuplicateRegistrations = cfg.ValidatorDuplicateRegistrations
+		if cfg.ValidatorYearMin != 0 {
+			defaultConfig.ValidatorYearMin = cfg.ValidatorYearMin
+		}
+		if cfg.ValidatorYearMax != 0 {
+			defaultConfig.ValidatorYearMax = cfg.ValidatorYearMax
+		}
+		if len(cfg.ValidatorFuelTypes) > 0 {
+			defaultConfig.ValidatorFuelTypes = cfg.ValidatorFuelTypes
+		}
+		if len(cfg.ValidatorTransmissions) > 0 {
+			defaultConfig.ValidatorTransmissions = cfg.ValidatorTransmissions
+		}
 	}
 
 	return &ApplicationDefault{
 		router: defaultRouter,
-		serverAddress: defaultConfig.ServerAddress,
+		server: &http.Server{
+			Addr: defaultConfig.ServerAddress,
+			Handler: defaultRouter,
+			ReadTimeout: defaultConfig.ServerReadTimeout,
+			ReadHeaderTimeout: defaultConfig.ServerReadHeaderTimeout,
+			WriteTimeout: defaultConfig.ServerWriteTimeout,
+			IdleTimeout: defaultConfig.ServerIdleTimeout,
+		},
+		serverDrainPeriod: defaultConfig.ServerDrainPeriod,
+		serverShutdownTimeout: defaultConfig.ServerShutdownTimeout,
 		loaderFilePath: defaultConfig.LoaderFilePath,
+		loaderFormat: defaultConfig.LoaderFormat,
+		loaderPolicy: defaultConfig.LoaderPolicy,
+		loaderWatchInterval: defaultConfig.LoaderWatchInterval,
+		repositoryBackend: defaultConfig.RepositoryBackend,
+		repositorySQLitePath: defaultConfig.RepositorySQLitePath,
+		repositoryMatch: repositoryMatch(defaultConfig.RepositoryNormalizedMatch),
+		averageCapacityRounding: internal.Rounding{Mode: internal.RoundingMode(defaultConfig.AverageCapacityRounding), Places: defaultConfig.AverageCapacityPlaces},
+		averageCapacityFormat: handler.AverageCapacityFormat(defaultConfig.AverageCapacityFormat),
+		validatorRegistrationFormats: defaultConfig.ValidatorRegistrationFormats,
+		validatorDuplicateRegistrations: defaultConfig.ValidatorDuplicateRegistrations,
+		validatorYearMin: defaultConfig.ValidatorYearMin,
+		validatorYearMax: defaultConfig.ValidatorYearMax,
+		validatorFuelTypes: defaultConfig.ValidatorFuelTypes,
+		validatorTransmissions: defaultConfig.ValidatorTransmissions,
 	}
 }
 
@@ -44,35 +239,173 @@ func NewApplicationDefault(cfg *ConfigApplicationDefault) *ApplicationDefault {
 type ApplicationDefault struct {
 	// router is the router / multiplexer that will be used by the application
 	router *gin.Engine
-	// serverAddress is the address where the server will be listening
-	serverAddress string
+	// server is the HTTP server of the router, built with the address and timeouts of the configuration
+	server *http.Server
+	// serverDrainPeriod is how long readiness reports not ready on shutdown before the server stops accepting requests
+	serverDrainPeriod time.Duration
+	// serverShutdownTimeout bounds the whole shutdown
+	serverShutdownTimeout time.Duration
+	// draining is set once the shutdown starts, readiness reports not ready from then on
+	draining atomic.Bool
 	// loaderFilePath is the path to the file that contains the vehicles
 	loaderFilePath string
+	// loaderFormat is the format of the file that contains the vehicles
+	loaderFormat string
+	// loaderPolicy is the policy applied to the vehicles that do not pass validation
+	loaderPolicy string
+	// loaderWatchInterval is the interval used to poll the vehicles file for changes
+	loaderWatchInterval time.Duration
+	// repositoryBackend is the storage of the vehicles
+	repositoryBackend string
+	// repositorySQLitePath is the path to the SQLite database file
+	repositorySQLitePath string
+	// repositoryMatch is the way the text criteria of the finds are compared
+	repositoryMatch internal.TextMatch
+	// averageCapacityRounding is the rounding of the average capacity
+	averageCapacityRounding internal.Rounding
+	// averageCapacityFormat is the default format of the average capacity
+	averageCapacityFormat handler.AverageCapacityFormat
+	// validatorRegistrationFormats are the registration formats by country code
+	validatorRegistrationFormats map[string]string
+	// validatorDuplicateRegistrations accepts registrations used by several vehicles
+	validatorDuplicateRegistrations bool
+	// validatorYearMin is the oldest plausible fabrication year
+	validatorYearMin int
+	// validatorYearMax is the newest plausible fabrication year
+	validatorYearMax int
+	// validatorFuelTypes are the known fuel types
+	validatorFuelTypes []string
+	// validatorTransmissions are the known transmissions
+	validatorTransmissions []string
+	// watcher is the watcher of the vehicles file, nil if watching is disabled
+	watcher *loader.WatcherFilePoll
+	// db is the SQLite database, nil unless the sqlite backend is used
+	db *sql.DB
 }
 
 // SetUp is a method that sets up the application
+// - the database is closed again if the setup fails, so there is nothing to shut down
 func (a *ApplicationDefault) SetUp() (err error) {
+	defer func() {
+		if err != nil && a.db != nil {
+			a.db.Close()
+			a.db = nil
+		}
+	}()
+
+	// config
+	if !a.averageCapacityRounding.Mode.Valid() || a.averageCapacityRounding.Places < 0 {
+		err = fmt.Errorf("%w: average capacity rounding %s with %d places", ErrApplicationInvalidConfig, a.averageCapacityRounding.Mode, a.averageCapacityRounding.Places)
+		return
+	}
+	if !a.averageCapacityFormat.Valid() {
+		err = fmt.Errorf("%w: average capacity format %s", ErrApplicationInvalidConfig, a.averageCapacityFormat)
+		return
+	}
+
 	// dependencies
+	// - validator: domain rules of the vehicles, checked on load and on every write
+	vv, err := a.validator()
+	if err != nil {
+		return
+	}
 	// - loader: loader for vehicles
-	ld := loader.NewLoaderVehicleJSON(a.loaderFilePath)
-	// - db: map of vehicles
-	db, err := ld.Load()
+	ld, err := loader.NewLoaderVehicle(a.loaderFilePath, a.loaderFormat, internal.LoadPolicy(a.loaderPolicy), vv, a.repositoryMatch)
 	if err != nil {
 		return
 	}
+	// - reporter: validation report of the loader, if it has one
+	rpt, _ := ld.(internal.LoaderReporter)
 	// - repository: repository for vehicles
-	rp := repository.NewRepositoryReadVehicleMap(db)
+	rp, err := a.repository(ld, rpt)
+	if err != nil {
+		return
+	}
+	// - repository: text search over the vehicles, kept in sync with every write and reload
+	rpSearch, err := repository.NewRepositoryVehicleSearch(rp)
+	if err != nil {
+		return
+	}
+	// - repository: checksum and count of the vehicles, recomputed on the first read after a write or reload
+	rpDataset, err := repository.NewRepositoryVehicleDataset(rpSearch)
+	if err != nil {
+		return
+	}
 	// - service: service for vehicles
-	sv := service.NewServiceVehicleDefault(rp)
+	sv := service.NewServiceVehicleDefault(rpDataset, vv, a.repositoryMatch)
+	// - aggregator: aggregator for vehicles
+	ag := service.NewAggregatorVehicleDefault(rpSearch, a.repositoryMatch)
+	// - searcher: text searcher for vehicles
+	sr := service.NewSearcherVehicleDefault(rpSearch)
+	// - reloader: reloader for the vehicles dataset
+	// - none for the sqlite backend, the database is the source of truth once imported and a reload would discard its writes
+	var rl internal.ReloaderVehicle
+	if a.repositoryBackend != RepositoryBackendSQLite {
+		rl = service.NewReloaderVehicleDefault(ld, rpDataset)
+	}
+	// - readiness: not ready while draining, nor when a dependency that implements ReadinessChecker reports it is not
+	// - the loader is only checked when it reloads the dataset, the sqlite backend does not read the file once imported
+	checks := []internal.ReadinessCheck{{Name: "server", Checker: internal.ReadinessFunc(a.ready)}}
+	if c, ok := ld.(internal.ReadinessChecker); ok && rl != nil {
+		checks = append(checks, internal.ReadinessCheck{Name: "loader", Checker: c})
+	}
+	if c, ok := rp.(internal.ReadinessChecker); ok {
+		checks = append(checks, internal.ReadinessCheck{Name: "repository", Checker: c})
+	}
 	// - handler: handler for vehicles
-	hd := handler.NewHandlerVehicle(sv)
+	hd := handler.NewHandlerVehicle(sv, &handler.ConfigHandlerVehicle{
+		CapacityRounding: a.averageCapacityRounding,
+		CapacityFormat: a.averageCapacityFormat,
+	})
+	// - handler: handler for aggregations of vehicles
+	hdAggregate := handler.NewHandlerAggregateVehicle(ag)
+	// - handler: handler for text searches of vehicles
+	hdSearch := handler.NewHandlerSearchVehicle(sr)
+	// - handler: handler for administrative tasks
+	hdAdmin := handler.NewHandlerAdmin(rl, rpt)
+	// - handler: handler for the health, readiness and version of the application
+	hdHealth := handler.NewHandlerHealth(checks, rpDataset, buildinfo.Read())
+	// - watcher: reloads the vehicles when the file changes, never discarding the vehicles written since the last load
+	switch {
+	case a.loaderWatchInterval > 0 && rl == nil:
+		log.Printf("vehicles file watching ignored: the %s backend is not reloaded from the file", a.repositoryBackend)
+	case a.loaderWatchInterval > 0:
+		a.watcher = loader.NewWatcherFilePoll(a.loaderFilePath, a.loaderWatchInterval, func() {
+			r, err := rl.Reload(false)
+			if errors.Is(err, internal.ErrRepositoryWritten) {
+				log.Printf("vehicles reload skipped, vehicles were written since the last load: POST /admin/reload?force=true discards them")
+				return
+			}
+			if err != nil {
+				log.Printf("vehicles reload failed, keeping previous dataset: %s", err.Error())
+				return
+			}
+			log.Printf("vehicles reloaded: %d added, %d changed, %d removed", r.Added, r.Changed, r.Removed)
+			logLoadReport(rpt)
+		})
+		a.watcher.Start()
+	}
 
 	// routes
 	// - middlewares
+	a.router.Use(request.IDGin())
 	a.router.Use(gin.Logger())
 	a.router.Use(gin.Recovery())
 	// - endpoints
+	// Check whether the process is alive
+	a.router.GET("/healthz", hdHealth.Health())
+	// Check whether the application can take requests
+	a.router.GET("/readyz", hdHealth.Ready())
+	// Get the build of the application and the dataset it serves
+	a.router.GET("/version", hdHealth.Version())
+
 	grVehicles := a.router.Group("/vehicles")
+	// Get vehicles by any combination of criteria (query)
+	grVehicles.GET("", hd.Find())
+	// Get a vehicle by id
+	grVehicles.GET("/:id", hd.FindByID())
+	// Get vehicles by registration, which is not unique
+	grVehicles.GET("/registration/:registration", hd.FindByRegistration())
 	// Get vehicles by color and year
 	grVehicles.GET("/color/:color/year/:year", hd.FindByColorAndYear())
 	// Get vehicles by brand between years
@@ -83,12 +416,186 @@ func (a *ApplicationDefault) SetUp() (err error) {
 	grVehicles.GET("/average_capacity/brand/:brand", hd.AverageCapacityByBrand())
 	// Get vehicles by weight range (query)
 	grVehicles.GET("/weight", hd.SearchByWeightRange())
+	// Get statistics of a numeric field, optionally grouped (query)
+	grVehicles.GET("/stats", hd.Stats())
+	// Get metrics of the vehicles grouped by any combination of fields (query)
+	grVehicles.GET("/aggregate", hdAggregate.Aggregate())
+	// Search vehicles by text (query)
+	grVehicles.GET("/search", hdSearch.Search())
+	// Create a vehicle
+	grVehicles.POST("", hd.Create())
+	// Replace a vehicle
+	grVehicles.PUT("/:id", hd.Update())
+	// Partially update a vehicle
+	grVehicles.PATCH("/:id", hd.Patch())
+	// Delete a vehicle
+	grVehicles.DELETE("/:id", hd.Delete())
+
+	grAdmin := a.router.Group("/admin")
+	// Reload the vehicles dataset
+	grAdmin.POST("/reload", hdAdmin.Reload())
+	// Get the validation report of the last load
+	grAdmin.GET("/load-report", hdAdmin.LoadReport())
+
+	return
+}
 
+// repository is a method that builds the repository of the configured backend
+func (a *ApplicationDefault) repository(ld internal.LoaderVehicle, rpt internal.LoaderReporter) (rp repositoryVehicle, err error) {
+	switch a.repositoryBackend {
+	case RepositoryBackendMap:
+		// - db: map of vehicles
+		var db map[int]internal.Vehicle
+		db, err = ld.Load()
+		logLoadReport(rpt)
+		if err != nil {
+			return
+		}
+		rp = repository.NewRepositoryReadVehicleMap(db, a.repositoryMatch)
+	case RepositoryBackendSQLite:
+		// - db: sqlite database, migrated on every start
+		a.db, err = sql.Open(repository.SQLiteDriver, a.repositorySQLitePath+"?_busy_timeout=5000&_journal_mode=WAL")
+		if err != nil {
+			return
+		}
+		rpSQLite := repository.NewRepositoryVehicleSQLite(a.db, a.repositoryMatch)
+		err = rpSQLite.Migrate()
+		if err != nil {
+			return
+		}
+		// - import: only into an empty database, so that writes are not lost on restart
+		var n int
+		n, err = rpSQLite.Count()
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			var r internal.ReloadReport
+			r, err = rpSQLite.Import(ld)
+			logLoadReport(rpt)
+			if err != nil {
+				return
+			}
+			log.Printf("vehicles imported into %s: %d added", a.repositorySQLitePath, r.Added)
+		}
+		rp = rpSQLite
+	default:
+		err = fmt.Errorf("%w: %s", ErrApplicationUnknownBackend, a.repositoryBackend)
+	}
 	return
 }
 
-// Run is a method that runs the application
+// repositoryMatch is a function that returns the way the repository compares text criteria
+func repositoryMatch(normalized bool) internal.TextMatch {
+	if normalized {
+		return internal.TextMatchNormalized
+	}
+	return internal.TextMatchExact
+}
+
+// validator is a method that returns the validator of the configured domain rules
+// - an invalid registration format or year range returns ErrApplicationInvalidConfig
+func (a *ApplicationDefault) validator() (vv *internal.VehicleValidatorRules, err error) {
+	// registration formats
+	formats := make(map[string]*regexp.Regexp, len(a.validatorRegistrationFormats))
+	for country, expr := range a.validatorRegistrationFormats {
+		formats[country], err = regexp.Compile(expr)
+		if err != nil {
+			err = fmt.Errorf("%w: registration format of %s: %s", ErrApplicationInvalidConfig, country, err.Error())
+			return
+		}
+	}
+
+	// year range
+	if a.validatorYearMin > a.validatorYearMax {
+		err = fmt.Errorf("%w: year range %d-%d", ErrApplicationInvalidConfig, a.validatorYearMin, a.validatorYearMax)
+		return
+	}
+
+	rules := []internal.VehicleRule{
+		internal.RuleRegistrationFormat(formats),
+		internal.RuleYearRange(a.validatorYearMin, a.validatorYearMax),
+		internal.RuleNonNegative(),
+		internal.RuleKnownValues(a.validatorFuelTypes, a.validatorTransmissions),
+	}
+	if !a.validatorDuplicateRegistrations {
+		rules = append(rules, internal.RuleUniqueRegistration())
+	}
+	vv = internal.NewVehicleValidatorRules(rules...)
+	return
+}
+
+// logLoadReport is a function that logs a summary of the validation report of the last load
+func logLoadReport(rpt internal.LoaderReporter) {
+	if rpt == nil {
+		return
+	}
+
+	r := rpt.Report()
+	log.Printf("vehicles load report (policy %s): %d read, %d loaded, %d skipped, issues: %d duplicate, %d missing_field, %d out_of_range, %d unknown_field, %d invalid_value, %d invalid",
+		r.Policy, r.Total, r.Loaded, r.Skipped,
+		r.Count(internal.LoadIssueDuplicate),
+		r.Count(internal.LoadIssueMissingField),
+		r.Count(internal.LoadIssueOutOfRange),
+		r.Count(internal.LoadIssueUnknownField),
+		r.Count(internal.LoadIssueInvalidValue),
+		r.Count(internal.LoadIssueInvalid),
+	)
+}
+
+// ready is a method that returns ErrNotReady once the shutdown starts
+func (a *ApplicationDefault) ready(ctx context.Context) (err error) {
+	if a.draining.Load() {
+		err = fmt.Errorf("%w: draining", internal.ErrNotReady)
+	}
+	return
+}
+
+// Run is a method that serves the requests until Shutdown stops the server
+// - returns nil once the server is stopped by Shutdown
 func (a *ApplicationDefault) Run() (err error) {
-	err = a.router.Run(a.serverAddress)
+	log.Printf("listening on %s", a.server.Addr)
+	err = a.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return
+}
+
+// Shutdown is a method that stops the application gracefully
+// - readiness reports not ready for the drain period, then the server stops accepting requests and waits for the in-flight ones
+// - the shutdown is bounded by the shutdown timeout of the configuration, and cut short when ctx is done
+// - the watcher of the vehicles file is stopped and the database closed once the server is stopped
+func (a *ApplicationDefault) Shutdown(ctx context.Context) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, a.serverShutdownTimeout)
+	defer cancel()
+
+	// drain
+	a.draining.Store(true)
+	if a.serverDrainPeriod > 0 {
+		log.Printf("draining for %s", a.serverDrainPeriod)
+		drain := time.NewTimer(a.serverDrainPeriod)
+		select {
+		case <-drain.C:
+		case <-ctx.Done():
+			drain.Stop()
+		}
+	}
+
+	// server
+	err = a.server.Shutdown(ctx)
+	if err != nil {
+		err = fmt.Errorf("server shutdown: %w", err)
+	}
+
+	// dependencies
+	if a.watcher != nil {
+		a.watcher.Stop()
+	}
+	if a.db != nil {
+		if errDB := a.db.Close(); errDB != nil {
+			err = errors.Join(err, fmt.Errorf("database close: %w", errDB))
+		}
+	}
 	return
 }
\ No newline at end of file
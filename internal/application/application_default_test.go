@@ -0,0 +1,190 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplicationDefault_SetUp is a function that tests the setup of the application
+func TestApplicationDefault_SetUp(t *testing.T) {
+	t.Run("should close the database when the setup fails", func(t *testing.T) {
+		// Arrange: the database is opened, then the import of the missing vehicles file fails
+		app := NewApplicationDefault(&ConfigApplicationDefault{
+			LoaderFilePath:       filepath.Join(t.TempDir(), "missing.json"),
+			RepositoryBackend:    RepositoryBackendSQLite,
+			RepositorySQLitePath: filepath.Join(t.TempDir(), "vehicles.db"),
+		})
+
+		// Act
+		err := app.SetUp()
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, app.db)
+	})
+}
+
+// TestApplicationDefault_Shutdown is a function that tests the shutdown of the application
+func TestApplicationDefault_Shutdown(t *testing.T) {
+	t.Run("should report not ready while draining, then stop the server", func(t *testing.T) {
+		// Arrange
+		app := NewApplicationDefault(&ConfigApplicationDefault{
+			ServerAddress:     "127.0.0.1:0",
+			LoaderFilePath:    "../../docs/db/vehicles_100.json",
+			ServerDrainPeriod: 200 * time.Millisecond,
+		})
+		require.NoError(t, app.SetUp())
+		errRun := make(chan error, 1)
+		go func() {
+			errRun <- app.Run()
+		}()
+		ready := func() int {
+			res := httptest.NewRecorder()
+			app.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			return res.Code
+		}
+		assert.Equal(t, http.StatusOK, ready())
+
+		// Act
+		errShutdown := make(chan error, 1)
+		go func() {
+			errShutdown <- app.Shutdown(context.Background())
+		}()
+
+		// Assert
+		assert.Eventually(t, func() bool { return ready() == http.StatusServiceUnavailable }, time.Second, 10*time.Millisecond)
+		select {
+		case err := <-errShutdown:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("shutdown did not return")
+		}
+		select {
+		case err := <-errRun:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("run did not return")
+		}
+	})
+
+	t.Run("should cut the drain short when the context is done", func(t *testing.T) {
+		// Arrange
+		app := NewApplicationDefault(&ConfigApplicationDefault{
+			ServerAddress:     "127.0.0.1:0",
+			LoaderFilePath:    "../../docs/db/vehicles_100.json",
+			ServerDrainPeriod: time.Minute,
+		})
+		require.NoError(t, app.SetUp())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// Act
+		start := time.Now()
+		err := app.Shutdown(ctx)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+// TestApplicationDefault_Probes is a function that tests the health, readiness and version endpoints of the application
+func TestApplicationDefault_Probes(t *testing.T) {
+	get := func(app *ApplicationDefault, target string) (code int, body map[string]any) {
+		res := httptest.NewRecorder()
+		app.router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, target, nil))
+		code = res.Code
+		_ = json.Unmarshal(res.Body.Bytes(), &body)
+		return
+	}
+	checkNames := func(body map[string]any) (names []string) {
+		checks, _ := body["checks"].([]any)
+		for _, c := range checks {
+			names = append(names, c.(map[string]any)["name"].(string))
+		}
+		return
+	}
+
+	t.Run("should check the server and the database, and report the same checksum once reopened", func(t *testing.T) {
+		// Arrange
+		cfg := &ConfigApplicationDefault{
+			LoaderFilePath:       "../../docs/db/vehicles_100.json",
+			RepositoryBackend:    RepositoryBackendSQLite,
+			RepositorySQLitePath: filepath.Join(t.TempDir(), "vehicles.db"),
+		}
+		imported := NewApplicationDefault(cfg)
+		require.NoError(t, imported.SetUp())
+
+		// Act
+		codeHealth, bodyHealth := get(imported, "/healthz")
+		codeReady, bodyReady := get(imported, "/readyz")
+		codeVersion, bodyVersion := get(imported, "/version")
+		require.NoError(t, imported.Shutdown(context.Background()))
+		reopened := NewApplicationDefault(cfg)
+		require.NoError(t, reopened.SetUp())
+		t.Cleanup(func() { reopened.Shutdown(context.Background()) })
+		codeReopened, bodyReopened := get(reopened, "/readyz")
+		_, bodyVersionReopened := get(reopened, "/version")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, codeHealth)
+		assert.Equal(t, "ok", bodyHealth["status"])
+		assert.Equal(t, http.StatusOK, codeReady)
+		assert.Equal(t, []string{"server", "repository"}, checkNames(bodyReady))
+		assert.Equal(t, http.StatusOK, codeVersion)
+		assert.Equal(t, float64(100), bodyVersion["vehicle_count"])
+		assert.Len(t, bodyVersion["dataset_checksum"], 64)
+		assert.NotEmpty(t, bodyVersion["go_version"])
+		assert.Equal(t, http.StatusOK, codeReopened)
+		assert.Equal(t, []string{"server", "repository"}, checkNames(bodyReopened))
+		assert.Equal(t, bodyVersion["dataset_checksum"], bodyVersionReopened["dataset_checksum"])
+	})
+	t.Run("should report the checksum of the vehicles after a write", func(t *testing.T) {
+		// Arrange
+		app := NewApplicationDefault(&ConfigApplicationDefault{
+			LoaderFilePath: "../../docs/db/vehicles_100.json",
+		})
+		require.NoError(t, app.SetUp())
+		_, bodyBefore := get(app, "/version")
+
+		// Act
+		res := httptest.NewRecorder()
+		app.router.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/vehicles/1", nil))
+		codeReady, bodyReady := get(app, "/readyz")
+		_, bodyAfter := get(app, "/version")
+
+		// Assert
+		require.Equal(t, http.StatusNoContent, res.Code)
+		assert.Equal(t, http.StatusOK, codeReady)
+		assert.Equal(t, []string{"server", "loader", "repository"}, checkNames(bodyReady))
+		assert.Equal(t, float64(100), bodyBefore["vehicle_count"])
+		assert.Equal(t, float64(99), bodyAfter["vehicle_count"])
+		assert.NotEqual(t, bodyBefore["dataset_checksum"], bodyAfter["dataset_checksum"])
+	})
+	t.Run("should report not ready once the vehicles file can not be reloaded", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+		app := NewApplicationDefault(&ConfigApplicationDefault{
+			LoaderFilePath: path,
+		})
+		require.NoError(t, app.SetUp())
+		require.NoError(t, os.Remove(path))
+
+		// Act
+		code, body := get(app, "/readyz")
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, []string{"server", "loader", "repository"}, checkNames(body))
+	})
+}
@@ -0,0 +1,591 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/loader"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigEnvPrefix is the prefix of the environment variables of the configuration, followed by the key in upper case
+	ConfigEnvPrefix = "VEHICLES_"
+	// ConfigEnvFile is the environment variable with the path to the config file, when the flag -config is not given
+	ConfigEnvFile = ConfigEnvPrefix + "CONFIG"
+)
+
+// configUsage explains where the configuration is read from, printed before the flags by -h
+const configUsage = `Usage: %s [flags]
+
+The configuration is read from these sources, each one overriding the previous ones:
+  1. the defaults
+  2. the config file given by -config or %s: YAML (.yaml, .yml), TOML (.toml) or JSON (.json),
+     with the keys printed by -print-config
+  3. the environment variables %s<KEY>, e.g. %sADDR; empty ones are ignored
+  4. the flags
+In the environment and the flags, lists are separated by commas and registration formats by semicolons,
+e.g. AR=^[A-Z]{3}\d{3}$;BR=^[A-Z]{3}\d[A-Z]\d{2}$; a config file gives them as lists and maps
+
+Flags:
+`
+
+// ConfigSources is a struct that represents the configuration of ApplicationDefault read from its sources
+type ConfigSources struct {
+	// Config is the resulting configuration
+	Config ConfigApplicationDefault
+	// Origins is where each value comes from by key, e.g. default, file config.yaml, env VEHICLES_ADDR or flag -addr
+	Origins map[string]string
+	// PrintConfig asks for the configuration to be printed instead of running the application
+	PrintConfig bool
+}
+
+// configSetting is a struct that represents a value of the configuration that can be set from any source
+// - key is its name in the config file, its flag is the key with dashes and its environment variable ConfigEnvPrefix followed by the key in upper case
+type configSetting struct {
+	// key is the name of the value
+	key string
+	// usage is the description of the value
+	usage string
+	// isBool makes the flag valid without an argument
+	isBool bool
+	// set parses the value and sets it in the configuration
+	set func(cfg *ConfigApplicationDefault, value string) (err error)
+	// setFile sets the value as decoded from a config file, nil if the value is a scalar parsed by set
+	setFile func(cfg *ConfigApplicationDefault, value any) (err error)
+	// get returns the value of the configuration, as it is written in a config file
+	get func(cfg *ConfigApplicationDefault) any
+}
+
+// flag is a method that returns the name of the flag of the setting
+func (s configSetting) flag() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+// env is a method that returns the name of the environment variable of the setting
+func (s configSetting) env() string {
+	return ConfigEnvPrefix + strings.ToUpper(s.key)
+}
+
+// configSettings are the values of the configuration, in the order they are printed
+var configSettings = []configSetting{
+	stringSetting("addr", "address the server listens on", func(c *ConfigApplicationDefault) *string { return &c.ServerAddress }),
+	durationSetting("read_timeout", "maximum duration for reading a whole request", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerReadTimeout }),
+	durationSetting("read_header_timeout", "maximum duration for reading the headers of a request", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerReadHeaderTimeout }),
+	durationSetting("write_timeout", "maximum duration for writing a response", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerWriteTimeout }),
+	durationSetting("idle_timeout", "maximum duration a keep-alive connection waits for its next request", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerIdleTimeout }),
+	durationSetting("drain_period", "duration readiness reports not ready on shutdown before the server stops accepting requests, 0 disables it", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerDrainPeriod }),
+	durationSetting("shutdown_timeout", "maximum duration of the shutdown, drain period included", func(c *ConfigApplicationDefault) *time.Duration { return &c.ServerShutdownTimeout }),
+	stringSetting("data_path", "path to the vehicles file", func(c *ConfigApplicationDefault) *string { return &c.LoaderFilePath }),
+	stringSetting("data_format", "format of the vehicles file: json, json-stream, ndjson, jsonl or csv (empty: from the extension)", func(c *ConfigApplicationDefault) *string { return &c.LoaderFormat }),
+	stringSetting("load_policy", "policy for invalid vehicles: fail, skip or warn (empty: warn)", func(c *ConfigApplicationDefault) *string { return &c.LoaderPolicy }),
+	durationSetting("watch_interval", "interval polling the vehicles file for changes, 0 disables watching; ignored by the sqlite backend, a change is not reloaded over API writes", func(c *ConfigApplicationDefault) *time.Duration { return &c.LoaderWatchInterval }),
+	stringSetting("repository_backend", "storage of the vehicles: map or sqlite", func(c *ConfigApplicationDefault) *string { return &c.RepositoryBackend }),
+	stringSetting("sqlite_path", "path to the SQLite database file of the sqlite backend", func(c *ConfigApplicationDefault) *string { return &c.RepositorySQLitePath }),
+	boolSetting("normalized_match", "store texts trimmed and in Unicode NFC, and compare the text criteria of the finds case folded", func(c *ConfigApplicationDefault) *bool { return &c.RepositoryNormalizedMatch }),
+	stringSetting("average_capacity_rounding", "rounding of the average capacity: none, half_up, half_even, down or up", func(c *ConfigApplicationDefault) *string { return &c.AverageCapacityRounding }),
+	intSetting("average_capacity_places", "decimal places kept by the rounding of the average capacity", func(c *ConfigApplicationDefault) *int { return &c.AverageCapacityPlaces }),
+	stringSetting("average_capacity_format", "default format of the average capacity: float or integer", func(c *ConfigApplicationDefault) *string { return &c.AverageCapacityFormat }),
+	mapSetting("registration_formats", "regular expressions a registration must match one of, by country code", func(c *ConfigApplicationDefault) *map[string]string { return &c.ValidatorRegistrationFormats }),
+	boolSetting("duplicate_registrations", "accept a registration already used by another vehicle", func(c *ConfigApplicationDefault) *bool { return &c.ValidatorDuplicateRegistrations }),
+	intSetting("year_min", "oldest plausible fabrication year", func(c *ConfigApplicationDefault) *int { return &c.ValidatorYearMin }),
+	intSetting("year_max", "newest plausible fabrication year", func(c *ConfigApplicationDefault) *int { return &c.ValidatorYearMax }),
+	listSetting("fuel_types", "known fuel types", func(c *ConfigApplicationDefault) *[]string { return &c.ValidatorFuelTypes }),
+	listSetting("transmissions", "known transmissions", func(c *ConfigApplicationDefault) *[]string { return &c.ValidatorTransmissions }),
+}
+
+// LoadConfig is a function that reads the configuration of ApplicationDefault from its sources
+// - precedence, from lowest to highest: defaults, config file, environment variables, flags
+// - the config file is the one given by -config or ConfigEnvFile, its format is inferred from its extension
+// - the resulting configuration is validated, see ConfigApplicationDefault.Validate
+// - -h prints the usage and returns flag.ErrHelp
+func LoadConfig(args []string, lookupEnv func(key string) (string, bool)) (cs *ConfigSources, err error) {
+	cs = &ConfigSources{Config: *DefaultConfigApplicationDefault(), Origins: make(map[string]string, len(configSettings))}
+	cs.Config.LoaderFilePath = "docs/db/vehicles_100.json"
+	cs.Config.LoaderWatchInterval = 5 * time.Second
+	for _, s := range configSettings {
+		cs.Origins[s.key] = "default"
+	}
+
+	// flags: parsed first as they can name the config file, applied last
+	fs := flag.NewFlagSet("vehicles", flag.ContinueOnError)
+	flags := make(map[string]string)
+	for _, s := range configSettings {
+		key := s.key
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env())
+		setFlag := func(value string) error {
+			flags[key] = value
+			return nil
+		}
+		if s.isBool {
+			fs.BoolFunc(s.flag(), usage, setFlag)
+			continue
+		}
+		fs.Func(s.flag(), usage, setFlag)
+	}
+	configPath := fs.String("config", "", fmt.Sprintf("path to a YAML, TOML or JSON config file (env %s)", ConfigEnvFile))
+	fs.BoolVar(&cs.PrintConfig, "print-config", false, "print the configuration with the origin of each value and exit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), configUsage, fs.Name(), ConfigEnvFile, ConfigEnvPrefix, ConfigEnvPrefix)
+		fs.PrintDefaults()
+	}
+	err = fs.Parse(args)
+	if err != nil {
+		return
+	}
+
+	// file
+	path := *configPath
+	if path == "" {
+		path, _ = lookupEnv(ConfigEnvFile)
+	}
+	if path != "" {
+		var values map[string]any
+		values, err = readConfigFile(path)
+		if err != nil {
+			return
+		}
+		for _, s := range configSettings {
+			if value, ok := values[s.key]; ok {
+				if err = cs.setFile(s, value, "file "+path); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	// env
+	for _, s := range configSettings {
+		if value, ok := lookupEnv(s.env()); ok && value != "" {
+			if err = cs.set(s, value, "env "+s.env()); err != nil {
+				return
+			}
+		}
+	}
+
+	// flags
+	for _, s := range configSettings {
+		if value, ok := flags[s.key]; ok {
+			if err = cs.set(s, value, "flag -"+s.flag()); err != nil {
+				return
+			}
+		}
+	}
+
+	err = cs.Config.Validate()
+	return
+}
+
+// set is a method that sets a value of the configuration and records its origin
+func (cs *ConfigSources) set(s configSetting, value string, origin string) (err error) {
+	err = s.set(&cs.Config, value)
+	if err != nil {
+		err = fmt.Errorf("%w: %s: %s", ErrApplicationInvalidConfig, origin, err.Error())
+		return
+	}
+	cs.Origins[s.key] = origin
+	return
+}
+
+// setFile is a method that sets a value decoded from a config file and records its origin
+// - lists and maps are set as decoded, their items are never joined into a text and split again
+func (cs *ConfigSources) setFile(s configSetting, value any, origin string) (err error) {
+	if s.setFile != nil {
+		err = s.setFile(&cs.Config, value)
+	} else {
+		var str string
+		if str, err = configFileScalar(value); err == nil {
+			err = s.set(&cs.Config, str)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %s: %s: %s", ErrApplicationInvalidConfig, origin, s.key, err.Error())
+		return
+	}
+	cs.Origins[s.key] = origin
+	return
+}
+
+// Print is a method that writes the configuration as a YAML config file, with the origin of each value as a comment
+func (cs *ConfigSources) Print(w io.Writer) (err error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range configSettings {
+		key := &yaml.Node{}
+		key.SetString(s.key)
+		value := &yaml.Node{}
+		if err = value.Encode(s.get(&cs.Config)); err != nil {
+			return
+		}
+		// - the comment goes after the value when it fits in the line of the key, after the key otherwise
+		if value.Kind == yaml.ScalarNode || len(value.Content) == 0 {
+			value.LineComment = cs.Origins[s.key]
+		} else {
+			key.LineComment = cs.Origins[s.key]
+		}
+		doc.Content = append(doc.Content, key, value)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(doc); err != nil {
+		return
+	}
+	err = enc.Close()
+	return
+}
+
+// Validate is a method that returns an error listing every value of the configuration that is not valid
+// - every error wraps ErrApplicationInvalidConfig
+// - empty values are valid as long as they have a default, see DefaultConfigApplicationDefault
+func (c *ConfigApplicationDefault) Validate() (err error) {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrApplicationInvalidConfig, fmt.Sprintf(format, args...)))
+	}
+	defaults := DefaultConfigApplicationDefault()
+
+	// server
+	// - the port is a number or a service name, as net.Listen accepts them
+	if c.ServerAddress != "" {
+		_, port, e := net.SplitHostPort(c.ServerAddress)
+		if e == nil {
+			_, e = net.LookupPort("tcp", port)
+		}
+		if e != nil {
+			invalid("addr %q is not a host:port address", c.ServerAddress)
+		}
+	}
+	timeouts := []struct {
+		key string
+		d   time.Duration
+	}{
+		{"read_timeout", c.ServerReadTimeout},
+		{"read_header_timeout", c.ServerReadHeaderTimeout},
+		{"write_timeout", c.ServerWriteTimeout},
+		{"idle_timeout", c.ServerIdleTimeout},
+		{"drain_period", c.ServerDrainPeriod},
+		{"shutdown_timeout", c.ServerShutdownTimeout},
+	}
+	for _, t := range timeouts {
+		if t.d < 0 {
+			invalid("%s %s must not be negative", t.key, t.d)
+		}
+	}
+	shutdownTimeout := c.ServerShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaults.ServerShutdownTimeout
+	}
+	if c.ServerDrainPeriod >= shutdownTimeout {
+		invalid("drain_period %s must be shorter than shutdown_timeout %s", c.ServerDrainPeriod, shutdownTimeout)
+	}
+
+	// loader
+	if c.LoaderFilePath == "" {
+		invalid("data_path is required")
+	}
+	switch c.LoaderFormat {
+	case "", loader.FormatJSON, loader.FormatJSONStream, loader.FormatNDJSON, loader.FormatJSONL, loader.FormatCSV:
+	default:
+		invalid("data_format %q is not one of json, json-stream, ndjson, jsonl or csv", c.LoaderFormat)
+	}
+	switch internal.LoadPolicy(c.LoaderPolicy) {
+	case "", internal.LoadPolicyFail, internal.LoadPolicySkip, internal.LoadPolicyWarn:
+	default:
+		invalid("load_policy %q is not one of fail, skip or warn", c.LoaderPolicy)
+	}
+	if c.LoaderWatchInterval < 0 {
+		invalid("watch_interval %s must not be negative", c.LoaderWatchInterval)
+	}
+
+	// repository
+	switch c.RepositoryBackend {
+	case "", RepositoryBackendMap, RepositoryBackendSQLite:
+	default:
+		invalid("repository_backend %q is not one of map or sqlite", c.RepositoryBackend)
+	}
+
+	// average capacity
+	if c.AverageCapacityRounding != "" && !internal.RoundingMode(c.AverageCapacityRounding).Valid() {
+		invalid("average_capacity_rounding %q is not one of none, half_up, half_even, down or up", c.AverageCapacityRounding)
+	}
+	if c.AverageCapacityPlaces < 0 {
+		invalid("average_capacity_places %d must not be negative", c.AverageCapacityPlaces)
+	}
+	if c.AverageCapacityFormat != "" && !handler.AverageCapacityFormat(c.AverageCapacityFormat).Valid() {
+		invalid("average_capacity_format %q is not one of float or integer", c.AverageCapacityFormat)
+	}
+
+	// validator
+	countries := make([]string, 0, len(c.ValidatorRegistrationFormats))
+	for country := range c.ValidatorRegistrationFormats {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	for _, country := range countries {
+		if _, e := regexp.Compile(c.ValidatorRegistrationFormats[country]); e != nil {
+			invalid("registration_formats of %s: %s", country, e.Error())
+		}
+	}
+	yearMin, yearMax := c.ValidatorYearMin, c.ValidatorYearMax
+	if yearMin == 0 {
+		yearMin = defaults.ValidatorYearMin
+	}
+	if yearMax == 0 {
+		yearMax = defaults.ValidatorYearMax
+	}
+	switch {
+	case yearMin < 0 || yearMax < 0:
+		invalid("year_min %d and year_max %d must not be negative", yearMin, yearMax)
+	case yearMin > yearMax:
+		invalid("year_min %d is greater than year_max %d", yearMin, yearMax)
+	}
+
+	err = errors.Join(errs...)
+	return
+}
+
+// readConfigFile is a function that reads the values of a config file by key, as decoded from the file
+// - unknown keys are an error, so that a misspelled key is not silently ignored
+func readConfigFile(path string) (values map[string]any, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("%w: config file: %s", ErrApplicationInvalidConfig, err.Error())
+		return
+	}
+
+	var doc map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &doc)
+	case ".toml":
+		err = toml.Unmarshal(b, &doc)
+	case ".json":
+		err = json.Unmarshal(b, &doc)
+	default:
+		err = fmt.Errorf("unknown extension %q, expected .yaml, .yml, .toml or .json", ext)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: config file %s: %s", ErrApplicationInvalidConfig, path, err.Error())
+		return
+	}
+
+	known := make(map[string]bool, len(configSettings))
+	for _, s := range configSettings {
+		known[s.key] = true
+	}
+	for key := range doc {
+		if !known[key] {
+			err = fmt.Errorf("%w: config file %s: unknown key %s", ErrApplicationInvalidConfig, path, key)
+			return
+		}
+	}
+	values = doc
+	return
+}
+
+// configFileScalar is a function that returns a single value decoded from a config file as it would be given in an environment variable
+func configFileScalar(v any) (s string, err error) {
+	switch v := v.(type) {
+	case nil:
+		return
+	case string:
+		s = v
+	case bool, int, int64, uint64, float64:
+		s = fmt.Sprint(v)
+	default:
+		err = fmt.Errorf("%v is not a single value", v)
+	}
+	return
+}
+
+// stringSetting is a function that returns a setting of a text value
+func stringSetting(key, usage string, field func(c *ConfigApplicationDefault) *string) configSetting {
+	return configSetting{
+		key:   key,
+		usage: usage,
+		set: func(c *ConfigApplicationDefault, value string) (err error) {
+			*field(c) = strings.TrimSpace(value)
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any { return *field(c) },
+	}
+}
+
+// intSetting is a function that returns a setting of an integer value
+func intSetting(key, usage string, field func(c *ConfigApplicationDefault) *int) configSetting {
+	return configSetting{
+		key:   key,
+		usage: usage,
+		set: func(c *ConfigApplicationDefault, value string) (err error) {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				err = fmt.Errorf("%q is not an integer", value)
+				return
+			}
+			*field(c) = n
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any { return *field(c) },
+	}
+}
+
+// boolSetting is a function that returns a setting of a boolean value
+func boolSetting(key, usage string, field func(c *ConfigApplicationDefault) *bool) configSetting {
+	return configSetting{
+		key:    key,
+		usage:  usage,
+		isBool: true,
+		set: func(c *ConfigApplicationDefault, value string) (err error) {
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				err = fmt.Errorf("%q is not a boolean", value)
+				return
+			}
+			*field(c) = b
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any { return *field(c) },
+	}
+}
+
+// durationSetting is a function that returns a setting of a duration value, e.g. 5s
+func durationSetting(key, usage string, field func(c *ConfigApplicationDefault) *time.Duration) configSetting {
+	return configSetting{
+		key:   key,
+		usage: usage,
+		set: func(c *ConfigApplicationDefault, value string) (err error) {
+			d, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil {
+				err = fmt.Errorf("%q is not a duration", value)
+				return
+			}
+			*field(c) = d
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any { return field(c).String() },
+	}
+}
+
+// listSetting is a function that returns a setting of a list of texts separated by commas
+// - a config file gives it as a list, whose items can hold commas, or as a text separated by commas
+func listSetting(key, usage string, field func(c *ConfigApplicationDefault) *[]string) configSetting {
+	set := func(c *ConfigApplicationDefault, value string) (err error) {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return
+	}
+
+	return configSetting{
+		key:   key,
+		usage: usage,
+		set:   set,
+		setFile: func(c *ConfigApplicationDefault, value any) (err error) {
+			list, ok := value.([]any)
+			if !ok {
+				var str string
+				if str, err = configFileScalar(value); err == nil {
+					err = set(c, str)
+				}
+				return
+			}
+
+			var items []string
+			for _, v := range list {
+				item, e := configFileScalar(v)
+				if e != nil {
+					err = e
+					return
+				}
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*field(c) = items
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any {
+			if *field(c) == nil {
+				return []string{}
+			}
+			return *field(c)
+		},
+	}
+}
+
+// mapSetting is a function that returns a setting of key=value pairs separated by semicolons
+// - a config file gives it as a map, whose values can hold semicolons, or as a text of pairs
+func mapSetting(key, usage string, field func(c *ConfigApplicationDefault) *map[string]string) configSetting {
+	set := func(c *ConfigApplicationDefault, value string) (err error) {
+		pairs := make(map[string]string)
+		for _, pair := range strings.Split(value, ";") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				err = fmt.Errorf("%q is not a key=value pair", pair)
+				return
+			}
+			pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		*field(c) = pairs
+		return
+	}
+
+	return configSetting{
+		key:   key,
+		usage: usage,
+		set:   set,
+		setFile: func(c *ConfigApplicationDefault, value any) (err error) {
+			m, ok := value.(map[string]any)
+			if !ok {
+				var str string
+				if str, err = configFileScalar(value); err == nil {
+					err = set(c, str)
+				}
+				return
+			}
+
+			pairs := make(map[string]string, len(m))
+			for k, v := range m {
+				str, ok := v.(string)
+				if !ok {
+					err = fmt.Errorf("value of %s is not a string", k)
+					return
+				}
+				if strings.TrimSpace(k) == "" {
+					err = fmt.Errorf("%q is not a key", k)
+					return
+				}
+				pairs[strings.TrimSpace(k)] = strings.TrimSpace(str)
+			}
+			*field(c) = pairs
+			return
+		},
+		get: func(c *ConfigApplicationDefault) any {
+			if *field(c) == nil {
+				return map[string]string{}
+			}
+			return *field(c)
+		},
+	}
+}
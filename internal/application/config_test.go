@@ -0,0 +1,205 @@
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// LoadConfigTestCase is a struct that represents a test case for LoadConfig
+type LoadConfigTestCase struct {
+	name            string
+	files           map[string]string
+	args            []string
+	env             map[string]string
+	expectedConfig  func(cfg *ConfigApplicationDefault)
+	expectedOrigins map[string]string
+	expectedError   error
+	expectedText    string
+}
+
+// TestLoadConfig is a function that tests LoadConfig
+func TestLoadConfig(t *testing.T) {
+	// Create the test cases
+	testCases := []LoadConfigTestCase{
+		{
+			// This test evaluates that LoadConfig returns the defaults when no source sets a value
+			name: "should return the defaults when no source sets a value",
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+			},
+			expectedOrigins: map[string]string{"addr": "default", "data_path": "default"},
+		},
+		{
+			// This test evaluates that LoadConfig reads a YAML file, overridden by the environment, overridden by the flags
+			name: "should read a YAML file, overridden by the environment, overridden by the flags",
+			files: map[string]string{"config.yaml": "addr: :7070\ndata_path: a.json\nyear_min: 1900\n" +
+				"fuel_types: [Gas, Diesel]\nregistration_formats:\n  AR: ^[A-Z]{3}$\n"},
+			args: []string{"-config", "config.yaml", "-year-min", "1950", "-normalized-match"},
+			env:  map[string]string{"VEHICLES_DATA_PATH": "b.json", "VEHICLES_YEAR_MIN": "1920", "VEHICLES_ADDR": ""},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.ServerAddress = ":7070"
+				cfg.LoaderFilePath = "b.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+				cfg.ValidatorYearMin = 1950
+				cfg.RepositoryNormalizedMatch = true
+				cfg.ValidatorFuelTypes = []string{"Gas", "Diesel"}
+				cfg.ValidatorRegistrationFormats = map[string]string{"AR": "^[A-Z]{3}$"}
+			},
+			expectedOrigins: map[string]string{
+				"addr":      "file config.yaml",
+				"data_path": "env VEHICLES_DATA_PATH",
+				"year_min":  "flag -year-min",
+			},
+		},
+		{
+			// This test evaluates that LoadConfig reads a TOML file named by the environment
+			name:  "should read a TOML file named by the environment",
+			files: map[string]string{"config.toml": "watch_interval = \"0s\"\naverage_capacity_places = 2\nduplicate_registrations = true\n"},
+			env:   map[string]string{"VEHICLES_CONFIG": "config.toml"},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.AverageCapacityPlaces = 2
+				cfg.ValidatorDuplicateRegistrations = true
+			},
+			expectedOrigins: map[string]string{"watch_interval": "file config.toml"},
+		},
+		{
+			// This test evaluates that LoadConfig reads a JSON file
+			name:  "should read a JSON file",
+			files: map[string]string{"config.json": `{"repository_backend":"sqlite","sqlite_path":"x.db","transmissions":["manual"]}`},
+			args:  []string{"-config", "config.json"},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+				cfg.RepositoryBackend = RepositoryBackendSQLite
+				cfg.RepositorySQLitePath = "x.db"
+				cfg.ValidatorTransmissions = []string{"manual"}
+			},
+		},
+		{
+			// This test evaluates that LoadConfig keeps the separators held by the lists and maps of a config file
+			name: "should keep the separators held by the lists and maps of a config file",
+			files: map[string]string{"config.yaml": "fuel_types: [\"Gas, compressed\", Diesel]\n" +
+				"registration_formats:\n  AR: \"^[A-Z]{3}(;|-)[0-9]{3}$\"\n  BR: \"^[A-Z]{3}=[0-9]{4}$\"\n"},
+			args: []string{"-config", "config.yaml"},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+				cfg.ValidatorFuelTypes = []string{"Gas, compressed", "Diesel"}
+				cfg.ValidatorRegistrationFormats = map[string]string{"AR": "^[A-Z]{3}(;|-)[0-9]{3}$", "BR": "^[A-Z]{3}=[0-9]{4}$"}
+			},
+			expectedOrigins: map[string]string{"fuel_types": "file config.yaml", "registration_formats": "file config.yaml"},
+		},
+		{
+			// This test evaluates that LoadConfig reads the tables of a TOML file as maps
+			name:  "should read the tables of a TOML file as maps",
+			files: map[string]string{"config.toml": "[registration_formats]\nAR = '^[A-Z]{3};[0-9]{3}$'\n"},
+			args:  []string{"-config", "config.toml"},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+				cfg.ValidatorRegistrationFormats = map[string]string{"AR": "^[A-Z]{3};[0-9]{3}$"}
+			},
+		},
+		{
+			// This test evaluates that LoadConfig rejects a value of a map of the config file that is not a text
+			name:          "should reject a value of a map of the config file that is not a text",
+			files:         map[string]string{"config.json": `{"registration_formats":{"AR":3}}`},
+			args:          []string{"-config", "config.json"},
+			expectedError: ErrApplicationInvalidConfig,
+			expectedText:  "application: invalid config: file config.json: registration_formats: value of AR is not a string",
+		},
+		{
+			// This test evaluates that LoadConfig rejects an unknown key of the config file
+			name:          "should reject an unknown key of the config file",
+			files:         map[string]string{"config.yaml": "adress: :7070\n"},
+			args:          []string{"-config", "config.yaml"},
+			expectedError: ErrApplicationInvalidConfig,
+			expectedText:  "application: invalid config: config file config.yaml: unknown key adress",
+		},
+		{
+			// This test evaluates that LoadConfig rejects a value that can not be parsed, naming its source
+			name:          "should reject a value that can not be parsed, naming its source",
+			env:           map[string]string{"VEHICLES_WATCH_INTERVAL": "often"},
+			expectedError: ErrApplicationInvalidConfig,
+			expectedText:  `application: invalid config: env VEHICLES_WATCH_INTERVAL: "often" is not a duration`,
+		},
+		{
+			// This test evaluates that LoadConfig accepts an address with a named port
+			name: "should accept an address with a named port",
+			args: []string{"-addr", ":http"},
+			expectedConfig: func(cfg *ConfigApplicationDefault) {
+				cfg.ServerAddress = ":http"
+				cfg.LoaderFilePath = "docs/db/vehicles_100.json"
+				cfg.LoaderWatchInterval = 5 * time.Second
+			},
+		},
+		{
+			// This test evaluates that LoadConfig rejects a port that is neither a number nor a known service
+			name:          "should reject a port that is neither a number nor a known service",
+			args:          []string{"-addr", ":no-such-service"},
+			expectedError: ErrApplicationInvalidConfig,
+			expectedText:  "application: invalid config: addr \":no-such-service\" is not a host:port address",
+		},
+		{
+			// This test evaluates that LoadConfig returns every invalid value of the resulting configuration
+			name:          "should return every invalid value of the resulting configuration",
+			args:          []string{"-addr", "localhost", "-load-policy", "ignore", "-year-min", "2100", "-registration-formats", "AR=[A-Z"},
+			expectedError: ErrApplicationInvalidConfig,
+			expectedText: "application: invalid config: addr \"localhost\" is not a host:port address\n" +
+				"application: invalid config: load_policy \"ignore\" is not one of fail, skip or warn\n" +
+				"application: invalid config: registration_formats of AR: error parsing regexp: missing closing ]: `[A-Z`\n" +
+				"application: invalid config: year_min 2100 is greater than year_max " + time.Now().AddDate(1, 0, 0).Format("2006"),
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			// - the files are written to a temporary directory, their names are replaced by their paths
+			dir := t.TempDir()
+			resolve := func(s string) string {
+				for name := range testCase.files {
+					s = strings.ReplaceAll(s, name, filepath.Join(dir, name))
+				}
+				return s
+			}
+			for name, content := range testCase.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			}
+			args := make([]string, 0, len(testCase.args))
+			for _, arg := range testCase.args {
+				args = append(args, resolve(arg))
+			}
+			lookupEnv := func(key string) (value string, ok bool) {
+				value, ok = testCase.env[key]
+				return resolve(value), ok
+			}
+
+			// Act
+			cs, err := LoadConfig(args, lookupEnv)
+
+			// Assert
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+				assert.EqualError(t, err, resolve(testCase.expectedText))
+				return
+			}
+			require.NoError(t, err)
+			expectedConfig := DefaultConfigApplicationDefault()
+			testCase.expectedConfig(expectedConfig)
+			assert.Equal(t, *expectedConfig, cs.Config)
+			for key, origin := range testCase.expectedOrigins {
+				assert.Equal(t, resolve(origin), cs.Origins[key], key)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerAdmin is a struct with methods that represent handlers for administrative tasks
+type HandlerAdmin struct {
+	// rl is the reloader of the vehicles dataset, nil if the dataset can not be reloaded from its file
+	rl internal.ReloaderVehicle
+	// rpt is the reporter of the last load, nil if the loader does not validate
+	rpt internal.LoaderReporter
+}
+
+// NewHandlerAdmin is a function that returns a new instance of HandlerAdmin
+func NewHandlerAdmin(rl internal.ReloaderVehicle, rpt internal.LoaderReporter) *HandlerAdmin {
+	return &HandlerAdmin{rl: rl, rpt: rpt}
+}
+
+// LoadIssueJSON is a struct that represents a load issue in JSON format
+type LoadIssueJSON struct {
+	Index   int    `json:"index"`
+	Id      int    `json:"id"`
+	Kind    string `json:"kind"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// LoadReportJSON is a struct that represents a load report in JSON format
+type LoadReportJSON struct {
+	Policy  string          `json:"policy"`
+	Total   int             `json:"total"`
+	Loaded  int             `json:"loaded"`
+	Skipped int             `json:"skipped"`
+	Counts  map[string]int  `json:"counts"`
+	Dropped int             `json:"dropped"`
+	Issues  []LoadIssueJSON `json:"issues"`
+}
+
+// Reload returns a handler that reloads the vehicles dataset from its file
+// - 409 conflict if vehicles were written since the last load, unless the query parameter force is true
+// - 409 conflict if the dataset can not be reloaded, e.g. it is stored in a database
+func (h *HandlerAdmin) Reload() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// check if there is a reloader
+		if h.rl == nil {
+			response.ProblemGin(ctx, response.Problem{Status: http.StatusConflict, Code: "reload_not_available", Detail: "reload not available: the vehicles are not reloaded from the file by this backend"})
+			return
+		}
+
+		// request
+		force := false
+		if f := ctx.Query("force"); f != "" {
+			var err error
+			force, err = strconv.ParseBool(f)
+			if err != nil {
+				requestErrorGin(ctx, "invalid force: must be a boolean")
+				return
+			}
+		}
+
+		// process
+		r, err := h.rl.Reload(force)
+		if errors.Is(err, internal.ErrRepositoryWritten) {
+			response.ProblemGin(ctx, response.Problem{Status: http.StatusConflict, Code: "reload_conflict", Detail: "reload conflict: vehicles were written since the last load, reload with force=true to discard them"})
+			return
+		}
+		if err != nil {
+			response.ProblemGin(ctx, response.Problem{Status: http.StatusInternalServerError, Code: "reload_failed", Detail: fmt.Sprintf("reload failed: %s", err.Error())})
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "vehicles reloaded",
+			"data": map[string]any{
+				"added":   r.Added,
+				"changed": r.Changed,
+				"removed": r.Removed,
+			},
+		})
+	}
+}
+
+// LoadReport returns a handler that returns the validation report of the last load
+// - the issues can be filtered by kind with the query parameter kind
+// - only the first internal.LoadReportMaxIssues issues are listed, counts include every issue and dropped the ones not listed
+func (h *HandlerAdmin) LoadReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// check if there is a reporter
+		if h.rpt == nil {
+			response.ProblemGin(ctx, response.Problem{Status: http.StatusNotFound, Code: "load_report_not_available", Detail: "load report not available"})
+			return
+		}
+
+		// request
+		kind := ctx.Query("kind")
+
+		// process
+		r := h.rpt.Report()
+
+		// response
+		body := LoadReportJSON{
+			Policy:  string(r.Policy),
+			Total:   r.Total,
+			Loaded:  r.Loaded,
+			Skipped: r.Skipped,
+			Counts:  make(map[string]int, len(r.Counts)),
+			Dropped: r.Dropped,
+			Issues:  make([]LoadIssueJSON, 0),
+		}
+		for k, n := range r.Counts {
+			body.Counts[string(k)] = n
+		}
+		for _, issue := range r.Issues {
+			if kind != "" && kind != string(issue.Kind) {
+				continue
+			}
+			body.Issues = append(body.Issues, LoadIssueJSON{
+				Index:   issue.Index,
+				Id:      issue.Id,
+				Kind:    string(issue.Kind),
+				Field:   issue.Field,
+				Message: issue.Message,
+			})
+		}
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "load report found",
+			"data":    body,
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/service"
+	"app/platform/web/response"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// ReloadTestCase is a struct that represents a test case for Reload
+type ReloadTestCase struct {
+	server         *gin.Engine
+	mockReloader   *service.MockReloader
+	name           string
+	noReloader     bool
+	query          string
+	expectedForce  bool
+	returnedReport internal.ReloadReport
+	reloaderError  error
+	handlerError   error
+	httpSetup      *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *ReloadTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.mockReloader = &service.MockReloader{}
+	var rl internal.ReloaderVehicle = tc.mockReloader
+	if tc.noReloader {
+		rl = nil
+	}
+	tc.server.POST("/admin/reload", NewHandlerAdmin(rl, nil).Reload())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": "vehicles reloaded",
+			"data": map[string]interface{}{
+				"added":   tc.returnedReport.Added,
+				"changed": tc.returnedReport.Changed,
+				"removed": tc.returnedReport.Removed,
+			},
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.mockReloader.On("Reload", tc.expectedForce).Return(tc.returnedReport, tc.reloaderError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodPost, "/admin/reload"+tc.query, nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *ReloadTestCase) Assert(t *testing.T) {
+	if tc.noReloader || tc.httpSetup.expectedErrorCode == CodeInvalidRequest {
+		tc.mockReloader.AssertNotCalled(t, "Reload", tc.expectedForce)
+	} else {
+		tc.mockReloader.AssertCalled(t, "Reload", tc.expectedForce)
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Reload is a method that tests the Reload handler
+func TestHandler_Reload(t *testing.T) {
+	// Create test cases
+	testCases := []ReloadTestCase{
+		{
+			// This test evaluates that Reload returns 200 ok and the reload report
+			name:           "should return 200 ok and the reload report",
+			returnedReport: internal.ReloadReport{Added: 2, Changed: 1, Removed: 3},
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Reload returns 500 internal server error with the loader error
+			name:          "should return 500 internal server error with the loader error",
+			reloaderError: errors.New("unexpected EOF"),
+			handlerError:  errors.New("reload failed: unexpected EOF"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "reload_failed",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			// This test evaluates that Reload returns 409 conflict when vehicles were written since the last load
+			name:          "should return 409 conflict when vehicles were written since the last load",
+			reloaderError: internal.ErrRepositoryWritten,
+			handlerError:  errors.New("reload conflict: vehicles were written since the last load, reload with force=true to discard them"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "reload_conflict",
+				expectedStatusCode: http.StatusConflict,
+			},
+		},
+		{
+			// This test evaluates that Reload forces the reload when the query parameter force is true
+			name:           "should force the reload when force is true",
+			query:          "?force=true",
+			expectedForce:  true,
+			returnedReport: internal.ReloadReport{Changed: 1},
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Reload returns 400 bad request when force is not a boolean
+			name:         "should return 400 bad request when force is not a boolean",
+			query:        "?force=yes",
+			handlerError: errors.New("invalid force: must be a boolean"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  CodeInvalidRequest,
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Reload returns 409 conflict when there is no reloader
+			name:         "should return 409 conflict when there is no reloader",
+			noReloader:   true,
+			handlerError: errors.New("reload not available: the vehicles are not reloaded from the file by this backend"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "reload_not_available",
+				expectedStatusCode: http.StatusConflict,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// LoadReportTestCase is a struct that represents a test case for LoadReport
+type LoadReportTestCase struct {
+	server         *gin.Engine
+	mockLoader     *loader.MockLoader
+	name           string
+	noReporter     bool
+	kind           string
+	returnedReport internal.LoadReport
+	expectedBody   string
+	httpSetup      *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *LoadReportTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.mockLoader = &loader.MockLoader{}
+	var rpt internal.LoaderReporter = tc.mockLoader
+	if tc.noReporter {
+		rpt = nil
+	}
+	tc.server.GET("/admin/load-report", NewHandlerAdmin(&service.MockReloader{}, rpt).LoadReport())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	if tc.noReporter {
+		tc.httpSetup.expectedHeaders = http.Header{
+			"Content-Type": []string{response.ContentTypeProblem},
+		}
+	}
+	tc.mockLoader.On("Report").Return(tc.returnedReport)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, "/admin/load-report?kind="+tc.kind, nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *LoadReportTestCase) Assert(t *testing.T) {
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, tc.expectedBody, tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_LoadReport is a method that tests the LoadReport handler
+func TestHandler_LoadReport(t *testing.T) {
+	report := internal.LoadReport{
+		Policy: internal.LoadPolicyWarn,
+		Total:  2,
+		Loaded: 2,
+	}
+	report.AddIssues(
+		internal.LoadIssue{Index: 0, Id: 1, Kind: internal.LoadIssueMissingField, Field: "length", Message: "required field is missing"},
+		internal.LoadIssue{Index: 1, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "id", Message: "id 1 already loaded"},
+	)
+	truncated := internal.LoadReport{
+		Policy:  internal.LoadPolicyWarn,
+		Total:   3000,
+		Loaded:  3000,
+		Issues:  []internal.LoadIssue{{Index: 1, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "id", Message: "id 1 already loaded"}},
+		Dropped: 1500,
+		Counts:  map[internal.LoadIssueKind]int{internal.LoadIssueDuplicate: 1501},
+	}
+
+	// Create test cases
+	testCases := []LoadReportTestCase{
+		{
+			// This test evaluates that LoadReport returns 200 ok and the whole report
+			name:           "should return 200 ok and the whole report",
+			returnedReport: report,
+			expectedBody: `{"message":"load report found","data":{"policy":"warn","total":2,"loaded":2,"skipped":0,
+				"counts":{"missing_field":1,"duplicate":1},"dropped":0,
+				"issues":[
+					{"index":0,"id":1,"kind":"missing_field","field":"length","message":"required field is missing"},
+					{"index":1,"id":1,"kind":"duplicate","field":"id","message":"id 1 already loaded"}
+				]}}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that LoadReport returns 200 ok and only the issues of the requested kind
+			name:           "should return 200 ok and only the issues of the requested kind",
+			kind:           "duplicate",
+			returnedReport: report,
+			expectedBody: `{"message":"load report found","data":{"policy":"warn","total":2,"loaded":2,"skipped":0,
+				"counts":{"missing_field":1,"duplicate":1},"dropped":0,
+				"issues":[
+					{"index":1,"id":1,"kind":"duplicate","field":"id","message":"id 1 already loaded"}
+				]}}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that LoadReport counts the issues the report dropped
+			name:           "should return 200 ok and count the issues the report dropped",
+			returnedReport: truncated,
+			expectedBody: `{"message":"load report found","data":{"policy":"warn","total":3000,"loaded":3000,"skipped":0,
+				"counts":{"duplicate":1501},"dropped":1500,
+				"issues":[
+					{"index":1,"id":1,"kind":"duplicate","field":"id","message":"id 1 already loaded"}
+				]}}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that LoadReport returns 404 not found error when the loader does not report
+			name:         "should return 404 not found error when the loader does not report",
+			noReporter:   true,
+			expectedBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"load report not available","code":"load_report_not_available"}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
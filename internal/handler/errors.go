@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/request"
+	"app/platform/web/response"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CodeInvalidRequest is the code of a request with an invalid parameter or body
+	CodeInvalidRequest = "invalid_request"
+	// CodeInternalError is the code of an error with no mapping
+	CodeInternalError = "internal_error"
+)
+
+// ErrorMapping is a struct that represents how a domain error is answered
+type ErrorMapping struct {
+	// Status is the HTTP status code
+	Status int
+	// Code is the machine readable code of the error
+	Code string
+	// Message is the human readable message of the error
+	Message string
+}
+
+// errorMappings are the mappings of the domain sentinels, checked in order
+var errorMappings = []struct {
+	err     error
+	mapping ErrorMapping
+}{
+	{err: internal.ErrRepositoryNotFound, mapping: ErrorMapping{Status: http.StatusNotFound, Code: "vehicle_not_found", Message: "vehicle not found"}},
+	{err: internal.ErrServiceNoVehicles, mapping: ErrorMapping{Status: http.StatusNotFound, Code: "vehicles_not_found", Message: "vehicles not found"}},
+	{err: internal.ErrValidatorInvalidVehicle, mapping: ErrorMapping{Status: http.StatusUnprocessableEntity, Code: "invalid_vehicle", Message: "invalid vehicle"}},
+	{err: internal.ErrServiceInvalidFind, mapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_find", Message: "invalid find"}},
+	{err: internal.ErrRepositoryInvalidFind, mapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_find", Message: "invalid find"}},
+	{err: internal.ErrServiceInvalidSearch, mapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_search", Message: "invalid search"}},
+	{err: internal.ErrServiceInvalidStats, mapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_stats", Message: "invalid stats"}},
+	{err: internal.ErrServiceInvalidAggregate, mapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_aggregate", Message: "invalid aggregate"}},
+}
+
+// MapError is a function that returns the mapping of the first domain sentinel err wraps
+// - detail is the message of the mapping followed by the context err adds to the sentinel, if any
+// - an error with no mapping is an internal error, its text is never part of the detail
+func MapError(err error) (m ErrorMapping, detail string) {
+	for _, em := range errorMappings {
+		if !errors.Is(err, em.err) {
+			continue
+		}
+
+		m, detail = em.mapping, em.mapping.Message
+		if context, ok := strings.CutPrefix(err.Error(), em.err.Error()+": "); ok {
+			detail += ": " + context
+		}
+		return
+	}
+
+	m = ErrorMapping{Status: http.StatusInternalServerError, Code: CodeInternalError, Message: "internal error"}
+	detail = m.Message
+	return
+}
+
+// renameRangeError is a function that names the parameters of a *internal.RangeError after the ones of a route
+// - any other error is returned as is
+func renameRangeError(err error, names map[string]string) error {
+	var rerr *internal.RangeError
+	if !errors.As(err, &rerr) {
+		return err
+	}
+	return rerr.Rename(names)
+}
+
+// errorGin is a function that responds the problem details of a domain error
+// - the fields of a *internal.VehicleValidationError are listed in errors
+func errorGin(ctx *gin.Context, err error) {
+	m, detail := MapError(err)
+	p := response.Problem{Status: m.Status, Code: m.Code, Detail: detail}
+
+	var verr *internal.VehicleValidationError
+	if errors.As(err, &verr) {
+		// the fields are listed one by one, the detail is only the message
+		p.Detail = m.Message
+		fields := make([]FieldErrorJSON, 0, len(verr.Fields))
+		for _, f := range verr.Fields {
+			fields = append(fields, FieldErrorJSON{Field: f.Field, Rule: string(f.Rule), Message: f.Message})
+		}
+		p.Errors = fields
+	}
+	if m.Status == http.StatusInternalServerError {
+		log.Printf("%s %s (request id %q): %s", ctx.Request.Method, ctx.Request.URL.Path, request.IDFromGin(ctx), err.Error())
+	}
+
+	response.ProblemGin(ctx, p)
+}
+
+// requestErrorGin is a function that responds the problem details of an invalid parameter or body
+func requestErrorGin(ctx *gin.Context, detail string) {
+	response.ProblemGin(ctx, response.Problem{Status: http.StatusBadRequest, Code: CodeInvalidRequest, Detail: detail})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MapErrorTestCase is a struct that represents a test case for MapError
+type MapErrorTestCase struct {
+	name            string
+	err             error
+	expectedMapping ErrorMapping
+	expectedDetail  string
+}
+
+// Assert is a method that asserts the test case
+func (tc *MapErrorTestCase) Assert(t *testing.T) {
+	m, detail := MapError(tc.err)
+	assert.Equal(t, tc.expectedMapping, m, tc.name)
+	assert.Equal(t, tc.expectedDetail, detail, tc.name)
+}
+
+// TestMapError is a method that tests MapError
+func TestMapError(t *testing.T) {
+	// Create test cases
+	testCases := []MapErrorTestCase{
+		{
+			// This test evaluates that MapError maps a domain sentinel to its status, code and message
+			name:            "should map a domain sentinel to its status, code and message",
+			err:             internal.ErrRepositoryNotFound,
+			expectedMapping: ErrorMapping{Status: http.StatusNotFound, Code: "vehicle_not_found", Message: "vehicle not found"},
+			expectedDetail:  "vehicle not found",
+		},
+		{
+			// This test evaluates that MapError keeps the context a wrapped sentinel adds in the detail
+			name:            "should keep the context a wrapped sentinel adds in the detail",
+			err:             fmt.Errorf("%w: unknown field speed", internal.ErrServiceInvalidStats),
+			expectedMapping: ErrorMapping{Status: http.StatusBadRequest, Code: "invalid_stats", Message: "invalid stats"},
+			expectedDetail:  "invalid stats: unknown field speed",
+		},
+		{
+			// This test evaluates that MapError maps a validation error to 422 unprocessable entity
+			name:            "should map a validation error to 422 unprocessable entity",
+			err:             &internal.VehicleValidationError{Fields: []internal.VehicleFieldError{{Field: "year", Rule: internal.ValidationRuleRange, Message: "must be at least 1886"}}},
+			expectedMapping: ErrorMapping{Status: http.StatusUnprocessableEntity, Code: "invalid_vehicle", Message: "invalid vehicle"},
+			expectedDetail:  "invalid vehicle: year: must be at least 1886",
+		},
+		{
+			// This test evaluates that MapError maps an unknown error to 500 internal server error without its text
+			name:            "should map an unknown error to 500 internal server error without its text",
+			err:             errors.New("database is locked"),
+			expectedMapping: ErrorMapping{Status: http.StatusInternalServerError, Code: CodeInternalError, Message: "internal error"},
+			expectedDetail:  "internal error",
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Assert(t)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/buildinfo"
+	"app/platform/web/response"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessTimeout bounds each readiness check, so that a dependency that hangs reports not ready instead of the probe timing out
+const ReadinessTimeout = 2 * time.Second
+
+// NewHandlerHealth is a function that returns a new instance of HandlerHealth
+// - checks are run in order by Ready, all of them must pass for the application to be ready
+// - ds describes the dataset reported by Version
+func NewHandlerHealth(checks []internal.ReadinessCheck, ds internal.DatasetVehicle, build buildinfo.Info) *HandlerHealth {
+	return &HandlerHealth{checks: checks, ds: ds, build: build}
+}
+
+// HandlerHealth is a struct with methods that represent handlers for the health of the application
+type HandlerHealth struct {
+	// checks are the readiness checks of the dependencies
+	checks []internal.ReadinessCheck
+	// ds is the describer of the vehicles dataset
+	ds internal.DatasetVehicle
+	// build is the build of the running binary
+	build buildinfo.Info
+}
+
+// ReadinessCheckJSON is a struct that represents the result of a readiness check in JSON format
+type ReadinessCheckJSON struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessJSON is a struct that represents the readiness of the application in JSON format
+type ReadinessJSON struct {
+	Status string               `json:"status"`
+	Checks []ReadinessCheckJSON `json:"checks"`
+}
+
+// VersionJSON is a struct that represents the build and the dataset of the application in JSON format
+type VersionJSON struct {
+	Commit          string `json:"commit"`
+	BuildTime       string `json:"build_time"`
+	Modified        bool   `json:"modified"`
+	GoVersion       string `json:"go_version"`
+	DatasetChecksum string `json:"dataset_checksum"`
+	VehicleCount    int    `json:"vehicle_count"`
+}
+
+// Health returns a handler that reports that the process is alive
+// - it checks no dependency, so that a slow dependency does not get the process restarted
+func (h *HandlerHealth) Health() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// response
+		response.JSONGin(ctx, http.StatusOK, map[string]any{"status": "ok"})
+	}
+}
+
+// Ready returns a handler that reports whether the application can take requests
+// - 503 service unavailable if any check fails, so that load balancers stop routing to it
+// - every check is run and listed, each one bounded by ReadinessTimeout
+func (h *HandlerHealth) Ready() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// process
+		code := http.StatusOK
+		body := ReadinessJSON{Status: "ready", Checks: make([]ReadinessCheckJSON, 0, len(h.checks))}
+		for _, c := range h.checks {
+			cj := ReadinessCheckJSON{Name: c.Name, Status: "ok"}
+			if err := ready(ctx.Request.Context(), c.Checker); err != nil {
+				cj.Status = "fail"
+				cj.Error = err.Error()
+				code = http.StatusServiceUnavailable
+				body.Status = "not_ready"
+			}
+			body.Checks = append(body.Checks, cj)
+		}
+
+		// response
+		response.JSONGin(ctx, code, body)
+	}
+}
+
+// Version returns a handler that reports the build of the binary and the dataset being served
+func (h *HandlerHealth) Version() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// process
+		d, err := h.ds.Info()
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, VersionJSON{
+			Commit:          h.build.Commit,
+			BuildTime:       h.build.Time,
+			Modified:        h.build.Modified,
+			GoVersion:       h.build.GoVersion,
+			DatasetChecksum: d.Checksum,
+			VehicleCount:    d.Count,
+		})
+	}
+}
+
+// ready is a function that runs a readiness check bounded by ReadinessTimeout
+func ready(ctx context.Context, c internal.ReadinessChecker) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, ReadinessTimeout)
+	defer cancel()
+
+	err = c.Ready(ctx)
+	return
+}
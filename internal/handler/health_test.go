@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"app/platform/buildinfo"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHandler_Health is a method that tests the Health handler
+func TestHandler_Health(t *testing.T) {
+	// This test evaluates that Health returns 200 ok without running any readiness check
+	t.Run("should return 200 ok without running any check", func(t *testing.T) {
+		// Arrange
+		checked := false
+		checks := []internal.ReadinessCheck{{Name: "repository", Checker: internal.ReadinessFunc(func(ctx context.Context) error {
+			checked = true
+			return internal.ErrNotReady
+		})}}
+		server := gin.New()
+		server.GET("/healthz", NewHandlerHealth(checks, nil, buildinfo.Info{}).Health())
+		res := httptest.NewRecorder()
+
+		// Act
+		server.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.JSONEq(t, `{"status":"ok"}`, res.Body.String())
+		assert.False(t, checked)
+	})
+}
+
+// ReadyTestCase is a struct that represents a test case for Ready
+type ReadyTestCase struct {
+	server       *gin.Engine
+	name         string
+	checks       []internal.ReadinessCheck
+	expectedBody string
+	httpSetup    *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *ReadyTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.server.GET("/readyz", NewHandlerHealth(tc.checks, nil, buildinfo.Info{}).Ready())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *ReadyTestCase) Assert(t *testing.T) {
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code, tc.name)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header(), tc.name)
+	assert.JSONEq(t, tc.expectedBody, tc.httpSetup.res.Body.String(), tc.name)
+}
+
+// TestHandler_Ready is a method that tests the Ready handler
+func TestHandler_Ready(t *testing.T) {
+	ok := internal.ReadinessFunc(func(ctx context.Context) error { return nil })
+	draining := internal.ReadinessFunc(func(ctx context.Context) error {
+		return fmt.Errorf("%w: draining", internal.ErrNotReady)
+	})
+	deadline := internal.ReadinessFunc(func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("no deadline")
+		}
+		return nil
+	})
+
+	// Create test cases
+	testCases := []ReadyTestCase{
+		{
+			// This test evaluates that Ready returns 200 ok when every check passes
+			name:         "should return 200 ok when every check passes",
+			checks:       []internal.ReadinessCheck{{Name: "loader", Checker: ok}, {Name: "repository", Checker: ok}},
+			expectedBody: `{"status":"ready","checks":[{"name":"loader","status":"ok"},{"name":"repository","status":"ok"}]}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Ready returns 200 ok when there are no checks
+			name:         "should return 200 ok when there are no checks",
+			expectedBody: `{"status":"ready","checks":[]}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Ready returns 503 service unavailable and runs every check when one fails
+			name:         "should return 503 service unavailable when a check fails",
+			checks:       []internal.ReadinessCheck{{Name: "server", Checker: draining}, {Name: "repository", Checker: ok}},
+			expectedBody: `{"status":"not_ready","checks":[{"name":"server","status":"fail","error":"not ready: draining"},{"name":"repository","status":"ok"}]}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusServiceUnavailable,
+			},
+		},
+		{
+			// This test evaluates that Ready bounds every check with a deadline
+			name:         "should bound every check with a deadline",
+			checks:       []internal.ReadinessCheck{{Name: "repository", Checker: deadline}},
+			expectedBody: `{"status":"ready","checks":[{"name":"repository","status":"ok"}]}`,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// VersionTestCase is a struct that represents a test case for Version
+type VersionTestCase struct {
+	server       *gin.Engine
+	mockDataset  *service.MockDataset
+	name         string
+	build        buildinfo.Info
+	returnedInfo internal.DatasetInfo
+	datasetError error
+	httpSetup    *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *VersionTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.mockDataset = &service.MockDataset{}
+	tc.server.GET("/version", NewHandlerHealth(nil, tc.mockDataset, tc.build).Version())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"commit":           tc.build.Commit,
+			"build_time":       tc.build.Time,
+			"modified":         tc.build.Modified,
+			"go_version":       tc.build.GoVersion,
+			"dataset_checksum": tc.returnedInfo.Checksum,
+			"vehicle_count":    tc.returnedInfo.Count,
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, "internal error")
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.mockDataset.On("Info").Return(tc.returnedInfo, tc.datasetError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, "/version", nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *VersionTestCase) Assert(t *testing.T) {
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code, tc.name)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header(), tc.name)
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String(), tc.name)
+}
+
+// TestHandler_Version is a method that tests the Version handler
+func TestHandler_Version(t *testing.T) {
+	// Create test cases
+	testCases := []VersionTestCase{
+		{
+			// This test evaluates that Version returns the build and the dataset
+			name:         "should return the build and the dataset",
+			build:        buildinfo.Info{Commit: "a1c2108", Time: "2026-10-17T10:00:00Z", GoVersion: "go1.21.2"},
+			returnedInfo: internal.DatasetInfo{Checksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Count: 100},
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Version returns 500 internal server error when the dataset can not be described
+			name:         "should return 500 internal server error when the dataset can not be described",
+			datasetError: errors.New("database is locked"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedStatusCode: http.StatusInternalServerError,
+				expectedErrorCode:  CodeInternalError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
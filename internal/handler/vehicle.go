@@ -2,9 +2,11 @@ package handler
 
 import (
 	"app/internal"
+	"app/platform/web/request"
 	"app/platform/web/response"
-	"errors"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -14,67 +16,313 @@ import (
 type HandlerVehicle struct {
 	// sv is the service that will be used by the handler
 	sv internal.ServiceVehicle
+	// capacityRounding is the rounding applied to the average capacity
+	capacityRounding internal.Rounding
+	// capacityFormat is the format of the average capacity when the request does not choose one
+	capacityFormat AverageCapacityFormat
+}
+
+// ConfigHandlerVehicle is a struct that represents the configuration for HandlerVehicle
+type ConfigHandlerVehicle struct {
+	// CapacityRounding is the rounding applied to the average capacity
+	// - the zero value keeps the exact average
+	CapacityRounding internal.Rounding
+	// CapacityFormat is the format of the average capacity when the request does not choose one
+	// - empty means float
+	CapacityFormat AverageCapacityFormat
+}
+
+// AverageCapacityFormat is the format of the average capacity in the body of a response
+type AverageCapacityFormat string
+
+const (
+	// AverageCapacityFormatFloat is the rounded average along with the brand and the sample size
+	AverageCapacityFormatFloat AverageCapacityFormat = "float"
+	// AverageCapacityFormatInteger is the truncated average alone, for clients of the former response
+	AverageCapacityFormatInteger AverageCapacityFormat = "integer"
+)
+
+// Valid is a method that returns true if the format is known
+func (f AverageCapacityFormat) Valid() bool {
+	return f == AverageCapacityFormatFloat || f == AverageCapacityFormatInteger
+}
+
+// AverageCapacityResponseJSON is a struct that represents the average capacity of a brand in the body of a response
+type AverageCapacityResponseJSON struct {
+	Brand   string  `json:"brand"`
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// VehicleRequestJSON is a struct that represents the body of a request to create or replace a vehicle
+type VehicleRequestJSON struct {
+	Brand           string  `json:"brand"`
+	Model           string  `json:"model"`
+	Registration    string  `json:"registration"`
+	Color           string  `json:"color"`
+	FabricationYear int     `json:"year"`
+	Capacity        int     `json:"passengers"`
+	MaxSpeed        float64 `json:"max_speed"`
+	FuelType        string  `json:"fuel_type"`
+	Transmission    string  `json:"transmission"`
+	Weight          float64 `json:"weight"`
+	Height          float64 `json:"height"`
+	Length          float64 `json:"length"`
+	Width           float64 `json:"width"`
+}
+
+// VehicleAttributes is a method that returns the vehicle attributes of the request
+func (r VehicleRequestJSON) VehicleAttributes() internal.VehicleAttributes {
+	return internal.VehicleAttributes{
+		Brand:           r.Brand,
+		Model:           r.Model,
+		Registration:    r.Registration,
+		Color:           r.Color,
+		FabricationYear: r.FabricationYear,
+		Capacity:        r.Capacity,
+		MaxSpeed:        r.MaxSpeed,
+		FuelType:        r.FuelType,
+		Transmission:    r.Transmission,
+		Weight:          r.Weight,
+		Dimensions: internal.Dimensions{
+			Height: r.Height,
+			Length: r.Length,
+			Width:  r.Width,
+		},
+	}
+}
+
+// VehiclePatchRequestJSON is a struct that represents the body of a request to partially update a vehicle
+type VehiclePatchRequestJSON struct {
+	Brand           *string  `json:"brand"`
+	Model           *string  `json:"model"`
+	Registration    *string  `json:"registration"`
+	Color           *string  `json:"color"`
+	FabricationYear *int     `json:"year"`
+	Capacity        *int     `json:"passengers"`
+	MaxSpeed        *float64 `json:"max_speed"`
+	FuelType        *string  `json:"fuel_type"`
+	Transmission    *string  `json:"transmission"`
+	Weight          *float64 `json:"weight"`
+	Height          *float64 `json:"height"`
+	Length          *float64 `json:"length"`
+	Width           *float64 `json:"width"`
+}
+
+// VehiclePatch is a method that returns the vehicle patch of the request
+func (r VehiclePatchRequestJSON) VehiclePatch() internal.VehiclePatch {
+	return internal.VehiclePatch{
+		Brand:           r.Brand,
+		Model:           r.Model,
+		Registration:    r.Registration,
+		Color:           r.Color,
+		FabricationYear: r.FabricationYear,
+		Capacity:        r.Capacity,
+		MaxSpeed:        r.MaxSpeed,
+		FuelType:        r.FuelType,
+		Transmission:    r.Transmission,
+		Weight:          r.Weight,
+		Height:          r.Height,
+		Length:          r.Length,
+		Width:           r.Width,
+	}
+}
+
+// VehicleResponseJSON is a struct that represents a vehicle in the body of a response
+// - the wire format clients depend on: field names mirror the vehicles file, dimensions are grouped
+// - frozen by the golden files of testdata, any change to them is a breaking change
+type VehicleResponseJSON struct {
+	Id              int                    `json:"id"`
+	Brand           string                 `json:"brand"`
+	Model           string                 `json:"model"`
+	Registration    string                 `json:"registration"`
+	Color           string                 `json:"color"`
+	FabricationYear int                    `json:"year"`
+	Capacity        int                    `json:"passengers"`
+	MaxSpeed        float64                `json:"max_speed"`
+	FuelType        string                 `json:"fuel_type"`
+	Transmission    string                 `json:"transmission"`
+	Weight          float64                `json:"weight"`
+	Dimensions      DimensionsResponseJSON `json:"dimensions"`
+}
+
+// DimensionsResponseJSON is a struct that represents the dimensions of a vehicle in the body of a response
+type DimensionsResponseJSON struct {
+	Height float64 `json:"height"`
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+}
+
+// NewVehicleResponseJSON is a function that returns the response representation of a vehicle
+func NewVehicleResponseJSON(v internal.Vehicle) VehicleResponseJSON {
+	return VehicleResponseJSON{
+		Id:              v.Id,
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		Dimensions: DimensionsResponseJSON{
+			Height: v.Height,
+			Length: v.Length,
+			Width:  v.Width,
+		},
+	}
 }
 
 // NewHandlerVehicle is a function that returns a new instance of HandlerVehicle
-func NewHandlerVehicle(sv internal.ServiceVehicle) *HandlerVehicle {
-	return &HandlerVehicle{sv: sv}
+// - cfg can be nil, in which case the exact average capacity is returned as a float
+func NewHandlerVehicle(sv internal.ServiceVehicle, cfg *ConfigHandlerVehicle) *HandlerVehicle {
+	h := &HandlerVehicle{sv: sv, capacityFormat: AverageCapacityFormatFloat}
+	if cfg != nil {
+		h.capacityRounding = cfg.CapacityRounding
+		if cfg.CapacityFormat != "" {
+			h.capacityFormat = cfg.CapacityFormat
+		}
+	}
+	return h
 }
 
-// FindByColorAndYear returns a handler that returns a map of vehicles that match the color and fabrication year
-func (h *HandlerVehicle) FindByColorAndYear() gin.HandlerFunc {
+// FindByID returns a handler that returns the vehicle with the id
+func (h *HandlerVehicle) FindByID() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// request
-		color := ctx.Param("color")
-		year, err := strconv.Atoi(ctx.Param("year"))
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			requestErrorGin(ctx, "invalid id")
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		v, err := h.sv.FindByID(id)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "vehicle found",
+			"data":    fs.Vehicle(v),
+		})
+	}
+}
+
+// FindByRegistration returns a handler that returns the vehicles that match the registration, sorted by id
+// - registrations are not unique, so the data is always a list
+func (h *HandlerVehicle) FindByRegistration() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		registration := ctx.Param("registration")
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusBadRequest, "invalid year")
+			requestErrorGin(ctx, err.Error())
 			return
 		}
 
 		// process
-		v, err := h.sv.FindByColorAndYear(color, year)
+		v, err := h.sv.FindByRegistration(registration)
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusInternalServerError, "internal error")
+			errorGin(ctx, err)
 			return
 		}
 
 		// response
+		vs := make([]internal.Vehicle, 0, len(v))
+		for _, value := range v {
+			vs = append(vs, value)
+		}
+		sort.Slice(vs, func(i, j int) bool { return vs[i].Id < vs[j].Id })
 		response.JSONGin(ctx, http.StatusOK, map[string]any{
 			"message": "vehicles found",
-			"data": v,
+			"data":    fs.Vehicles(vs),
+			"total":   len(vs),
 		})
 	}
 }
 
-// FindByBrandAndYearRange returns a handler that returns a map of vehicles that match the brand and a range of fabrication years
+// FindByColorAndYear returns a handler that returns a page of vehicles that match the color and fabrication year
+func (h *HandlerVehicle) FindByColorAndYear() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		color := ctx.Param("color")
+		year, err := strconv.Atoi(ctx.Param("year"))
+		if err != nil {
+			requestErrorGin(ctx, "invalid year")
+			return
+		}
+		page, err := PageQueryFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		// - the range of the filter is exposed as the year of the route
+		filter := internal.VehicleFilter{Color: &color, YearMin: &year, YearMax: &year}
+		p, err := h.sv.FindPage(filter, page)
+		if err != nil {
+			errorGin(ctx, renameRangeError(err, map[string]string{"year_min": "year", "year_max": "year"}))
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, vehiclePageJSON(page, p, fs))
+	}
+}
+
+// FindByBrandAndYearRange returns a handler that returns a page of vehicles that match the brand and a range of fabrication years
 func (h *HandlerVehicle) FindByBrandAndYearRange() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// request
 		brand := ctx.Param("brand")
 		startYear, err := strconv.Atoi(ctx.Param("start_year"))
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusBadRequest, "invalid start_year")
+			requestErrorGin(ctx, "invalid start_year")
 			return
 		}
 		endYear, err := strconv.Atoi(ctx.Param("end_year"))
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusBadRequest, "invalid end_year")
+			requestErrorGin(ctx, "invalid end_year")
+			return
+		}
+		page, err := PageQueryFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
 			return
 		}
 
 		// process
-		v, err := h.sv.FindByBrandAndYearRange(brand, startYear, endYear)
+		// - the range of the filter is exposed as the start_year and end_year of the route
+		filter := internal.VehicleFilter{Brand: &brand, YearMin: &startYear, YearMax: &endYear}
+		p, err := h.sv.FindPage(filter, page)
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusInternalServerError, "internal error")
+			errorGin(ctx, renameRangeError(err, map[string]string{"year_min": "start_year", "year_max": "end_year"}))
 			return
 		}
 
 		// response
-		response.JSONGin(ctx, http.StatusOK, map[string]any{
-			"message": "vehicles found",
-			"data": v,
-		})
+		response.JSONGin(ctx, http.StatusOK, vehiclePageJSON(page, p, fs))
 	}
 }
 
@@ -87,12 +335,7 @@ func (h *HandlerVehicle) AverageMaxSpeedByBrand() gin.HandlerFunc {
 		// process
 		average, err := h.sv.AverageMaxSpeedByBrand(brand)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrServiceNoVehicles):
-				response.ErrorGin(ctx, http.StatusNotFound, "vehicles not found")
-			default:
-				response.ErrorGin(ctx, http.StatusInternalServerError, "internal error")
-			}
+			errorGin(ctx, err)
 			return
 		}
 
@@ -105,65 +348,258 @@ func (h *HandlerVehicle) AverageMaxSpeedByBrand() gin.HandlerFunc {
 }
 
 // AverageCapacityByBrand returns a handler that returns the average capacity of the vehicles by brand
+// - the format query parameter chooses between the float and the integer formats
 func (h *HandlerVehicle) AverageCapacityByBrand() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// request
 		brand := ctx.Param("brand")
+		format := h.capacityFormat
+		if f := ctx.Query("format"); f != "" {
+			format = AverageCapacityFormat(f)
+			if !format.Valid() {
+				requestErrorGin(ctx, "invalid format")
+				return
+			}
+		}
 
 		// process
 		average, err := h.sv.AverageCapacityByBrand(brand)
 		if err != nil {
-			switch {
-			case errors.Is(err, internal.ErrServiceNoVehicles):
-				response.ErrorGin(ctx, http.StatusNotFound, "vehicles not found")
-			default:
-				response.ErrorGin(ctx, http.StatusInternalServerError, "internal error")
-			}
+			errorGin(ctx, err)
 			return
 		}
 
 		// response
+		var data any
+		switch format {
+		case AverageCapacityFormatInteger:
+			// - truncated, as the integer division it used to be
+			data = int(math.Trunc(average.Value))
+		default:
+			data = AverageCapacityResponseJSON{
+				Brand:   brand,
+				Average: h.capacityRounding.Round(average.Value),
+				Count:   average.Count,
+			}
+		}
 		response.JSONGin(ctx, http.StatusOK, map[string]any{
 			"message": "average capacity found",
-			"data": average,
+			"data": data,
 		})
 	}
 }
 
-// SearchByWeightRange returns a handler that returns a map of vehicles that match the weight range
+// SearchByWeightRange returns a handler that returns a page of vehicles that match the weight range
+// - the range is half-open when only weight_min or only weight_max is given
 func (h *HandlerVehicle) SearchByWeightRange() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// request
-		var query internal.SearchQuery
+		var filter internal.VehicleFilter
 
-		// check if query exists and decode
-		ok := ctx.Request.URL.Query().Has("weight_min") && ctx.Request.URL.Query().Has("weight_max")
-		if ok {
-			var err error
-			query.FromWeight, err = strconv.ParseFloat(ctx.Query("weight_min"), 64)
+		// check which bounds exist and decode
+		if ctx.Request.URL.Query().Has("weight_min") {
+			weightMin, err := strconv.ParseFloat(ctx.Query("weight_min"), 64)
 			if err != nil {
-				response.ErrorGin(ctx, http.StatusBadRequest, "invalid weight_min")
+				requestErrorGin(ctx, "invalid weight_min")
 				return
 			}
-
-			query.ToWeight, err = strconv.ParseFloat(ctx.Query("weight_max"), 64)
+			filter.WeightMin = &weightMin
+		}
+		if ctx.Request.URL.Query().Has("weight_max") {
+			weightMax, err := strconv.ParseFloat(ctx.Query("weight_max"), 64)
 			if err != nil {
-				response.ErrorGin(ctx, http.StatusBadRequest, "invalid weight_max")
+				requestErrorGin(ctx, "invalid weight_max")
 				return
 			}
+			filter.WeightMax = &weightMax
+		}
+		page, err := PageQueryFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		// - without any bound every vehicle is returned
+		// - range errors name weight_min and weight_max, the parameters of the route
+		p, err := h.sv.FindPage(filter, page)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, vehiclePageJSON(page, p, fs))
+	}
+}
+
+// Find returns a handler that returns a page of vehicles that match every criteria given in the query
+func (h *HandlerVehicle) Find() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		filter, err := VehicleFilterFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		page, err := PageQueryFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		p, err := h.sv.FindPage(filter, page)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, vehiclePageJSON(page, p, fs))
+	}
+}
+
+// Create returns a handler that registers a new vehicle
+func (h *HandlerVehicle) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		var body VehicleRequestJSON
+		err := request.JSON(ctx.Request, &body)
+		if err != nil {
+			requestErrorGin(ctx, "invalid body")
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		v := internal.Vehicle{VehicleAttributes: body.VehicleAttributes()}
+		err = h.sv.Save(&v)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusCreated, map[string]any{
+			"message": "vehicle created",
+			"data":    fs.Vehicle(v),
+		})
+	}
+}
+
+// Update returns a handler that replaces an existing vehicle
+func (h *HandlerVehicle) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			requestErrorGin(ctx, "invalid id")
+			return
+		}
+		var body VehicleRequestJSON
+		err = request.JSON(ctx.Request, &body)
+		if err != nil {
+			requestErrorGin(ctx, "invalid body")
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
 		}
 
 		// process
-		v, err := h.sv.SearchByWeightRange(query, ok)
+		v := internal.Vehicle{Id: id, VehicleAttributes: body.VehicleAttributes()}
+		err = h.sv.Update(&v)
 		if err != nil {
-			response.ErrorGin(ctx, http.StatusInternalServerError, "internal error")
+			errorGin(ctx, err)
 			return
 		}
 
 		// response
 		response.JSONGin(ctx, http.StatusOK, map[string]any{
-			"message": "vehicles found",
-			"data": v,
+			"message": "vehicle updated",
+			"data":    fs.Vehicle(v),
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Patch returns a handler that partially updates an existing vehicle
+func (h *HandlerVehicle) Patch() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			requestErrorGin(ctx, "invalid id")
+			return
+		}
+		var body VehiclePatchRequestJSON
+		err = request.JSON(ctx.Request, &body)
+		if err != nil {
+			requestErrorGin(ctx, "invalid body")
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		v, err := h.sv.Patch(id, body.VehiclePatch())
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "vehicle updated",
+			"data":    fs.Vehicle(v),
+		})
+	}
+}
+
+// Delete returns a handler that deletes a vehicle by its id
+func (h *HandlerVehicle) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		id, err := strconv.Atoi(ctx.Param("id"))
+		if err != nil {
+			requestErrorGin(ctx, "invalid id")
+			return
+		}
+
+		// process
+		err = h.sv.Delete(id)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		response.JSONGin(ctx, http.StatusNoContent, nil)
+	}
+}
+
+// FieldErrorJSON is a struct that represents a field of a vehicle that breaks a domain rule in JSON format
+type FieldErrorJSON struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
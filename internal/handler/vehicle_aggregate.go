@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerAggregateVehicle is a struct with methods that represent handlers for aggregations of vehicles
+type HandlerAggregateVehicle struct {
+	// ag is the aggregator that will be used by the handler
+	ag internal.AggregatorVehicle
+}
+
+// NewHandlerAggregateVehicle is a function that returns a new instance of HandlerAggregateVehicle
+func NewHandlerAggregateVehicle(ag internal.AggregatorVehicle) *HandlerAggregateVehicle {
+	return &HandlerAggregateVehicle{ag: ag}
+}
+
+// VehicleAggregateJSON is a struct that represents the metrics of a group of vehicles in the body of a response
+type VehicleAggregateJSON struct {
+	Group   map[string]string  `json:"group"`
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// VehicleAggregateResponseJSON is a struct that represents an aggregation of the vehicles in the body of a response
+type VehicleAggregateResponseJSON struct {
+	GroupBy []string               `json:"group_by"`
+	Metrics []string               `json:"metrics"`
+	Groups  []VehicleAggregateJSON `json:"groups"`
+}
+
+// compareOps are the operators of a threshold, two character ones first so they are not read as their prefix
+var compareOps = []internal.CompareOp{
+	internal.CompareGte,
+	internal.CompareLte,
+	internal.CompareNe,
+	internal.CompareGt,
+	internal.CompareLt,
+	internal.CompareEq,
+}
+
+// AggregateQueryFromQuery is a function that decodes an aggregation from the query parameters
+// - group_by is a comma separated list of fields, metrics a comma separated list like count,avg(max_speed)
+// - having is a comma separated list of thresholds like count>5,avg(max_speed)>=150
+// - sort is a comma separated list of grouping fields or selected metrics, each one descending if prefixed with "-"
+// - unknown, repeated or malformed parameters are a *QueryError
+func AggregateQueryFromQuery(query url.Values) (q internal.AggregateQuery, err error) {
+	// params: sorted so the reported error does not depend on map order
+	params := make([]string, 0, len(query))
+	for param := range query {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		if len(query[param]) > 1 {
+			err = &QueryError{Param: param, Reason: "repeated parameter"}
+			return
+		}
+		value := query.Get(param)
+
+		switch param {
+		case "group_by":
+			for _, field := range strings.Split(value, ",") {
+				f := internal.VehicleGroupField(field)
+				if !f.Valid() {
+					err = &QueryError{Param: param, Reason: "invalid"}
+					return
+				}
+				q.GroupBy = append(q.GroupBy, f)
+			}
+		case "metrics":
+			for _, metric := range strings.Split(value, ",") {
+				m, ok := internal.ParseVehicleMetric(metric)
+				if !ok {
+					err = &QueryError{Param: param, Reason: "invalid"}
+					return
+				}
+				q.Metrics = append(q.Metrics, m)
+			}
+		case "having":
+			for _, threshold := range strings.Split(value, ",") {
+				h, ok := parseHaving(threshold)
+				if !ok {
+					err = &QueryError{Param: param, Reason: "invalid"}
+					return
+				}
+				q.Having = append(q.Having, h)
+			}
+		case "sort":
+			for _, key := range strings.Split(value, ",") {
+				q.Sort = append(q.Sort, internal.AggregateSortKey{Key: strings.TrimPrefix(key, "-"), Desc: strings.HasPrefix(key, "-")})
+			}
+		default:
+			err = &QueryError{Param: param, Reason: "unknown parameter"}
+			return
+		}
+	}
+
+	// sort keys: a grouping field or a selected metric, count when none is selected
+	keys := map[string]bool{}
+	for _, field := range q.GroupBy {
+		keys[string(field)] = true
+	}
+	for _, m := range q.Metrics {
+		keys[m.String()] = true
+	}
+	if len(q.Metrics) == 0 {
+		keys[string(internal.AggregateCount)] = true
+	}
+	for _, key := range q.Sort {
+		if !keys[key.Key] {
+			err = &QueryError{Param: "sort", Reason: "invalid"}
+			return
+		}
+	}
+	return
+}
+
+// parseHaving is a function that decodes a threshold like avg(max_speed)>=150
+// - ok is false if the metric, the operator or the value are not valid
+func parseHaving(s string) (h internal.VehicleHaving, ok bool) {
+	i := strings.IndexAny(s, "<>=!")
+	if i < 0 {
+		return
+	}
+	for _, op := range compareOps {
+		rest, found := strings.CutPrefix(s[i:], string(op))
+		if !found {
+			continue
+		}
+		h.Metric, ok = internal.ParseVehicleMetric(s[:i])
+		if !ok {
+			return
+		}
+		h.Op = op
+		var err error
+		h.Value, err = strconv.ParseFloat(rest, 64)
+		ok = err == nil && !math.IsNaN(h.Value) && !math.IsInf(h.Value, 0)
+		return
+	}
+	return
+}
+
+// Aggregate returns a handler that groups the vehicles and returns the metrics of each group
+func (h *HandlerAggregateVehicle) Aggregate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		q, err := AggregateQueryFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		ag, err := h.ag.Aggregate(q)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		if len(q.Metrics) == 0 {
+			q.Metrics = []internal.VehicleMetric{{Func: internal.AggregateCount}}
+		}
+		data := VehicleAggregateResponseJSON{
+			GroupBy: make([]string, 0, len(q.GroupBy)),
+			Metrics: make([]string, 0, len(q.Metrics)),
+			Groups:  make([]VehicleAggregateJSON, 0, len(ag)),
+		}
+		for _, field := range q.GroupBy {
+			data.GroupBy = append(data.GroupBy, string(field))
+		}
+		for _, m := range q.Metrics {
+			data.Metrics = append(data.Metrics, m.String())
+		}
+		for _, a := range ag {
+			g := VehicleAggregateJSON{
+				Group:   make(map[string]string, len(a.Group)),
+				Metrics: make(map[string]float64, len(a.Metrics)),
+			}
+			for i, value := range a.Group {
+				g.Group[data.GroupBy[i]] = value
+			}
+			for i, value := range a.Metrics {
+				g.Metrics[data.Metrics[i]] = value
+			}
+			data.Groups = append(data.Groups, g)
+		}
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "vehicles aggregated",
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// AggregateTestCase is a struct that represents a test case for Aggregate
+type AggregateTestCase struct {
+	server             *gin.Engine
+	mockAggregator     *service.MockAggregator
+	name               string
+	query              string
+	aggregateQuery     internal.AggregateQuery
+	returnedAggregates []internal.VehicleAggregate
+	expectedData       interface{}
+	serviceError       error
+	handlerError       error
+	mockOnCalled       bool
+	httpSetup          *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *AggregateTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.mockAggregator = &service.MockAggregator{}
+	tc.server.GET(basePath+"/aggregate", NewHandlerAggregateVehicle(tc.mockAggregator).Aggregate())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": "vehicles aggregated",
+			"data":    tc.expectedData,
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.mockAggregator.On("Aggregate", tc.aggregateQuery).Return(tc.returnedAggregates, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/aggregate%s", basePath, tc.query), nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *AggregateTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.mockAggregator.AssertCalled(t, "Aggregate", tc.aggregateQuery))
+	} else {
+		tc.mockAggregator.AssertNotCalled(t, "Aggregate", tc.aggregateQuery)
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Aggregate is a method that tests the Aggregate handler
+func TestHandler_Aggregate(t *testing.T) {
+	// Create test cases
+	testCases := []AggregateTestCase{
+		{
+			// This test evaluates that Aggregate returns 200 ok and the metrics of each group keyed by name
+			name:  "should return 200 ok and the metrics of each group keyed by name",
+			query: "?" + url.Values{"group_by": {"brand,fuel_type"}, "metrics": {"count,avg(max_speed),sum(weight)"}, "having": {"count>=2,avg(max_speed)!=0"}, "sort": {"-avg(max_speed),brand"}}.Encode(),
+			aggregateQuery: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByFuelType},
+				Metrics: []internal.VehicleMetric{
+					{Func: internal.AggregateCount},
+					{Func: internal.AggregateAvg, Field: internal.NumericMaxSpeed},
+					{Func: internal.AggregateSum, Field: internal.NumericWeight},
+				},
+				Having: []internal.VehicleHaving{
+					{Metric: internal.VehicleMetric{Func: internal.AggregateCount}, Op: internal.CompareGte, Value: 2},
+					{Metric: internal.VehicleMetric{Func: internal.AggregateAvg, Field: internal.NumericMaxSpeed}, Op: internal.CompareNe, Value: 0},
+				},
+				Sort: []internal.AggregateSortKey{{Key: "avg(max_speed)", Desc: true}, {Key: "brand"}},
+			},
+			returnedAggregates: []internal.VehicleAggregate{
+				{Group: []string{"Ford", "gas"}, Metrics: []float64{2, 150, 2500}},
+			},
+			expectedData: VehicleAggregateResponseJSON{
+				GroupBy: []string{"brand", "fuel_type"},
+				Metrics: []string{"count", "avg(max_speed)", "sum(weight)"},
+				Groups: []VehicleAggregateJSON{
+					{
+						Group:   map[string]string{"brand": "Ford", "fuel_type": "gas"},
+						Metrics: map[string]float64{"count": 2, "avg(max_speed)": 150, "sum(weight)": 2500},
+					},
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 200 ok and the count of every vehicle when the query is empty
+			name: "should return 200 ok and the count of every vehicle when the query is empty",
+			returnedAggregates: []internal.VehicleAggregate{
+				{Group: []string{}, Metrics: []float64{5}},
+			},
+			expectedData: VehicleAggregateResponseJSON{
+				GroupBy: []string{},
+				Metrics: []string{"count"},
+				Groups: []VehicleAggregateJSON{
+					{Group: map[string]string{}, Metrics: map[string]float64{"count": 5}},
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when a grouping field is unknown
+			name:         "should return 400 bad request error when a grouping field is unknown",
+			query:        "?group_by=brand,model",
+			handlerError: errors.New("invalid group_by"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when a metric is malformed
+			name:         "should return 400 bad request error when a metric is malformed",
+			query:        "?" + url.Values{"metrics": {"avg(max_speed"}}.Encode(),
+			handlerError: errors.New("invalid metrics"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when a threshold has no operator
+			name:         "should return 400 bad request error when a threshold has no operator",
+			query:        "?having=count",
+			handlerError: errors.New("invalid having"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when a sort key is not selected
+			name:         "should return 400 bad request error when a sort key is not selected",
+			query:        "?group_by=brand&sort=color",
+			handlerError: errors.New("invalid sort"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when a parameter is unknown
+			name:         "should return 400 bad request error when a parameter is unknown",
+			query:        "?brand=Ford",
+			handlerError: errors.New("unknown parameter brand"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 400 bad request error when the aggregator rejects the query
+			name:           "should return 400 bad request error when the aggregator rejects the query",
+			query:          "?group_by=brand,brand",
+			aggregateQuery: internal.AggregateQuery{GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByBrand}},
+			serviceError:   internal.ErrServiceInvalidAggregate,
+			handlerError:   errors.New("invalid aggregate"),
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_aggregate",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Aggregate returns 500 internal server error when the aggregator returns an error
+			name:         "should return 500 internal server error when the aggregator returns an error",
+			serviceError: errors.New("error"),
+			handlerError: errors.New("internal error"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"app/internal"
+	"net/url"
+	"strings"
+)
+
+// fieldsParam is the query parameter that selects the fields of the returned vehicles
+const fieldsParam = "fields"
+
+// vehicleFields are the fields of the vehicle schema, by path
+// - paths follow the json names of VehicleResponseJSON, nested fields are separated by a dot
+var vehicleFields = map[string]func(v internal.Vehicle) any{
+	"id":                func(v internal.Vehicle) any { return v.Id },
+	"brand":             func(v internal.Vehicle) any { return v.Brand },
+	"model":             func(v internal.Vehicle) any { return v.Model },
+	"registration":      func(v internal.Vehicle) any { return v.Registration },
+	"color":             func(v internal.Vehicle) any { return v.Color },
+	"year":              func(v internal.Vehicle) any { return v.FabricationYear },
+	"passengers":        func(v internal.Vehicle) any { return v.Capacity },
+	"max_speed":         func(v internal.Vehicle) any { return v.MaxSpeed },
+	"fuel_type":         func(v internal.Vehicle) any { return v.FuelType },
+	"transmission":      func(v internal.Vehicle) any { return v.Transmission },
+	"weight":            func(v internal.Vehicle) any { return v.Weight },
+	"dimensions.height": func(v internal.Vehicle) any { return v.Height },
+	"dimensions.length": func(v internal.Vehicle) any { return v.Length },
+	"dimensions.width":  func(v internal.Vehicle) any { return v.Width },
+}
+
+// vehicleFieldGroups are the parents of nested fields, with their children
+var vehicleFieldGroups = map[string][]string{
+	"dimensions": {"dimensions.height", "dimensions.length", "dimensions.width"},
+}
+
+// FieldSet is a struct that represents the fields of the vehicles returned to the client
+// - the zero value selects every field
+type FieldSet struct {
+	// paths are the selected fields, nil to select every field
+	paths []string
+}
+
+// FieldSetFromQuery is a function that decodes the selected fields from the query parameters
+// - fields is a comma separated list of paths such as id,brand,dimensions.height
+// - unknown fields are a *QueryError
+func FieldSetFromQuery(query url.Values) (fs FieldSet, err error) {
+	if !query.Has(fieldsParam) {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(query.Get(fieldsParam), ",") {
+		paths := []string{path}
+		if children, ok := vehicleFieldGroups[path]; ok {
+			paths = children
+		} else if _, ok := vehicleFields[path]; !ok {
+			err = &QueryError{Param: path, Reason: "unknown field"}
+			return
+		}
+
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				fs.paths = append(fs.paths, p)
+			}
+		}
+	}
+	return
+}
+
+// Vehicle is a method that returns the vehicle with only the selected fields
+// - the whole VehicleResponseJSON is returned when every field is selected
+func (fs FieldSet) Vehicle(v internal.Vehicle) any {
+	if fs.paths == nil {
+		return NewVehicleResponseJSON(v)
+	}
+
+	projection := make(map[string]any, len(fs.paths))
+	for _, path := range fs.paths {
+		// nested fields
+		parent, child, nested := strings.Cut(path, ".")
+		if nested {
+			group, ok := projection[parent].(map[string]any)
+			if !ok {
+				group = make(map[string]any)
+				projection[parent] = group
+			}
+			group[child] = vehicleFields[path](v)
+			continue
+		}
+
+		projection[path] = vehicleFields[path](v)
+	}
+	return projection
+}
+
+// Vehicles is a method that returns the vehicles with only the selected fields, in order
+func (fs FieldSet) Vehicles(v []internal.Vehicle) any {
+	projections := make([]any, 0, len(v))
+	for _, vh := range v {
+		projections = append(projections, fs.Vehicle(vh))
+	}
+	return projections
+}
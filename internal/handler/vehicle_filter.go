@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrHandlerInvalidQuery is an error that represents an invalid query parameter
+	ErrHandlerInvalidQuery = errors.New("handler: invalid query")
+)
+
+// QueryError is a struct that represents an invalid query parameter
+// - its message is meant to be shown to the client, e.g. "invalid year_min"
+type QueryError struct {
+	// Param is the name of the query parameter, or of the field it refers to
+	Param string
+	// Reason is what is wrong with it: invalid, unknown parameter, repeated parameter or unknown field
+	Reason string
+}
+
+// Error is a method that returns the message of the error
+func (e *QueryError) Error() string {
+	return e.Reason + " " + e.Param
+}
+
+// Unwrap is a method that returns ErrHandlerInvalidQuery
+func (e *QueryError) Unwrap() error {
+	return ErrHandlerInvalidQuery
+}
+
+// VehicleFilterFromQuery is a function that decodes a filter from the query parameters
+// - absent parameters leave their criteria unset, unknown, repeated or malformed ones are a *QueryError
+// - the parameters of the page and the selected fields are skipped, see PageQueryFromQuery and FieldSetFromQuery
+func VehicleFilterFromQuery(query url.Values) (f internal.VehicleFilter, err error) {
+	// params: sorted so the reported error does not depend on map order
+	params := make([]string, 0, len(query))
+	for param := range query {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		if pageParams[param] || param == fieldsParam {
+			continue
+		}
+		if len(query[param]) > 1 {
+			err = &QueryError{Param: param, Reason: "repeated parameter"}
+			return
+		}
+		value := query.Get(param)
+
+		switch param {
+		case "brand":
+			f.Brand = &value
+		case "model":
+			f.Model = &value
+		case "color":
+			f.Color = &value
+		case "fuel_type":
+			f.FuelType = &value
+		case "transmission":
+			f.Transmission = &value
+		case "year_min":
+			f.YearMin, err = parseIntParam(param, value)
+		case "year_max":
+			f.YearMax, err = parseIntParam(param, value)
+		case "passengers_min":
+			f.CapacityMin, err = parseIntParam(param, value)
+		case "passengers_max":
+			f.CapacityMax, err = parseIntParam(param, value)
+		case "speed_min":
+			f.SpeedMin, err = parseFloatParam(param, value)
+		case "speed_max":
+			f.SpeedMax, err = parseFloatParam(param, value)
+		case "weight_min":
+			f.WeightMin, err = parseFloatParam(param, value)
+		case "weight_max":
+			f.WeightMax, err = parseFloatParam(param, value)
+		default:
+			err = &QueryError{Param: param, Reason: "unknown parameter"}
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// parseIntParam is a function that parses an integer query parameter
+func parseIntParam(param string, value string) (n *int, err error) {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		err = &QueryError{Param: param, Reason: "invalid"}
+		return
+	}
+	n = &v
+	return
+}
+
+// parseFloatParam is a function that parses a decimal query parameter
+func parseFloatParam(param string, value string) (n *float64, err error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		err = &QueryError{Param: param, Reason: "invalid"}
+		return
+	}
+	n = &v
+	return
+}
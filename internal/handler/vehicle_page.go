@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxPageLimit is the maximum number of vehicles of a page
+const maxPageLimit = 1000
+
+// pageParams are the query parameters that order and window a list, accepted by every list route
+var pageParams = map[string]bool{
+	"sort":       true,
+	"limit":      true,
+	"offset":     true,
+	"page_token": true,
+}
+
+// errPageToken is the error of a page token that can not resume the list
+var errPageToken = errors.New("invalid page token")
+
+// pageToken is a struct that represents the position a page token resumes a list from
+type pageToken struct {
+	// Sort is the sort the token was issued for, in the format of the sort parameter
+	Sort string `json:"sort"`
+	// Key are the values of the sort fields of the last vehicle of the page, in order
+	Key []json.RawMessage `json:"key"`
+	// Id is the id of the last vehicle of the page
+	Id int `json:"id"`
+}
+
+// PageQueryFromQuery is a function that decodes the order and window of a list from the query parameters
+// - sort is a comma separated list of fields, each one descending if prefixed with "-"
+// - page_token is the next_page_token of a previous page, it must be given the same sort and can not be combined with offset
+// - absent parameters return every vehicle sorted by id
+func PageQueryFromQuery(query url.Values) (p internal.PageQuery, err error) {
+	// sort
+	if query.Has("sort") {
+		for _, field := range strings.Split(query.Get("sort"), ",") {
+			key := internal.VehicleSortKey{Field: internal.VehicleSortField(strings.TrimPrefix(field, "-")), Desc: strings.HasPrefix(field, "-")}
+			if !key.Field.Valid() {
+				err = &QueryError{Param: "sort", Reason: "invalid"}
+				return
+			}
+			p.Sort = append(p.Sort, key)
+		}
+	}
+
+	// limit
+	if query.Has("limit") {
+		p.Limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || p.Limit < 1 || p.Limit > maxPageLimit {
+			err = &QueryError{Param: "limit", Reason: "invalid"}
+			return
+		}
+	}
+
+	// offset or page_token
+	switch {
+	case query.Has("offset") && query.Has("page_token"):
+		err = &QueryError{Param: "page_token", Reason: "invalid"}
+	case query.Has("offset"):
+		p.Offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil || p.Offset < 0 {
+			err = &QueryError{Param: "offset", Reason: "invalid"}
+		}
+	case query.Has("page_token"):
+		p.After, err = decodePageToken(query.Get("page_token"), p.Sort)
+		if err != nil {
+			err = &QueryError{Param: "page_token", Reason: "invalid"}
+		}
+	}
+	return
+}
+
+// nextPageToken is a function that returns the token of the page after p, empty if p is the last one
+// - the token is opaque to clients, it encodes the sort and the sort fields and id of the last vehicle of p
+// - the next page seeks past that vehicle, so vehicles added or removed before it do not shift the pages
+func nextPageToken(page internal.PageQuery, p internal.VehiclePage) string {
+	if page.Limit == 0 || len(p.Vehicles) == 0 || !p.More {
+		return ""
+	}
+
+	last := p.Vehicles[len(p.Vehicles)-1]
+	token := pageToken{Sort: sortParam(page.Sort), Key: make([]json.RawMessage, 0, len(page.Sort)), Id: last.Id}
+	for _, key := range page.Sort {
+		value, err := json.Marshal(key.Field.Ref(&last))
+		if err != nil {
+			return ""
+		}
+		token.Key = append(token.Key, value)
+	}
+	b, err := json.Marshal(token)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodePageToken is a function that returns the vehicle a page token seeks past
+// - only the sort fields and the id of the vehicle are set
+// - the token must have been issued for sort
+func decodePageToken(s string, sort []internal.VehicleSortKey) (after *internal.Vehicle, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return
+	}
+	var token pageToken
+	err = json.Unmarshal(b, &token)
+	if err != nil {
+		return
+	}
+	if token.Sort != sortParam(sort) || len(token.Key) != len(sort) {
+		err = errPageToken
+		return
+	}
+
+	v := internal.Vehicle{Id: token.Id}
+	for i, key := range sort {
+		err = json.Unmarshal(token.Key[i], key.Field.Ref(&v))
+		if err != nil {
+			return
+		}
+	}
+	after = &v
+	return
+}
+
+// sortParam is a function that returns the sort keys in the format of the sort parameter
+func sortParam(sort []internal.VehicleSortKey) string {
+	fields := make([]string, 0, len(sort))
+	for _, key := range sort {
+		if key.Desc {
+			fields = append(fields, "-"+string(key.Field))
+		} else {
+			fields = append(fields, string(key.Field))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// vehiclePageJSON is a function that returns the body of a list response, with only the selected fields
+func vehiclePageJSON(page internal.PageQuery, p internal.VehiclePage, fs FieldSet) map[string]any {
+	body := map[string]any{
+		"message": "vehicles found",
+		"data":    fs.Vehicles(p.Vehicles),
+		"total":   p.Total,
+	}
+	if token := nextPageToken(page, p); token != "" {
+		body["next_page_token"] = token
+	}
+	return body
+}
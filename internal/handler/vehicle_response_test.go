@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"app/internal"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update is a flag that rewrites the golden files with the current output instead of comparing against them
+// - go test ./internal/handler/... -run Golden -update
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// goldenVehicle is the vehicle every golden file is rendered from
+var goldenVehicle = internal.Vehicle{
+	Id: 1,
+	VehicleAttributes: internal.VehicleAttributes{
+		Brand:           "Ford",
+		Model:           "Fiesta",
+		Registration:    "ABC-1234",
+		Color:           "Red",
+		FabricationYear: 2010,
+		Capacity:        5,
+		MaxSpeed:        180.5,
+		FuelType:        "Gasoline",
+		Transmission:    "Manual",
+		Weight:          1000,
+		Dimensions: internal.Dimensions{
+			Height: 1.5,
+			Length: 4,
+			Width:  1.8,
+		},
+	},
+}
+
+// assertGolden is a function that compares a JSON body against a golden file of testdata
+func assertGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, body, "", "  "))
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		require.NoError(t, os.WriteFile(path, indented.Bytes(), 0o644))
+	}
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), indented.String(), "wire format changed, run with -update only if the change is intended")
+}
+
+// TestVehicleResponseJSON_Golden is a test function that freezes the wire format of the vehicle responses
+func TestVehicleResponseJSON_Golden(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target string
+	}{
+		{name: "vehicle_page", target: basePath + "?limit=1"},
+		{name: "vehicle_page_fields", target: basePath + "?limit=1&fields=id,brand,model,dimensions.height"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			setup := Setup()
+			setup.server.GET(basePath, setup.handler.Find())
+			setup.mockService.On("FindPage", internal.VehicleFilter{}, internal.PageQuery{Limit: 1}).Return(internal.VehiclePage{
+				Vehicles: []internal.Vehicle{goldenVehicle},
+				Total:    2,
+				More:     true,
+			}, nil)
+			req := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			res := httptest.NewRecorder()
+
+			// Act
+			setup.server.ServeHTTP(res, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, res.Code)
+			assertGolden(t, testCase.name, res.Body.Bytes())
+		})
+	}
+
+	t.Run("vehicle", func(t *testing.T) {
+		// Act
+		body, err := json.Marshal(NewVehicleResponseJSON(goldenVehicle))
+
+		// Assert
+		require.NoError(t, err)
+		assertGolden(t, "vehicle", body)
+	})
+}
+
+// TestVehicleFields_MatchResponse is a test function that checks every selectable field exists in the response
+func TestVehicleFields_MatchResponse(t *testing.T) {
+	// Arrange
+	body, err := json.Marshal(NewVehicleResponseJSON(goldenVehicle))
+	require.NoError(t, err)
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(body, &response))
+
+	for path, value := range vehicleFields {
+		// Act
+		var obtained any = response
+		for _, key := range strings.Split(path, ".") {
+			obtained = obtained.(map[string]any)[key]
+		}
+
+		// Assert
+		expected, _ := json.Marshal(value(goldenVehicle))
+		actual, _ := json.Marshal(obtained)
+		assert.JSONEq(t, string(expected), string(actual), path)
+	}
+}
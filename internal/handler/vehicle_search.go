@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSearchLimit is the number of vehicles returned by a search that does not set a limit
+const defaultSearchLimit = 20
+
+// HandlerSearchVehicle is a struct with methods that represent handlers for text searches of vehicles
+type HandlerSearchVehicle struct {
+	// sr is the searcher that will be used by the handler
+	sr internal.SearcherVehicle
+}
+
+// NewHandlerSearchVehicle is a function that returns a new instance of HandlerSearchVehicle
+func NewHandlerSearchVehicle(sr internal.SearcherVehicle) *HandlerSearchVehicle {
+	return &HandlerSearchVehicle{sr: sr}
+}
+
+// VehicleSearchHitJSON is a struct that represents a vehicle matched by a text search in the body of a response
+// - the vehicle holds the selected fields only, see FieldSet
+type VehicleSearchHitJSON struct {
+	Score   float64 `json:"score"`
+	Vehicle any     `json:"vehicle"`
+}
+
+// Search returns a handler that returns the vehicles that best match a text, best first
+// - q is the text, limit the maximum number of vehicles returned and fields the fields of each vehicle
+func (h *HandlerSearchVehicle) Search() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		text, limit, err := searchFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+		fs, err := FieldSetFromQuery(ctx.Request.URL.Query())
+		if err != nil {
+			requestErrorGin(ctx, err.Error())
+			return
+		}
+
+		// process
+		r, err := h.sr.Search(text, limit)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleSearchHitJSON, 0, len(r.Hits))
+		for _, hit := range r.Hits {
+			data = append(data, VehicleSearchHitJSON{Score: hit.Score, Vehicle: fs.Vehicle(hit.Vehicle)})
+		}
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "vehicles found",
+			"data":    data,
+			"total":   r.Total,
+		})
+	}
+}
+
+// searchFromQuery is a function that decodes the text and the limit of a search from the query parameters
+// - an empty text, a limit out of range, and unknown or repeated parameters are a *QueryError
+// - the selected fields are skipped, see FieldSetFromQuery
+func searchFromQuery(query url.Values) (text string, limit int, err error) {
+	// params: sorted so the reported error does not depend on map order
+	params := make([]string, 0, len(query))
+	for param := range query {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		switch {
+		case param == fieldsParam:
+			continue
+		case param != "q" && param != "limit":
+			err = &QueryError{Param: param, Reason: "unknown parameter"}
+		case len(query[param]) > 1:
+			err = &QueryError{Param: param, Reason: "repeated parameter"}
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	text = query.Get("q")
+	if text == "" {
+		err = &QueryError{Param: "q", Reason: "invalid"}
+		return
+	}
+	limit = defaultSearchLimit
+	if query.Has("limit") {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			err = &QueryError{Param: "limit", Reason: "invalid"}
+			return
+		}
+	}
+	return
+}
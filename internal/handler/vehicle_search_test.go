@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// SearchTestCase is a struct that represents a test case for Search
+type SearchTestCase struct {
+	server         *gin.Engine
+	mockSearcher   *service.MockSearcher
+	name           string
+	query          string
+	text           string
+	limit          int
+	returnedResult internal.VehicleSearchResult
+	serviceError   error
+	handlerError   error
+	mockOnCalled   bool
+	httpSetup      *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *SearchTestCase) Arrange() {
+	tc.server = gin.New()
+	tc.mockSearcher = &service.MockSearcher{}
+	tc.server.GET(basePath+"/search", NewHandlerSearchVehicle(tc.mockSearcher).Search())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		u, _ := url.Parse(tc.query)
+		fs, _ := FieldSetFromQuery(u.Query())
+		data := []VehicleSearchHitJSON{}
+		for _, hit := range tc.returnedResult.Hits {
+			data = append(data, VehicleSearchHitJSON{Score: hit.Score, Vehicle: fs.Vehicle(hit.Vehicle)})
+		}
+		expectedResponse = map[string]interface{}{
+			"message": "vehicles found",
+			"data":    data,
+			"total":   tc.returnedResult.Total,
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.mockSearcher.On("Search", tc.text, tc.limit).Return(tc.returnedResult, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/search%s", basePath, tc.query), nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *SearchTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.mockSearcher.AssertCalled(t, "Search", tc.text, tc.limit))
+	} else {
+		tc.mockSearcher.AssertNotCalled(t, "Search", tc.text, tc.limit)
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Search is a method that tests the Search handler
+func TestHandler_Search(t *testing.T) {
+	result := internal.VehicleSearchResult{
+		Hits: []internal.VehicleSearchHit{
+			{Vehicle: internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta"}}, Score: 6},
+			{Vehicle: internal.Vehicle{Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Chevrolet", Model: "Fordson"}}, Score: 2.1},
+		},
+		Total: 2,
+	}
+
+	// Create test cases
+	testCases := []SearchTestCase{
+		{
+			// This test evaluates that Search returns 200 ok and the hits with their score, limited by default
+			name:           "should return 200 ok and the hits with their score, limited by default",
+			query:          "?q=ford%20fi",
+			text:           "ford fi",
+			limit:          defaultSearchLimit,
+			returnedResult: result,
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Search returns 200 ok and passes the limit of the request
+			name:           "should return 200 ok and pass the limit of the request",
+			query:          "?q=ford&limit=5",
+			text:           "ford",
+			limit:          5,
+			returnedResult: internal.VehicleSearchResult{Hits: []internal.VehicleSearchHit{}},
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Search returns 200 ok and only the selected fields of each vehicle
+			name:           "should return 200 ok and only the selected fields of each vehicle",
+			query:          "?q=ford&fields=id,brand",
+			text:           "ford",
+			limit:          defaultSearchLimit,
+			returnedResult: result,
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when a selected field is unknown
+			name:         "should return 400 bad request error when a selected field is unknown",
+			query:        "?q=ford&fields=id,doors",
+			handlerError: errors.New("unknown field doors"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when a parameter is unknown
+			name:         "should return 400 bad request error when a parameter is unknown",
+			query:        "?q=ford&brand=ford",
+			handlerError: errors.New("unknown parameter brand"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when a parameter is repeated
+			name:         "should return 400 bad request error when a parameter is repeated",
+			query:        "?q=ford&q=fiat",
+			handlerError: errors.New("repeated parameter q"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when the text is missing
+			name:         "should return 400 bad request error when the text is missing",
+			handlerError: errors.New("invalid q"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when the limit is out of range
+			name:         "should return 400 bad request error when the limit is out of range",
+			query:        "?q=ford&limit=0",
+			handlerError: errors.New("invalid limit"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 400 bad request error when the searcher rejects the text
+			name:         "should return 400 bad request error when the searcher rejects the text",
+			query:        "?q=%20",
+			text:         " ",
+			limit:        defaultSearchLimit,
+			serviceError: internal.ErrServiceInvalidSearch,
+			handlerError: errors.New("invalid search"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_search",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Search returns 500 internal server error when the searcher returns an error
+			name:         "should return 500 internal server error when the searcher returns an error",
+			query:        "?q=ford",
+			text:         "ford",
+			limit:        defaultSearchLimit,
+			serviceError: errors.New("error"),
+			handlerError: errors.New("internal error"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"app/internal"
+	"app/platform/web/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VehicleStatsJSON is a struct that represents the statistics of a group of vehicles in the body of a response
+type VehicleStatsJSON struct {
+	Group  string  `json:"group,omitempty"`
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+// VehicleStatsResponseJSON is a struct that represents the statistics of a field in the body of a response
+type VehicleStatsResponseJSON struct {
+	Field   string             `json:"field"`
+	GroupBy string             `json:"group_by,omitempty"`
+	Groups  []VehicleStatsJSON `json:"groups"`
+}
+
+// Stats returns a handler that returns the statistics of a numeric field, optionally grouped by a categorical one
+func (h *HandlerVehicle) Stats() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// request
+		field := internal.VehicleNumericField(ctx.Query("field"))
+		if !field.Valid() {
+			requestErrorGin(ctx, "invalid field")
+			return
+		}
+		groupBy := internal.VehicleGroupField(ctx.Query("group_by"))
+		if groupBy != "" && !groupBy.Valid() {
+			requestErrorGin(ctx, "invalid group_by")
+			return
+		}
+
+		// process
+		st, err := h.sv.Stats(field, groupBy)
+		if err != nil {
+			errorGin(ctx, err)
+			return
+		}
+
+		// response
+		data := VehicleStatsResponseJSON{
+			Field:   string(field),
+			GroupBy: string(groupBy),
+			Groups:  make([]VehicleStatsJSON, 0, len(st)),
+		}
+		for _, s := range st {
+			data.Groups = append(data.Groups, VehicleStatsJSON{
+				Group:  s.Group,
+				Count:  s.Count,
+				Min:    s.Min,
+				Max:    s.Max,
+				Mean:   s.Mean,
+				Median: s.Median,
+				StdDev: s.StdDev,
+				P90:    s.P90,
+				P99:    s.P99,
+			})
+		}
+		response.JSONGin(ctx, http.StatusOK, map[string]any{
+			"message": "stats computed",
+			"data":    data,
+		})
+	}
+}
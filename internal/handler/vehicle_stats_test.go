@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// StatsTestCase is a struct that represents a test case for Stats
+type StatsTestCase struct {
+	setup         *TestCaseServerSetup
+	name          string
+	query         string
+	field         internal.VehicleNumericField
+	groupBy       internal.VehicleGroupField
+	returnedStats []internal.VehicleStats
+	expectedData  interface{}
+	serviceError  error
+	handlerError  error
+	mockOnCalled  bool
+	httpSetup     *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *StatsTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.GET(basePath+"/stats", tc.setup.handler.Stats())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": "stats computed",
+			"data":    tc.expectedData,
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("Stats", tc.field, tc.groupBy).Return(tc.returnedStats, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/stats%s", basePath, tc.query), nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *StatsTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "Stats", tc.field, tc.groupBy))
+	} else {
+		tc.setup.mockService.AssertNotCalled(t, "Stats", tc.field, tc.groupBy)
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Stats is a method that tests the Stats handler
+func TestHandler_Stats(t *testing.T) {
+	// Create test cases
+	testCases := []StatsTestCase{
+		{
+			// This test evaluates that Stats returns 200 ok and the statistics of each group
+			name:    "should return 200 ok and the statistics of each group",
+			query:   "?field=max_speed&group_by=brand",
+			field:   internal.NumericMaxSpeed,
+			groupBy: internal.GroupByBrand,
+			returnedStats: []internal.VehicleStats{
+				{Group: "Fiat", Count: 1, Min: 150, Max: 150, Mean: 150, Median: 150, P90: 150, P99: 150},
+				{Group: "Ford", Count: 2, Min: 100, Max: 200, Mean: 150, Median: 150, StdDev: 50, P90: 190, P99: 199},
+			},
+			expectedData: VehicleStatsResponseJSON{
+				Field:   "max_speed",
+				GroupBy: "brand",
+				Groups: []VehicleStatsJSON{
+					{Group: "Fiat", Count: 1, Min: 150, Max: 150, Mean: 150, Median: 150, P90: 150, P99: 150},
+					{Group: "Ford", Count: 2, Min: 100, Max: 200, Mean: 150, Median: 150, StdDev: 50, P90: 190, P99: 199},
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Stats returns 200 ok and a single group without grouping
+			name:  "should return 200 ok and a single group without grouping",
+			query: "?field=weight",
+			field: internal.NumericWeight,
+			returnedStats: []internal.VehicleStats{
+				{Count: 1, Min: 1000, Max: 1000, Mean: 1000, Median: 1000, P90: 1000, P99: 1000},
+			},
+			expectedData: VehicleStatsResponseJSON{
+				Field: "weight",
+				Groups: []VehicleStatsJSON{
+					{Count: 1, Min: 1000, Max: 1000, Mean: 1000, Median: 1000, P90: 1000, P99: 1000},
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Stats returns 400 bad request error when the field is not numeric
+			name:         "should return 400 bad request error when the field is not numeric",
+			query:        "?field=brand",
+			field:        "brand",
+			handlerError: errors.New("invalid field"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Stats returns 400 bad request error when the grouping is unknown
+			name:         "should return 400 bad request error when the grouping is unknown",
+			query:        "?field=weight&group_by=model",
+			field:        internal.NumericWeight,
+			groupBy:      "model",
+			handlerError: errors.New("invalid group_by"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Stats returns 404 not found error when there are no vehicles
+			name:         "should return 404 not found error when there are no vehicles",
+			query:        "?field=weight",
+			field:        internal.NumericWeight,
+			serviceError: internal.ErrServiceNoVehicles,
+			handlerError: errors.New("vehicles not found"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "vehicles_not_found",
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+		{
+			// This test evaluates that Stats returns 500 internal server error when the service returns an error
+			name:         "should return 500 internal server error when the service returns an error",
+			query:        "?field=weight",
+			field:        internal.NumericWeight,
+			serviceError: errors.New("error"),
+			handlerError: errors.New("internal error"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
@@ -3,11 +3,14 @@ package handler
 import (
 	"app/internal"
 	"app/internal/service"
+	"app/platform/web/response"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 
 	"testing"
 
@@ -38,7 +41,7 @@ func Setup() *TestCaseServerSetup {
 	server := gin.New()
 	mockContext := mock.AnythingOfType("*gin.Context")
 	mockService := service.MockService{}
-	handler := NewHandlerVehicle(&mockService)
+	handler := NewHandlerVehicle(&mockService, nil)
 
 	return &TestCaseServerSetup{
 		server:      server,
@@ -52,12 +55,37 @@ func Setup() *TestCaseServerSetup {
 type TestCaseHttpSetup struct {
 	isErrorResponse    bool
 	expectedStatusCode int
+	expectedErrorCode  string
 	expectedHeaders    http.Header
 	expectedResponse   []byte
 	req                *http.Request
 	res                *httptest.ResponseRecorder
 }
 
+// problemResponse is a function that returns the problem details the handler is expected to respond
+// - it also sets the problem details content type in the expected headers
+func problemResponse(h *TestCaseHttpSetup, detail string) map[string]interface{} {
+	h.expectedHeaders = http.Header{
+		"Content-Type": []string{response.ContentTypeProblem},
+	}
+	return map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(h.expectedStatusCode),
+		"status": h.expectedStatusCode,
+		"detail": detail,
+		"code":   h.expectedErrorCode,
+	}
+}
+
+// vehiclesResponseJSON is a function that returns the response representation of the vehicles
+func vehiclesResponseJSON(v []internal.Vehicle) []VehicleResponseJSON {
+	r := make([]VehicleResponseJSON, 0, len(v))
+	for _, vh := range v {
+		r = append(r, NewVehicleResponseJSON(vh))
+	}
+	return r
+}
+
 // FindByColorAndYearTestCase is a struct that represents a test case for FindByColorAndYear
 type FindByColorAndYearTestCase struct {
 	setup            *TestCaseServerSetup
@@ -65,7 +93,10 @@ type FindByColorAndYearTestCase struct {
 	color            string
 	year             interface{}
 	successMessage   string
-	returnedVehicles map[int]internal.Vehicle
+	page             internal.PageQuery
+	query            string
+	returnedVehicles []internal.Vehicle
+	returnedTotal    int
 	serviceError     error
 	handlerError     error
 	mockOnCalled     bool
@@ -84,28 +115,32 @@ func (tc *FindByColorAndYearTestCase) Arrange() {
 	if !tc.httpSetup.isErrorResponse {
 		expectedResponse = map[string]interface{}{
 			"message": tc.successMessage,
-			"data":    tc.returnedVehicles,
+			"data":    vehiclesResponseJSON(tc.returnedVehicles),
+			"total":   tc.returnedTotal,
 		}
 	} else {
-		expectedResponse = map[string]interface{}{
-			"status":  http.StatusText(tc.httpSetup.expectedStatusCode),
-			"message": tc.handlerError.Error(),
-		}
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
 	}
 	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
 
-	tc.setup.mockService.On("FindByColorAndYear", tc.color, tc.year).Return(tc.returnedVehicles, tc.serviceError)
+	tc.setup.mockService.On("FindPage", tc.filter(), tc.page).Return(internal.VehiclePage{Vehicles: tc.returnedVehicles, Total: tc.returnedTotal}, tc.serviceError)
 
-	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/color/%s/year/%v", basePath, tc.color, tc.year), nil)
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/color/%s/year/%v%s", basePath, tc.color, tc.year, tc.query), nil)
 	tc.httpSetup.res = httptest.NewRecorder()
 }
 
+// filter is a method that returns the filter the handler is expected to build
+func (tc *FindByColorAndYearTestCase) filter() internal.VehicleFilter {
+	year, _ := tc.year.(int)
+	return internal.VehicleFilter{Color: &tc.color, YearMin: &year, YearMax: &year}
+}
+
 // Assert is a method that asserts the test case
 func (tc *FindByColorAndYearTestCase) Assert(t *testing.T) {
 	if tc.mockOnCalled {
-		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindByColorAndYear", tc.color, tc.year))
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindPage", tc.filter(), tc.page))
 	} else {
-		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindByColorAndYear", tc.color, tc.year))
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything))
 	}
 	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
 	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
@@ -116,13 +151,13 @@ func TestHandler_FindByColorAndYear(t *testing.T) {
 	// Create test cases
 	testCases := []FindByColorAndYearTestCase{
 		{
-			// This test evaluates that FindByColorAndYear returns 200 ok and a map of vehicles that match the color and fabrication year
-			name:           "should return 200 ok and a map of vehicles that match the color and fabrication year",
+			// This test evaluates that FindByColorAndYear returns 200 ok and the vehicles that match the color and fabrication year
+			name:           "should return 200 ok and the vehicles that match the color and fabrication year",
 			color:          "Red",
 			year:           2010,
 			successMessage: "vehicles found",
-			returnedVehicles: map[int]internal.Vehicle{
-				1: {
+			returnedVehicles: []internal.Vehicle{
+				{
 					Id: 1,
 					VehicleAttributes: internal.VehicleAttributes{
 						Brand:           "Ford",
@@ -143,11 +178,43 @@ func TestHandler_FindByColorAndYear(t *testing.T) {
 					},
 				},
 			},
-			mockOnCalled: true,
+			returnedTotal: 1,
+			mockOnCalled:  true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that FindByColorAndYear passes the sort of the query to the service
+			name:           "should return 200 ok and the vehicles sorted as the query asks",
+			color:          "Red",
+			year:           2010,
+			query:          "?sort=-weight,brand",
+			page:           internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByWeight, Desc: true}, {Field: internal.SortByBrand}}},
+			successMessage: "vehicles found",
+			returnedVehicles: []internal.Vehicle{
+				{Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Red", FabricationYear: 2010, Weight: 1100}},
+				{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Color: "Red", FabricationYear: 2010, Weight: 1000}},
+			},
+			returnedTotal: 2,
+			mockOnCalled:  true,
 			httpSetup: &TestCaseHttpSetup{
 				expectedStatusCode: http.StatusOK,
 			},
 		},
+		{
+			// This test evaluates that FindByColorAndYear returns a 400 bad request error when the sort field is unknown
+			name:         "should return a 400 bad request error when the sort field is unknown",
+			color:        "Red",
+			year:         2010,
+			query:        "?sort=price",
+			handlerError: errors.New("invalid sort"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
 		{
 			// This test evaluates that FindByColorAndYear returns a 400 bad request error when the year is invalid
 			name:         "should return a 400 bad request error when the year is invalid",
@@ -156,6 +223,21 @@ func TestHandler_FindByColorAndYear(t *testing.T) {
 			handlerError: errors.New("invalid year"),
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that FindByColorAndYear names the year of the route when the service rejects the range
+			name:         "should return 400 bad request error naming the year when the service rejects the range",
+			color:        "Red",
+			year:         -1,
+			serviceError: &internal.RangeError{Err: internal.ErrServiceInvalidFind, Param: "year_min", Value: -1, Reason: "must not be negative"},
+			handlerError: errors.New("invalid find: year must not be negative, got -1"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_find",
 				expectedStatusCode: http.StatusBadRequest,
 			},
 		},
@@ -169,6 +251,7 @@ func TestHandler_FindByColorAndYear(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
 				expectedStatusCode: http.StatusInternalServerError,
 			},
 		},
@@ -190,7 +273,10 @@ type FindByBrandAndYearRangeTestCase struct {
 	startYear        interface{}
 	endYear          interface{}
 	successMessage   string
-	returnedVehicles map[int]internal.Vehicle
+	page             internal.PageQuery
+	query            string
+	returnedVehicles []internal.Vehicle
+	returnedTotal    int
 	serviceError     error
 	handlerError     error
 	mockOnCalled     bool
@@ -209,28 +295,33 @@ func (tc *FindByBrandAndYearRangeTestCase) Arrange() {
 	if !tc.httpSetup.isErrorResponse {
 		expectedResponse = map[string]interface{}{
 			"message": tc.successMessage,
-			"data":    tc.returnedVehicles,
+			"data":    vehiclesResponseJSON(tc.returnedVehicles),
+			"total":   tc.returnedTotal,
 		}
 	} else {
-		expectedResponse = map[string]interface{}{
-			"status":  http.StatusText(tc.httpSetup.expectedStatusCode),
-			"message": tc.handlerError.Error(),
-		}
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
 	}
 	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
 
-	tc.setup.mockService.On("FindByBrandAndYearRange", tc.brand, tc.startYear, tc.endYear).Return(tc.returnedVehicles, tc.serviceError)
+	tc.setup.mockService.On("FindPage", tc.filter(), tc.page).Return(internal.VehiclePage{Vehicles: tc.returnedVehicles, Total: tc.returnedTotal}, tc.serviceError)
 
-	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/brand/%s/between/%v/%v", basePath, tc.brand, tc.startYear, tc.endYear), nil)
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/brand/%s/between/%v/%v%s", basePath, tc.brand, tc.startYear, tc.endYear, tc.query), nil)
 	tc.httpSetup.res = httptest.NewRecorder()
 }
 
+// filter is a method that returns the filter the handler is expected to build
+func (tc *FindByBrandAndYearRangeTestCase) filter() internal.VehicleFilter {
+	startYear, _ := tc.startYear.(int)
+	endYear, _ := tc.endYear.(int)
+	return internal.VehicleFilter{Brand: &tc.brand, YearMin: &startYear, YearMax: &endYear}
+}
+
 // Assert is a method that asserts the test case
 func (tc *FindByBrandAndYearRangeTestCase) Assert(t *testing.T) {
 	if tc.mockOnCalled {
-		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindByBrandAndYearRange", tc.brand, tc.startYear, tc.endYear))
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindPage", tc.filter(), tc.page))
 	} else {
-		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindByBrandAndYearRange", tc.brand, tc.startYear, tc.endYear))
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything))
 	}
 	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
 	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
@@ -241,14 +332,14 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 	// Create test cases
 	testCases := []FindByBrandAndYearRangeTestCase{
 		{
-			// This test evaluates that FindByBrandAndYearRange returns 200 ok and a map of vehicles that match the brand and fabrication year range
-			name:           "should return 200 ok and a map of vehicles that match the brand and fabrication year range",
+			// This test evaluates that FindByBrandAndYearRange returns 200 ok and the vehicles that match the brand and fabrication year range
+			name:           "should return 200 ok and the vehicles that match the brand and fabrication year range",
 			brand:          "Ford",
 			startYear:      2010,
 			endYear:        2012,
 			successMessage: "vehicles found",
-			returnedVehicles: map[int]internal.Vehicle{
-				1: {
+			returnedVehicles: []internal.Vehicle{
+				{
 					Id: 1,
 					VehicleAttributes: internal.VehicleAttributes{
 						Brand:           "Ford",
@@ -268,7 +359,7 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 						},
 					},
 				},
-				2: {
+				{
 					Id: 2,
 					VehicleAttributes: internal.VehicleAttributes{
 						Brand:           "Ford",
@@ -289,7 +380,8 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 					},
 				},
 			},
-			mockOnCalled: true,
+			returnedTotal: 2,
+			mockOnCalled:  true,
 			httpSetup: &TestCaseHttpSetup{
 				expectedStatusCode: http.StatusOK,
 			},
@@ -302,6 +394,7 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 			handlerError: errors.New("invalid start_year"),
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
 				expectedStatusCode: http.StatusBadRequest,
 			},
 		},
@@ -314,6 +407,22 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 			handlerError: errors.New("invalid end_year"),
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that FindByBrandAndYearRange returns 400 bad request error naming the parameters of the route when the service rejects the range
+			name:         "should return 400 bad request error naming the parameters of the route when the service rejects the range",
+			brand:        "Ford",
+			startYear:    2012,
+			endYear:      2010,
+			serviceError: &internal.RangeError{Err: internal.ErrServiceInvalidFind, Param: "year_min", Value: 2012, MaxParam: "year_max", MaxValue: 2010},
+			handlerError: errors.New("invalid find: start_year 2012 is greater than end_year 2010"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_find",
 				expectedStatusCode: http.StatusBadRequest,
 			},
 		},
@@ -328,6 +437,7 @@ func TestHandler_FindByBrandAndYearRange(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
 				expectedStatusCode: http.StatusInternalServerError,
 			},
 		},
@@ -369,10 +479,7 @@ func (tc *AverageMaxSpeedByBrandTestCase) Arrange() {
 			"data":    tc.returnedAverage,
 		}
 	} else {
-		expectedResponse = map[string]interface{}{
-			"status":  http.StatusText(tc.httpSetup.expectedStatusCode),
-			"message": tc.handlerError.Error(),
-		}
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
 	}
 	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
 
@@ -418,6 +525,7 @@ func TestHandler_AverageMaxSpeedByBrand(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "vehicles_not_found",
 				expectedStatusCode: http.StatusNotFound,
 			},
 		},
@@ -430,6 +538,7 @@ func TestHandler_AverageMaxSpeedByBrand(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
 				expectedStatusCode: http.StatusInternalServerError,
 			},
 		},
@@ -448,8 +557,11 @@ type AverageCapacityByBrandTestCase struct {
 	setup            *TestCaseServerSetup
 	name             string
 	brand            string
+	query            string
+	config           *ConfigHandlerVehicle
 	successMessage   string
-	returnedCapacity int
+	returnedCapacity internal.VehicleAverage
+	expectedData     interface{}
 	serviceError     error
 	handlerError     error
 	mockOnCalled     bool
@@ -459,6 +571,7 @@ type AverageCapacityByBrandTestCase struct {
 // Arrange is a method that sets up the test case
 func (tc *AverageCapacityByBrandTestCase) Arrange() {
 	tc.setup = Setup()
+	tc.setup.handler = NewHandlerVehicle(tc.setup.mockService, tc.config)
 	tc.setup.server.GET(basePath+"/average_capacity/brand/:brand", tc.setup.handler.AverageCapacityByBrand())
 
 	tc.httpSetup.expectedHeaders = http.Header{
@@ -468,19 +581,16 @@ func (tc *AverageCapacityByBrandTestCase) Arrange() {
 	if !tc.httpSetup.isErrorResponse {
 		expectedResponse = map[string]interface{}{
 			"message": tc.successMessage,
-			"data":    tc.returnedCapacity,
+			"data":    tc.expectedData,
 		}
 	} else {
-		expectedResponse = map[string]interface{}{
-			"status":  http.StatusText(tc.httpSetup.expectedStatusCode),
-			"message": tc.handlerError.Error(),
-		}
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
 	}
 	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
 
 	tc.setup.mockService.On("AverageCapacityByBrand", tc.brand).Return(tc.returnedCapacity, tc.serviceError)
 
-	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/average_capacity/brand/%s", basePath, tc.brand), nil)
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/average_capacity/brand/%s%s", basePath, tc.brand, tc.query), nil)
 	tc.httpSetup.res = httptest.NewRecorder()
 }
 
@@ -501,16 +611,83 @@ func TestHandler_AverageCapacityByBrand(t *testing.T) {
 	// Create test cases
 	testCases := []AverageCapacityByBrandTestCase{
 		{
-			// This test evaluates that AverageCapacityByBrand returns 200 ok and the average capacity of the vehicles that match the brand
-			name:             "should return 200 ok and the average capacity of the vehicles that match the brand",
+			// This test evaluates that AverageCapacityByBrand returns 200 ok and the exact average capacity along with the sample size
+			name:             "should return 200 ok and the exact average capacity along with the sample size",
+			brand:            "Ford",
+			successMessage:   "average capacity found",
+			returnedCapacity: internal.VehicleAverage{Value: 3.8, Count: 5},
+			expectedData:     AverageCapacityResponseJSON{Brand: "Ford", Average: 3.8, Count: 5},
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that AverageCapacityByBrand returns 200 ok and the average capacity rounded as configured
+			name:             "should return 200 ok and the average capacity rounded as configured",
 			brand:            "Ford",
+			config:           &ConfigHandlerVehicle{CapacityRounding: internal.Rounding{Mode: internal.RoundingHalfUp, Places: 2}},
 			successMessage:   "average capacity found",
-			returnedCapacity: 5,
+			returnedCapacity: internal.VehicleAverage{Value: 11.0 / 3.0, Count: 3},
+			expectedData:     AverageCapacityResponseJSON{Brand: "Ford", Average: 3.67, Count: 3},
 			mockOnCalled:     true,
 			httpSetup: &TestCaseHttpSetup{
 				expectedStatusCode: http.StatusOK,
 			},
 		},
+		{
+			// This test evaluates that AverageCapacityByBrand returns 200 ok and the truncated average capacity when the request asks for the integer format
+			name:             "should return 200 ok and the truncated average capacity when the request asks for the integer format",
+			brand:            "Ford",
+			query:            "?format=integer",
+			config:           &ConfigHandlerVehicle{CapacityRounding: internal.Rounding{Mode: internal.RoundingHalfUp}},
+			successMessage:   "average capacity found",
+			returnedCapacity: internal.VehicleAverage{Value: 3.8, Count: 5},
+			expectedData:     3,
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that AverageCapacityByBrand returns 200 ok and the truncated average capacity when the integer format is the default
+			name:             "should return 200 ok and the truncated average capacity when the integer format is the default",
+			brand:            "Ford",
+			config:           &ConfigHandlerVehicle{CapacityFormat: AverageCapacityFormatInteger},
+			successMessage:   "average capacity found",
+			returnedCapacity: internal.VehicleAverage{Value: 3.8, Count: 5},
+			expectedData:     3,
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that AverageCapacityByBrand returns 200 ok and the float format when the request asks for it over the default
+			name:             "should return 200 ok and the float format when the request asks for it over the default",
+			brand:            "Ford",
+			query:            "?format=float",
+			config:           &ConfigHandlerVehicle{CapacityFormat: AverageCapacityFormatInteger},
+			successMessage:   "average capacity found",
+			returnedCapacity: internal.VehicleAverage{Value: 3.8, Count: 5},
+			expectedData:     AverageCapacityResponseJSON{Brand: "Ford", Average: 3.8, Count: 5},
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that AverageCapacityByBrand returns 400 bad request error when the format is unknown
+			name:         "should return 400 bad request error when the format is unknown",
+			brand:        "Ford",
+			query:        "?format=decimal",
+			handlerError: errors.New("invalid format"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
 		{
 			// This test evaluates that AverageCapacityByBrand returns 404 not found error when there are no vehicles that match the brand
 			name:         "should return 404 not found error when there are no vehicles that match the brand",
@@ -520,6 +697,7 @@ func TestHandler_AverageCapacityByBrand(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "vehicles_not_found",
 				expectedStatusCode: http.StatusNotFound,
 			},
 		},
@@ -532,6 +710,7 @@ func TestHandler_AverageCapacityByBrand(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
 				expectedStatusCode: http.StatusInternalServerError,
 			},
 		},
@@ -553,7 +732,8 @@ type SearchByWeightRangeTestCase struct {
 	toWeight         interface{}
 	ok               bool
 	successMessage   string
-	returnedVehicles map[int]internal.Vehicle
+	returnedVehicles []internal.Vehicle
+	returnedTotal    int
 	serviceError     error
 	handlerError     error
 	mockOnCalled     bool
@@ -572,60 +752,53 @@ func (tc *SearchByWeightRangeTestCase) Arrange() {
 	if !tc.httpSetup.isErrorResponse {
 		expectedResponse = map[string]interface{}{
 			"message": tc.successMessage,
-			"data":    tc.returnedVehicles,
+			"data":    vehiclesResponseJSON(tc.returnedVehicles),
+			"total":   tc.returnedTotal,
 		}
 	} else {
-		expectedResponse = map[string]interface{}{
-			"status":  http.StatusText(tc.httpSetup.expectedStatusCode),
-			"message": tc.handlerError.Error(),
-		}
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
 	}
 	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
 
-	var query internal.SearchQuery
-	var target string
-
+	target := fmt.Sprintf("%s/weight", basePath)
 	if tc.ok {
-		if tc.fromWeight == nil {
-			query = internal.SearchQuery{
-				FromWeight: 0.0,
-				ToWeight:   tc.toWeight.(float64),
-			}
-			target = fmt.Sprintf("%s/weight?weight_min=%v", basePath, tc.fromWeight)
-		} else if tc.toWeight == nil {
-			query = internal.SearchQuery{
-				FromWeight: tc.fromWeight.(float64),
-				ToWeight:   0.0,
-			}
-			target = fmt.Sprintf("%s/weight?weight_max=%v", basePath, tc.toWeight)
-		} else {
-			query = internal.SearchQuery{
-				FromWeight: tc.fromWeight.(float64),
-				ToWeight:   tc.toWeight.(float64),
-			}
-			target = fmt.Sprintf("%s/weight?weight_min=%v&weight_max=%v", basePath, tc.fromWeight, tc.toWeight)
+		query := url.Values{}
+		if tc.fromWeight != nil {
+			query.Set("weight_min", fmt.Sprint(tc.fromWeight))
 		}
-	} else {
-		target = fmt.Sprintf("%s/weight", basePath)
+		if tc.toWeight != nil {
+			query.Set("weight_max", fmt.Sprint(tc.toWeight))
+		}
+		target += "?" + query.Encode()
 	}
 
-	tc.setup.mockService.On("SearchByWeightRange", query, tc.ok).Return(tc.returnedVehicles, tc.serviceError)
+	tc.setup.mockService.On("FindPage", tc.filter(), internal.PageQuery{}).Return(internal.VehiclePage{Vehicles: tc.returnedVehicles, Total: tc.returnedTotal}, tc.serviceError)
 
 	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, target, nil)
 	tc.httpSetup.res = httptest.NewRecorder()
 }
 
+// filter is a method that returns the filter the handler is expected to build
+// - each bound given is set, so the range is half-open when only one is given
+func (tc *SearchByWeightRangeTestCase) filter() (f internal.VehicleFilter) {
+	if !tc.ok {
+		return
+	}
+	if fromWeight, ok := tc.fromWeight.(float64); ok {
+		f.WeightMin = &fromWeight
+	}
+	if toWeight, ok := tc.toWeight.(float64); ok {
+		f.WeightMax = &toWeight
+	}
+	return
+}
+
 // Assert is a method that asserts the test case
 func (tc *SearchByWeightRangeTestCase) Assert(t *testing.T) {
 	if tc.mockOnCalled {
-		query := internal.SearchQuery{
-			FromWeight: tc.fromWeight.(float64),
-			ToWeight:   tc.toWeight.(float64),
-		}
-		assert.True(t, tc.setup.mockService.AssertCalled(t, "SearchByWeightRange", query, tc.ok))
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindPage", tc.filter(), internal.PageQuery{}))
 	} else {
-		var query internal.SearchQuery
-		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "SearchByWeightRange", query, tc.ok))
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything))
 	}
 	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
 	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
@@ -643,8 +816,8 @@ func TestHandler_SearchByWeightRange(t *testing.T) {
 			toWeight:       1050.0,
 			ok:             true,
 			successMessage: "vehicles found",
-			returnedVehicles: map[int]internal.Vehicle{
-				1: {
+			returnedVehicles: []internal.Vehicle{
+				{
 					Id: 1,
 					VehicleAttributes: internal.VehicleAttributes{
 						Brand:           "Ford",
@@ -665,11 +838,61 @@ func TestHandler_SearchByWeightRange(t *testing.T) {
 					},
 				},
 			},
-			mockOnCalled: true,
+			returnedTotal: 1,
+			mockOnCalled:  true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that SearchByWeightRange requests a half-open range when only weight_min is given
+			name:           "should request a half-open range when only weight_min is given",
+			fromWeight:     1000.0,
+			ok:             true,
+			successMessage: "vehicles found",
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that SearchByWeightRange requests a half-open range when only weight_max is given
+			name:           "should request a half-open range when only weight_max is given",
+			toWeight:       1050.0,
+			ok:             true,
+			successMessage: "vehicles found",
+			mockOnCalled:   true,
 			httpSetup: &TestCaseHttpSetup{
 				expectedStatusCode: http.StatusOK,
 			},
 		},
+		{
+			// This test evaluates that SearchByWeightRange returns 400 bad request error when weight_min is not a number
+			name:         "should return 400 bad request error when weight_min is not a number",
+			fromWeight:   "heavy",
+			ok:           true,
+			handlerError: errors.New("invalid weight_min"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that SearchByWeightRange returns 400 bad request error naming the parameter when the service rejects the range
+			name:         "should return 400 bad request error naming the parameter when the service rejects the range",
+			fromWeight:   1050.0,
+			toWeight:     1000.0,
+			ok:           true,
+			serviceError: &internal.RangeError{Err: internal.ErrServiceInvalidSearch, Param: "weight_min", Value: 1050.0, MaxParam: "weight_max", MaxValue: 1000.0},
+			handlerError: errors.New("invalid search: weight_min 1050 is greater than weight_max 1000"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_search",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
 		{
 			// This test evaluates that SearchByWeightRange returns 500 internal server error when the service returns an error
 			name:         "should return 500 internal server error when the service returns an error",
@@ -681,6 +904,821 @@ func TestHandler_SearchByWeightRange(t *testing.T) {
 			mockOnCalled: true,
 			httpSetup: &TestCaseHttpSetup{
 				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// CreateTestCase is a struct that represents a test case for Create
+type CreateTestCase struct {
+	setup          *TestCaseServerSetup
+	name           string
+	body           string
+	vehicle        internal.Vehicle
+	successMessage string
+	serviceError   error
+	handlerError   error
+	expectedFields []FieldErrorJSON
+	mockOnCalled   bool
+	httpSetup      *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *CreateTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.POST(basePath, tc.setup.handler.Create())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		created := tc.vehicle
+		created.Id = 1
+		expectedResponse = map[string]interface{}{
+			"message": tc.successMessage,
+			"data":    NewVehicleResponseJSON(created),
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+		if tc.expectedFields != nil {
+			expectedResponse.(map[string]interface{})["errors"] = tc.expectedFields
+		}
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("Save", &tc.vehicle).Run(func(args mock.Arguments) {
+		args.Get(0).(*internal.Vehicle).Id = 1
+	}).Return(tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodPost, basePath, strings.NewReader(tc.body))
+	tc.httpSetup.req.Header.Set("Content-Type", "application/json")
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *CreateTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertNumberOfCalls(t, "Save", 1))
+	} else {
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "Save", mock.Anything))
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Create is a method that tests the Create handler
+func TestHandler_Create(t *testing.T) {
+	// Create test cases
+	testCases := []CreateTestCase{
+		{
+			// This test evaluates that Create returns 201 created and the created vehicle
+			name:           "should return 201 created and the created vehicle",
+			body:           `{"brand":"Ford","model":"Fiesta","registration":"ABC-1234","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"Gasoline","transmission":"Manual","weight":1000,"height":1.5,"length":4,"width":1.8}`,
+			successMessage: "vehicle created",
+			vehicle: internal.Vehicle{
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand:           "Ford",
+					Model:           "Fiesta",
+					Registration:    "ABC-1234",
+					Color:           "Red",
+					FabricationYear: 2010,
+					Capacity:        5,
+					MaxSpeed:        180,
+					FuelType:        "Gasoline",
+					Transmission:    "Manual",
+					Weight:          1000,
+					Dimensions: internal.Dimensions{
+						Height: 1.5,
+						Length: 4,
+						Width:  1.8,
+					},
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusCreated,
+			},
+		},
+		{
+			// This test evaluates that Create returns 400 bad request error when the body is invalid
+			name:         "should return 400 bad request error when the body is invalid",
+			body:         `{"brand":`,
+			handlerError: errors.New("invalid body"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Create returns 422 unprocessable entity and the fields that break a domain rule
+			name:    "should return 422 unprocessable entity and the fields that break a domain rule",
+			body:    `{"brand":"Ford","registration":"0"}`,
+			vehicle: internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Registration: "0"}},
+			serviceError: &internal.VehicleValidationError{Fields: []internal.VehicleFieldError{
+				{Field: "registration", Rule: internal.ValidationRuleUnique, Message: `"0" is already used by vehicle 1`},
+			}},
+			handlerError:   errors.New("invalid vehicle"),
+			expectedFields: []FieldErrorJSON{{Field: "registration", Rule: "unique", Message: `"0" is already used by vehicle 1`}},
+			mockOnCalled:   true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_vehicle",
+				expectedStatusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			// This test evaluates that Create returns 500 internal server error when the service returns an error
+			name:         "should return 500 internal server error when the service returns an error",
+			body:         `{"brand":"Ford"}`,
+			vehicle:      internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}},
+			serviceError: errors.New("error"),
+			handlerError: errors.New("internal error"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// PatchTestCase is a struct that represents a test case for Patch
+type PatchTestCase struct {
+	setup           *TestCaseServerSetup
+	name            string
+	id              interface{}
+	body            string
+	patch           internal.VehiclePatch
+	successMessage  string
+	returnedVehicle internal.Vehicle
+	serviceError    error
+	handlerError    error
+	mockOnCalled    bool
+	httpSetup       *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *PatchTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.PATCH(basePath+"/:id", tc.setup.handler.Patch())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": tc.successMessage,
+			"data":    NewVehicleResponseJSON(tc.returnedVehicle),
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("Patch", tc.id, tc.patch).Return(tc.returnedVehicle, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%v", basePath, tc.id), strings.NewReader(tc.body))
+	tc.httpSetup.req.Header.Set("Content-Type", "application/json")
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *PatchTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "Patch", tc.id, tc.patch))
+	} else {
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "Patch", tc.id, tc.patch))
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Patch is a method that tests the Patch handler
+func TestHandler_Patch(t *testing.T) {
+	color := "Black"
+
+	// Create test cases
+	testCases := []PatchTestCase{
+		{
+			// This test evaluates that Patch returns 200 ok and the updated vehicle
+			name:           "should return 200 ok and the updated vehicle",
+			id:             1,
+			body:           `{"color":"Black"}`,
+			patch:          internal.VehiclePatch{Color: &color},
+			successMessage: "vehicle updated",
+			returnedVehicle: internal.Vehicle{
+				Id: 1,
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand: "Ford",
+					Model: "Fiesta",
+					Color: "Black",
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Patch returns 400 bad request error when the id is invalid
+			name:         "should return 400 bad request error when the id is invalid",
+			id:           "a",
+			body:         `{"color":"Black"}`,
+			handlerError: errors.New("invalid id"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Patch returns 404 not found error when the vehicle does not exist
+			name:         "should return 404 not found error when the vehicle does not exist",
+			id:           10,
+			body:         `{"color":"Black"}`,
+			patch:        internal.VehiclePatch{Color: &color},
+			serviceError: internal.ErrRepositoryNotFound,
+			handlerError: errors.New("vehicle not found"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "vehicle_not_found",
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// DeleteTestCase is a struct that represents a test case for Delete
+type DeleteTestCase struct {
+	setup        *TestCaseServerSetup
+	name         string
+	id           interface{}
+	serviceError error
+	handlerError error
+	mockOnCalled bool
+	httpSetup    *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *DeleteTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.DELETE(basePath+"/:id", tc.setup.handler.Delete())
+
+	tc.httpSetup.expectedHeaders = http.Header{}
+	if tc.httpSetup.isErrorResponse {
+		tc.httpSetup.expectedHeaders = http.Header{
+			"Content-Type": []string{"application/json"},
+		}
+		tc.httpSetup.expectedResponse, _ = json.Marshal(problemResponse(tc.httpSetup, tc.handlerError.Error()))
+	}
+
+	tc.setup.mockService.On("Delete", tc.id).Return(tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%v", basePath, tc.id), nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *DeleteTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "Delete", tc.id))
+	} else {
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "Delete", tc.id))
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	if tc.httpSetup.isErrorResponse {
+		assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+	} else {
+		assert.Empty(t, tc.httpSetup.res.Body.String())
+	}
+}
+
+// TestHandler_Delete is a method that tests the Delete handler
+func TestHandler_Delete(t *testing.T) {
+	// Create test cases
+	testCases := []DeleteTestCase{
+		{
+			// This test evaluates that Delete returns 204 no content when the vehicle is deleted
+			name:         "should return 204 no content when the vehicle is deleted",
+			id:           1,
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusNoContent,
+			},
+		},
+		{
+			// This test evaluates that Delete returns 404 not found error when the vehicle does not exist
+			name:         "should return 404 not found error when the vehicle does not exist",
+			id:           10,
+			serviceError: internal.ErrRepositoryNotFound,
+			handlerError: errors.New("vehicle not found"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "vehicle_not_found",
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// FindByIDTestCase is a struct that represents a test case for FindByID
+type FindByIDTestCase struct {
+	setup           *TestCaseServerSetup
+	name            string
+	id              interface{}
+	successMessage  string
+	returnedVehicle internal.Vehicle
+	serviceError    error
+	handlerError    error
+	mockOnCalled    bool
+	httpSetup       *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindByIDTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.GET(basePath+"/:id", tc.setup.handler.FindByID())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": tc.successMessage,
+			"data":    NewVehicleResponseJSON(tc.returnedVehicle),
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("FindByID", tc.id).Return(tc.returnedVehicle, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s/%v", basePath, tc.id), nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindByIDTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindByID", tc.id))
+	} else {
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindByID", tc.id))
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_FindByID is a method that tests the FindByID handler
+func TestHandler_FindByID(t *testing.T) {
+	// Create test cases
+	testCases := []FindByIDTestCase{
+		{
+			// This test evaluates that FindByID returns 200 ok and the vehicle
+			name:           "should return 200 ok and the vehicle",
+			id:             1,
+			successMessage: "vehicle found",
+			returnedVehicle: internal.Vehicle{
+				Id: 1,
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand: "Ford",
+					Model: "Fiesta",
+					Color: "Red",
+				},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that FindByID returns 400 bad request error when the id is invalid
+			name:         "should return 400 bad request error when the id is invalid",
+			id:           "a",
+			handlerError: errors.New("invalid id"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that FindByID returns 404 not found error when the vehicle does not exist
+			name:         "should return 404 not found error when the vehicle does not exist",
+			id:           10,
+			serviceError: internal.ErrRepositoryNotFound,
+			handlerError: errors.New("vehicle not found"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "vehicle_not_found",
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// FindByRegistrationTestCase is a struct that represents a test case for FindByRegistration
+type FindByRegistrationTestCase struct {
+	setup            *TestCaseServerSetup
+	name             string
+	registration     string
+	successMessage   string
+	returnedVehicles map[int]internal.Vehicle
+	expectedVehicles []internal.Vehicle
+	serviceError     error
+	handlerError     error
+	httpSetup        *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindByRegistrationTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.GET(basePath+"/registration/:registration", tc.setup.handler.FindByRegistration())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": tc.successMessage,
+			"data":    vehiclesResponseJSON(tc.expectedVehicles),
+			"total":   len(tc.expectedVehicles),
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("FindByRegistration", tc.registration).Return(tc.returnedVehicles, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, basePath+"/registration/"+tc.registration, nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindByRegistrationTestCase) Assert(t *testing.T) {
+	assert.True(t, tc.setup.mockService.AssertCalled(t, "FindByRegistration", tc.registration))
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_FindByRegistration is a method that tests the FindByRegistration handler
+func TestHandler_FindByRegistration(t *testing.T) {
+	first := internal.Vehicle{Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Registration: "0"}}
+	second := internal.Vehicle{Id: 7, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Registration: "0"}}
+
+	// Create test cases
+	testCases := []FindByRegistrationTestCase{
+		{
+			// This test evaluates that FindByRegistration returns 200 ok and every vehicle sharing the registration, sorted by id
+			name:             "should return 200 ok and every vehicle sharing the registration sorted by id",
+			registration:     "0",
+			successMessage:   "vehicles found",
+			returnedVehicles: map[int]internal.Vehicle{7: second, 3: first},
+			expectedVehicles: []internal.Vehicle{first, second},
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that FindByRegistration returns 404 not found error when no vehicle matches
+			name:             "should return 404 not found error when no vehicle matches",
+			registration:     "XYZ-0000",
+			returnedVehicles: map[int]internal.Vehicle(nil),
+			serviceError:     internal.ErrRepositoryNotFound,
+			handlerError:     errors.New("vehicle not found"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "vehicle_not_found",
+				expectedStatusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.setup.server.ServeHTTP(testCase.httpSetup.res, testCase.httpSetup.req)
+		testCase.Assert(t)
+	}
+}
+
+// FindTestCase is a struct that represents a test case for Find
+type FindTestCase struct {
+	setup            *TestCaseServerSetup
+	name             string
+	query            string
+	filter           internal.VehicleFilter
+	page             internal.PageQuery
+	successMessage   string
+	returnedVehicles []internal.Vehicle
+	returnedTotal    int
+	returnedMore     bool
+	nextPageToken    string
+	expectedData     interface{}
+	serviceError     error
+	handlerError     error
+	mockOnCalled     bool
+	httpSetup        *TestCaseHttpSetup
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.server.GET(basePath, tc.setup.handler.Find())
+
+	tc.httpSetup.expectedHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	var expectedResponse interface{}
+	if !tc.httpSetup.isErrorResponse {
+		expectedResponse = map[string]interface{}{
+			"message": tc.successMessage,
+			"data":    vehiclesResponseJSON(tc.returnedVehicles),
+			"total":   tc.returnedTotal,
+		}
+		if tc.expectedData != nil {
+			expectedResponse.(map[string]interface{})["data"] = tc.expectedData
+		}
+		if tc.nextPageToken != "" {
+			expectedResponse.(map[string]interface{})["next_page_token"] = tc.nextPageToken
+		}
+	} else {
+		expectedResponse = problemResponse(tc.httpSetup, tc.handlerError.Error())
+	}
+	tc.httpSetup.expectedResponse, _ = json.Marshal(expectedResponse)
+
+	tc.setup.mockService.On("FindPage", tc.filter, tc.page).Return(internal.VehiclePage{Vehicles: tc.returnedVehicles, Total: tc.returnedTotal, More: tc.returnedMore}, tc.serviceError)
+
+	tc.httpSetup.req = httptest.NewRequest(http.MethodGet, basePath+tc.query, nil)
+	tc.httpSetup.res = httptest.NewRecorder()
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindTestCase) Assert(t *testing.T) {
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockService.AssertCalled(t, "FindPage", tc.filter, tc.page))
+	} else {
+		assert.True(t, tc.setup.mockService.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything))
+	}
+	assert.Equal(t, tc.httpSetup.expectedStatusCode, tc.httpSetup.res.Code)
+	assert.Equal(t, tc.httpSetup.expectedHeaders, tc.httpSetup.res.Header())
+	assert.JSONEq(t, string(tc.httpSetup.expectedResponse), tc.httpSetup.res.Body.String())
+}
+
+// TestHandler_Find is a method that tests the Find handler
+func TestHandler_Find(t *testing.T) {
+	brand := "Ford"
+	fuelType := "Diesel"
+	yearMin := 2010
+	speedMin := 150.5
+
+	// Create test cases
+	testCases := []FindTestCase{
+		{
+			// This test evaluates that Find returns 200 ok and the vehicles that match every criteria of the query
+			name:           "should return 200 ok and the vehicles that match the query",
+			query:          "?brand=Ford&fuel_type=Diesel&year_min=2010&speed_min=150.5",
+			filter:         internal.VehicleFilter{Brand: &brand, FuelType: &fuelType, YearMin: &yearMin, SpeedMin: &speedMin},
+			successMessage: "vehicles found",
+			returnedVehicles: []internal.Vehicle{
+				{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "Diesel", FabricationYear: 2012, MaxSpeed: 180}},
+			},
+			returnedTotal: 1,
+			mockOnCalled:  true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find returns 200 ok and every vehicle when there is no query
+			name:           "should return 200 ok and every vehicle when there is no query",
+			filter:         internal.VehicleFilter{},
+			successMessage: "vehicles found",
+			returnedVehicles: []internal.Vehicle{
+				{Id: 1},
+				{Id: 2},
+			},
+			returnedTotal: 2,
+			mockOnCalled:  true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find returns the token of the next page when there are more vehicles
+			name:             "should return 200 ok and the next page token when there are more vehicles",
+			query:            "?brand=Ford&sort=-year&limit=2",
+			filter:           internal.VehicleFilter{Brand: &brand},
+			page:             internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}}, Limit: 2},
+			successMessage:   "vehicles found",
+			returnedVehicles: []internal.Vehicle{{Id: 3, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2015}}, {Id: 1, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2012}}},
+			returnedTotal:    5,
+			returnedMore:     true,
+			nextPageToken:    "eyJzb3J0IjoiLXllYXIiLCJrZXkiOlsyMDEyXSwiaWQiOjF9",
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find seeks past the last vehicle of a page token and omits the token on the last page
+			name:             "should return 200 ok and no next page token on the last page",
+			query:            "?brand=Ford&sort=-year&limit=2&page_token=eyJzb3J0IjoiLXllYXIiLCJrZXkiOlsyMDEyXSwiaWQiOjF9",
+			filter:           internal.VehicleFilter{Brand: &brand},
+			page:             internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}}, After: &internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{FabricationYear: 2012}}, Limit: 2},
+			successMessage:   "vehicles found",
+			returnedVehicles: []internal.Vehicle{{Id: 2}},
+			returnedTotal:    5,
+			mockOnCalled:     true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find returns only the selected fields, nesting the dimensions
+			name:           "should return 200 ok and only the selected fields",
+			query:          "?brand=Ford&fields=id,brand,dimensions.height",
+			filter:         internal.VehicleFilter{Brand: &brand},
+			successMessage: "vehicles found",
+			returnedVehicles: []internal.Vehicle{
+				{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Red", Dimensions: internal.Dimensions{Height: 1.5, Width: 1.8}}},
+			},
+			returnedTotal: 1,
+			expectedData: []map[string]interface{}{
+				{"id": 1, "brand": "Ford", "dimensions": map[string]interface{}{"height": 1.5}},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find selects every nested field when their parent is given
+			name:           "should return 200 ok and every nested field when their parent is selected",
+			query:          "?fields=dimensions",
+			successMessage: "vehicles found",
+			returnedVehicles: []internal.Vehicle{
+				{Id: 1, VehicleAttributes: internal.VehicleAttributes{Dimensions: internal.Dimensions{Height: 1.5, Length: 4, Width: 1.8}}},
+			},
+			returnedTotal: 1,
+			expectedData: []map[string]interface{}{
+				{"dimensions": map[string]interface{}{"height": 1.5, "length": 4, "width": 1.8}},
+			},
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				expectedStatusCode: http.StatusOK,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when a selected field is unknown
+			name:         "should return 400 bad request when a selected field is unknown",
+			query:        "?fields=id,dimensions.weight",
+			handlerError: errors.New("unknown field dimensions.weight"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when the limit is out of range
+			name:         "should return 400 bad request when the limit is out of range",
+			query:        "?limit=0",
+			handlerError: errors.New("invalid limit"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when the page token is malformed
+			name:         "should return 400 bad request when the page token is malformed",
+			query:        "?page_token=abc",
+			handlerError: errors.New("invalid page_token"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when the page token was issued for another sort
+			name:         "should return 400 bad request when the page token was issued for another sort",
+			query:        "?sort=year&page_token=eyJzb3J0IjoiLXllYXIiLCJrZXkiOlsyMDEyXSwiaWQiOjF9",
+			handlerError: errors.New("invalid page_token"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when both offset and page token are given
+			name:         "should return 400 bad request when both offset and page token are given",
+			query:        "?sort=-year&offset=2&page_token=eyJzb3J0IjoiLXllYXIiLCJrZXkiOlsyMDEyXSwiaWQiOjF9",
+			handlerError: errors.New("invalid page_token"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when a bound is not a number
+			name:         "should return 400 bad request when a bound is not a number",
+			query:        "?year_min=abc",
+			handlerError: errors.New("invalid year_min"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when a parameter is unknown
+			name:         "should return 400 bad request when a parameter is unknown",
+			query:        "?brand=Ford&colour=Red",
+			handlerError: errors.New("unknown parameter colour"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 400 bad request when a parameter is repeated
+			name:         "should return 400 bad request when a parameter is repeated",
+			query:        "?brand=Ford&brand=Fiat",
+			handlerError: errors.New("repeated parameter brand"),
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "invalid_request",
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// This test evaluates that Find returns 500 internal server error when the service returns an error
+			name:         "should return 500 internal server error when the service returns an error",
+			filter:       internal.VehicleFilter{},
+			serviceError: errors.New("error"),
+			handlerError: errors.New("internal error"),
+			mockOnCalled: true,
+			httpSetup: &TestCaseHttpSetup{
+				isErrorResponse:    true,
+				expectedErrorCode:  "internal_error",
 				expectedStatusCode: http.StatusInternalServerError,
 			},
 		},
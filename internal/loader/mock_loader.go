@@ -0,0 +1,24 @@
+package loader
+
+import (
+	"app/internal"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLoader is a struct that represents a mock loader
+type MockLoader struct {
+	mock.Mock
+}
+
+// Load is a method that loads the vehicles
+func (m *MockLoader) Load() (v map[int]internal.Vehicle, err error) {
+	args := m.Called()
+	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
+}
+
+// Report is a method that returns the report of the last load
+func (m *MockLoader) Report() (r internal.LoadReport) {
+	args := m.Called()
+	return args.Get(0).(internal.LoadReport)
+}
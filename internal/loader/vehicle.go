@@ -0,0 +1,71 @@
+package loader
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FormatJSON is the format of a file with a JSON array of vehicles
+	FormatJSON = "json"
+	// FormatCSV is the format of a CSV file with a header row
+	FormatCSV = "csv"
+	// FormatJSONStream is the format of a JSON array of vehicles decoded one element at a time
+	FormatJSONStream = "json-stream"
+	// FormatNDJSON is the format of a file with one JSON vehicle per line
+	FormatNDJSON = "ndjson"
+	// FormatJSONL is an alias of FormatNDJSON, matching the .jsonl extension
+	FormatJSONL = "jsonl"
+)
+
+var (
+	// ErrLoaderUnknownFormat is an error that represents a file format with no loader
+	ErrLoaderUnknownFormat = errors.New("loader: unknown format")
+)
+
+// NewLoaderVehicle is a function that returns the loader for the given format
+// - an empty format is inferred from the file extension
+// - the policy applies to every loader, an empty one defaults to LoadPolicyWarn
+// - vv checks the domain rules of the vehicles, it can be nil
+// - match is the way the repository the vehicles are loaded into compares texts
+func NewLoaderVehicle(path string, format string, policy internal.LoadPolicy, vv internal.VehicleValidator, match internal.TextMatch) (ld internal.LoaderVehicle, err error) {
+	// default format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	// check policy
+	switch policy {
+	case "", internal.LoadPolicyFail, internal.LoadPolicySkip, internal.LoadPolicyWarn:
+	default:
+		err = internal.ErrLoaderUnknownPolicy
+		return
+	}
+
+	switch format {
+	case FormatJSON:
+		ld = NewLoaderVehicleJSON(path, policy, vv, match)
+	case FormatCSV:
+		ld = NewLoaderVehicleCSV(path, policy, vv, match)
+	case FormatJSONStream, FormatNDJSON, FormatJSONL:
+		ld = NewLoaderVehicleJSONStream(path, policy, vv, match)
+	default:
+		err = ErrLoaderUnknownFormat
+	}
+	return
+}
+
+// readyFile is a function that returns ErrNotReady if the file of the vehicles can not be opened, as the next load would fail
+func readyFile(path string) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		err = fmt.Errorf("%w: vehicles file: %s", internal.ErrNotReady, err.Error())
+		return
+	}
+	err = f.Close()
+	return
+}
@@ -0,0 +1,263 @@
+package loader
+
+import (
+	"app/internal"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrLoaderCSVMissingColumn is an error that represents a required column missing from the header
+	ErrLoaderCSVMissingColumn = errors.New("loader: missing csv column")
+	// ErrLoaderCSVUnknownColumn is an error that represents a header column that does not match any vehicle field
+	ErrLoaderCSVUnknownColumn = errors.New("loader: unknown csv column")
+)
+
+// RowError is an error that represents an invalid value in a row of a file
+type RowError struct {
+	// Line is the line number of the row, starting at 1
+	Line int
+	// Column is the name of the column with the invalid value
+	Column string
+	// Err is the underlying error
+	Err error
+}
+
+// Error is a method that returns the error message
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: column %s: %s", e.Line, e.Column, e.Err.Error())
+}
+
+// Unwrap is a method that returns the underlying error
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// NewLoaderVehicleCSV is a function that returns a new instance of LoaderVehicleCSV
+// - an empty policy defaults to LoadPolicyWarn
+// - vv can be nil, in which case the domain rules of the vehicles are not checked
+// - match is the way the repository the vehicles are loaded into compares texts, registrations are checked the same way
+func NewLoaderVehicleCSV(path string, policy internal.LoadPolicy, vv internal.VehicleValidator, match internal.TextMatch) *LoaderVehicleCSV {
+	// default policy
+	if policy == "" {
+		policy = internal.LoadPolicyWarn
+	}
+
+	return &LoaderVehicleCSV{
+		path:      path,
+		policy:    policy,
+		validator: vv,
+		match:     match,
+	}
+}
+
+// LoaderVehicleCSV is a struct that implements the LoaderVehicle, LoaderReporter and ReadinessChecker interfaces
+// - the first row is the header, its column names are the same as the VehicleJSON tags
+// - columns can be in any order, id is required to read the file
+// - a column missing from the header or repeated in it is reported on every row, like a missing or repeated field of a JSON vehicle
+// - rows are checked like the vehicles of the JSON loaders, the index of an issue is the position of its row after the header
+type LoaderVehicleCSV struct {
+	// path is the path to the file that contains the vehicles in CSV format
+	path string
+	// policy is the policy applied to the vehicles that do not pass validation
+	policy internal.LoadPolicy
+	// validator checks the domain rules of the vehicles, nil if there are none
+	validator internal.VehicleValidator
+	// match is the way registrations are compared
+	match internal.TextMatch
+	// mu guards report
+	mu sync.RWMutex
+	// report is the report of the last load
+	report internal.LoadReport
+}
+
+// csvColumns is the set of columns supported by LoaderVehicleCSV
+var csvColumns = map[string]bool{
+	"id":           true,
+	"brand":        true,
+	"model":        true,
+	"registration": true,
+	"color":        true,
+	"year":         true,
+	"passengers":   true,
+	"max_speed":    true,
+	"fuel_type":    true,
+	"transmission": true,
+	"weight":       true,
+	"height":       true,
+	"length":       true,
+	"width":        true,
+}
+
+// setCSVField is a function that sets the field of the vehicle that matches the column
+func setCSVField(vh *VehicleJSON, column string, value string) (err error) {
+	switch column {
+	case "id":
+		vh.Id, err = strconv.Atoi(value)
+	case "brand":
+		vh.Brand = value
+	case "model":
+		vh.Model = value
+	case "registration":
+		vh.Registration = value
+	case "color":
+		vh.Color = value
+	case "year":
+		vh.FabricationYear, err = strconv.Atoi(value)
+	case "passengers":
+		vh.Capacity, err = strconv.Atoi(value)
+	case "max_speed":
+		vh.MaxSpeed, err = strconv.ParseFloat(value, 64)
+	case "fuel_type":
+		vh.FuelType = value
+	case "transmission":
+		vh.Transmission = value
+	case "weight":
+		vh.Weight, err = strconv.ParseFloat(value, 64)
+	case "height":
+		vh.Height, err = strconv.ParseFloat(value, 64)
+	case "length":
+		vh.Length, err = strconv.ParseFloat(value, 64)
+	case "width":
+		vh.Width, err = strconv.ParseFloat(value, 64)
+	}
+	return
+}
+
+// Load is a method that loads the vehicles
+// - an invalid header or a malformed file fails the load, whatever the policy
+// - a row with a value that can not be parsed is skipped, with one issue per invalid value
+func (l *LoaderVehicleCSV) Load() (v map[int]internal.Vehicle, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// read header
+	r := csv.NewReader(file)
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return
+	}
+	columns, present, repeated, err := readCSVHeader(header)
+	if err != nil {
+		return
+	}
+
+	// read, validate and serialize rows
+	c := newVehicleJSONChecker(l.policy, l.validator, l.match)
+	for index := 0; ; index++ {
+		record, e := r.Read()
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			// malformed csv (quotes, field count): the error already includes the line
+			err = e
+			return
+		}
+		line, _ := r.FieldPos(0)
+
+		var vh VehicleJSON
+		var issues []internal.LoadIssue
+		for i, value := range record {
+			if columns[i] == "" {
+				continue
+			}
+			e = setCSVField(&vh, columns[i], strings.TrimSpace(value))
+			if e != nil {
+				rowErr := &RowError{Line: line, Column: columns[i], Err: e}
+				issues = append(issues, internal.LoadIssue{Index: index, Kind: internal.LoadIssueInvalidValue, Field: columns[i], Message: rowErr.Error()})
+			}
+		}
+		if len(issues) > 0 {
+			c.skip(issues...)
+			continue
+		}
+
+		c.addVehicle(index, vh, checkVehicleCSV(index, vh, present, repeated))
+	}
+	v, err = c.result()
+
+	// save report
+	l.mu.Lock()
+	l.report = c.report
+	l.mu.Unlock()
+
+	return
+}
+
+// readCSVHeader is a function that returns the column of each position of the header
+// - the position of a repeated column is left empty, so that the first one is kept, and the column is returned in repeated
+// - an unknown column or a missing id fails the load
+func readCSVHeader(header []string) (columns []string, present map[string]bool, repeated []string, err error) {
+	columns = make([]string, len(header))
+	present = make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !csvColumns[name] {
+			err = &RowError{Line: 1, Column: name, Err: ErrLoaderCSVUnknownColumn}
+			return
+		}
+		if present[name] {
+			repeated = append(repeated, name)
+			continue
+		}
+		columns[i] = name
+		present[name] = true
+	}
+	if !present["id"] {
+		err = &RowError{Line: 1, Column: "id", Err: ErrLoaderCSVMissingColumn}
+		return
+	}
+	return
+}
+
+// checkVehicleCSV is a function that returns the issues of a parsed row, given the columns of the header
+func checkVehicleCSV(index int, vh VehicleJSON, present map[string]bool, repeated []string) (issues []internal.LoadIssue) {
+	issue := func(kind internal.LoadIssueKind, field string, message string) {
+		issues = append(issues, internal.LoadIssue{Index: index, Id: vh.Id, Kind: kind, Field: field, Message: message})
+	}
+
+	// missing columns
+	for _, name := range vehicleJSONFields {
+		if !present[name] {
+			issue(internal.LoadIssueMissingField, name, "required column is missing")
+		}
+	}
+
+	// repeated columns
+	for _, name := range repeated {
+		issue(internal.LoadIssueDuplicate, name, "column is repeated in the header, the first one is kept")
+	}
+
+	// ranges: only checked for present columns, a missing one is already reported
+	issues = append(issues, checkVehicleRanges(index, vh, func(name string) bool { return present[name] })...)
+
+	return
+}
+
+// Report is a method that returns the report of the last load
+func (l *LoaderVehicleCSV) Report() (r internal.LoadReport) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	r = l.report
+	return
+}
+
+// Ready is a method that returns ErrNotReady if the file of the vehicles can not be opened
+func (l *LoaderVehicleCSV) Ready(ctx context.Context) (err error) {
+	err = readyFile(l.path)
+	return
+}
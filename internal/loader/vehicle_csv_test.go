@@ -0,0 +1,233 @@
+package loader
+
+import (
+	"app/internal"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile is a function that writes the content to a temporary file and returns its path
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// Tests for LoaderVehicleCSV
+func TestLoaderVehicleCSV_Load(t *testing.T) {
+	t.Run("success - columns in any order", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "brand,id,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,length,width\n"+
+			"Ford,1,Fiesta,ABC-1234,Red,2010,5,180,Gasoline,Manual,1000,1.5,4,1.8\n"+
+			"Fiat, 2 ,Uno,ABC-1236,Red,2012,5,180,Gasoline,Manual,1200,1.5,4,1.8\n")
+		ld := NewLoaderVehicleCSV(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		expected := map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta", Registration: "ABC-1234", Color: "Red", FabricationYear: 2010, Capacity: 5, MaxSpeed: 180, FuelType: "Gasoline", Transmission: "Manual", Weight: 1000, Dimensions: internal.Dimensions{Height: 1.5, Length: 4, Width: 1.8}}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "Uno", Registration: "ABC-1236", Color: "Red", FabricationYear: 2012, Capacity: 5, MaxSpeed: 180, FuelType: "Gasoline", Transmission: "Manual", Weight: 1200, Dimensions: internal.Dimensions{Height: 1.5, Length: 4, Width: 1.8}}},
+		}
+		require.NoError(t, err)
+		require.Equal(t, expected, v)
+	})
+
+	t.Run("missing columns - reported on every row", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "ID,Brand,Model,Registration,Color,Year,Passengers,Max_Speed,Fuel_Type,Transmission,Weight,Height\n"+
+			"1,Ford,Fiesta,ABC-1234,Red,2010,5,180,Gasoline,Manual,1000,1.5\n"+
+			"2,Fiat,Uno,ABC-1236,Red,2012,5,180,Gasoline,Manual,1200,1.5\n")
+
+		// act
+		vWarn, errWarn := NewLoaderVehicleCSV(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact).Load()
+		ldSkip := NewLoaderVehicleCSV(path, internal.LoadPolicySkip, nil, internal.TextMatchExact)
+		vSkip, errSkip := ldSkip.Load()
+
+		// assert
+		require.NoError(t, errWarn)
+		require.Len(t, vWarn, 2)
+		require.Zero(t, vWarn[1].Length)
+		require.NoError(t, errSkip)
+		require.Empty(t, vSkip)
+		expectedIssues := []internal.LoadIssue{
+			{Index: 0, Id: 1, Kind: internal.LoadIssueMissingField, Field: "length", Message: "required column is missing"},
+			{Index: 0, Id: 1, Kind: internal.LoadIssueMissingField, Field: "width", Message: "required column is missing"},
+			{Index: 1, Id: 2, Kind: internal.LoadIssueMissingField, Field: "length", Message: "required column is missing"},
+			{Index: 1, Id: 2, Kind: internal.LoadIssueMissingField, Field: "width", Message: "required column is missing"},
+		}
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicySkip, Total: 2, Skipped: 2, Issues: expectedIssues, Counts: map[internal.LoadIssueKind]int{internal.LoadIssueMissingField: 4}}, ldSkip.Report())
+	})
+
+	t.Run("repeated column - keeps the first one and reports it on every row", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "id,brand,model,registration,color,year,passengers,max_speed,fuel_type,transmission,weight,height,length,width,color\n"+
+			"1,Ford,Fiesta,ABC-1234,Red,2010,5,180,Gasoline,Manual,1000,1.5,4,1.8,Blue\n")
+		ld := NewLoaderVehicleCSV(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "Red", v[1].Color)
+		require.Equal(t, []internal.LoadIssue{
+			{Index: 0, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "color", Message: "column is repeated in the header, the first one is kept"},
+		}, ld.Report().Issues)
+	})
+
+	// a valid row, one with a duplicated registration, one breaking a domain rule, one with a duplicated id and one that can not be parsed
+	rest := ",Ford,Fiesta,Red,5,180,Gasoline,Manual,1.5,4,1.8\n"
+	content := "id,registration,year,weight,brand,model,color,passengers,max_speed,fuel_type,transmission,height,length,width\n" +
+		"1,ABC-1234,2010,1000" + rest + "2,ABC-1234,2010,1000" + rest + "3,XYZ-0001,1700,1000" + rest + "1,XYZ-0002,2011,-1" + rest + "4,XYZ-0003,old,heavy" + rest
+	vv := internal.NewVehicleValidatorRules(internal.RuleUniqueRegistration(), internal.RuleYearRange(1886, 2030))
+	expectedIssues := []internal.LoadIssue{
+		{Index: 1, Id: 2, Kind: internal.LoadIssueDuplicate, Field: "registration", Message: `"ABC-1234" is already used by vehicle 1`},
+		{Index: 2, Id: 3, Kind: internal.LoadIssueOutOfRange, Field: "year", Message: "must be between 1886 and 2030, got 1700"},
+		{Index: 3, Id: 1, Kind: internal.LoadIssueOutOfRange, Field: "weight", Message: "must not be negative, got -1"},
+		{Index: 3, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "id", Message: "id 1 already loaded"},
+		{Index: 4, Kind: internal.LoadIssueInvalidValue, Field: "year", Message: `line 6: column year: strconv.Atoi: parsing "old": invalid syntax`},
+		{Index: 4, Kind: internal.LoadIssueInvalidValue, Field: "weight", Message: `line 6: column weight: strconv.ParseFloat: parsing "heavy": invalid syntax`},
+	}
+	expectedCounts := map[internal.LoadIssueKind]int{
+		internal.LoadIssueDuplicate: 2, internal.LoadIssueOutOfRange: 2, internal.LoadIssueInvalidValue: 2,
+	}
+
+	t.Run("warn policy - loads every parsed row but the duplicates and reports the issues", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", content)
+		ld := NewLoaderVehicleCSV(path, internal.LoadPolicyWarn, vv, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 3)
+		require.Equal(t, "ABC-1234", v[1].Registration)
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicyWarn, Total: 5, Loaded: 3, Skipped: 2, Issues: expectedIssues, Counts: expectedCounts}, ld.Report())
+	})
+
+	t.Run("skip policy - leaves out the rows with issues", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", content)
+		ld := NewLoaderVehicleCSV(path, internal.LoadPolicySkip, vv, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, "ABC-1234", v[1].Registration)
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicySkip, Total: 5, Loaded: 1, Skipped: 4, Issues: expectedIssues, Counts: expectedCounts}, ld.Report())
+	})
+
+	t.Run("fail policy - fails the load and keeps the report", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", content)
+		ld := NewLoaderVehicleCSV(path, internal.LoadPolicyFail, vv, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrLoaderInvalidVehicles)
+		require.EqualError(t, err, "loader: invalid vehicles: 6 issues found")
+		require.Nil(t, v)
+		require.Equal(t, expectedIssues, ld.Report().Issues)
+	})
+
+	t.Run("default policy is warn", func(t *testing.T) {
+		// arrange
+		ld := NewLoaderVehicleCSV("vehicles.csv", "", nil, internal.TextMatchExact)
+
+		// assert
+		require.Equal(t, internal.LoadPolicyWarn, ld.policy)
+	})
+
+	t.Run("error - unknown column", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "id,brand,doors\n1,Ford,3\n")
+		ld := NewLoaderVehicleCSV(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, ErrLoaderCSVUnknownColumn)
+		require.EqualError(t, err, "line 1: column doors: loader: unknown csv column")
+		require.Nil(t, v)
+	})
+
+	t.Run("error - missing id column", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "brand\nFord\n")
+		ld := NewLoaderVehicleCSV(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, ErrLoaderCSVMissingColumn)
+		require.Nil(t, v)
+	})
+
+	t.Run("error - malformed csv", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.csv", "id,brand\n1,Ford,extra\n")
+		ld := NewLoaderVehicleCSV(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.Error(t, err)
+		require.Nil(t, v)
+	})
+}
+
+// Tests for NewLoaderVehicle
+func TestNewLoaderVehicle(t *testing.T) {
+	t.Run("format inferred from extension", func(t *testing.T) {
+		ld, err := NewLoaderVehicle("vehicles.CSV", "", "", nil, internal.TextMatchExact)
+		require.NoError(t, err)
+		require.IsType(t, &LoaderVehicleCSV{}, ld)
+
+		ld, err = NewLoaderVehicle("vehicles.json", "", "", nil, internal.TextMatchExact)
+		require.NoError(t, err)
+		require.IsType(t, &LoaderVehicleJSON{}, ld)
+
+		ld, err = NewLoaderVehicle("vehicles.ndjson", "", "", nil, internal.TextMatchExact)
+		require.NoError(t, err)
+		require.IsType(t, &LoaderVehicleJSONStream{}, ld)
+	})
+
+	t.Run("explicit format wins over extension", func(t *testing.T) {
+		ld, err := NewLoaderVehicle("vehicles.txt", FormatCSV, "", nil, internal.TextMatchExact)
+		require.NoError(t, err)
+		require.IsType(t, &LoaderVehicleCSV{}, ld)
+
+		ld, err = NewLoaderVehicle("vehicles.json", FormatJSONStream, "", nil, internal.TextMatchExact)
+		require.NoError(t, err)
+		require.IsType(t, &LoaderVehicleJSONStream{}, ld)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		ld, err := NewLoaderVehicle("vehicles.xml", "", "", nil, internal.TextMatchExact)
+		require.True(t, errors.Is(err, ErrLoaderUnknownFormat))
+		require.Nil(t, ld)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		ld, err := NewLoaderVehicle("vehicles.json", "", "ignore", nil, internal.TextMatchExact)
+		require.True(t, errors.Is(err, internal.ErrLoaderUnknownPolicy))
+		require.Nil(t, ld)
+	})
+}
@@ -2,21 +2,51 @@ package loader
 
 import (
 	"app/internal"
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"sync"
+)
+
+var (
+	// ErrLoaderJSONNotArray is an error that represents a JSON file that does not hold an array of vehicles
+	ErrLoaderJSONNotArray = errors.New("loader: json file is not an array")
 )
 
 // NewLoaderVehicleJSON is a function that returns a new instance of LoaderVehicleJSON
-func NewLoaderVehicleJSON(path string) *LoaderVehicleJSON {
+// - an empty policy defaults to LoadPolicyWarn
+// - vv can be nil, in which case the domain rules of the vehicles are not checked
+// - match is the way the repository the vehicles are loaded into compares texts, registrations are checked the same way
+func NewLoaderVehicleJSON(path string, policy internal.LoadPolicy, vv internal.VehicleValidator, match internal.TextMatch) *LoaderVehicleJSON {
+	// default policy
+	if policy == "" {
+		policy = internal.LoadPolicyWarn
+	}
+
 	return &LoaderVehicleJSON{
-		path: path,
+		path:      path,
+		policy:    policy,
+		validator: vv,
+		match:     match,
 	}
 }
 
-// LoaderVehicleJSON is a struct that implements the LoaderVehicle interface
+// LoaderVehicleJSON is a struct that implements the LoaderVehicle, LoaderReporter and ReadinessChecker interfaces
+// - the file is a JSON array, its elements are decoded one at a time so the file is never held in memory as a whole
 type LoaderVehicleJSON struct {
 	// path is the path to the file that contains the vehicles in JSON format
 	path string
+	// policy is the policy applied to the vehicles that do not pass validation
+	policy internal.LoadPolicy
+	// validator checks the domain rules of the vehicles, nil if there are none
+	validator internal.VehicleValidator
+	// match is the way registrations are compared
+	match internal.TextMatch
+	// mu guards report
+	mu sync.RWMutex
+	// report is the report of the last load
+	report internal.LoadReport
 }
 
 // VehicleJSON is a struct that represents a vehicle in JSON format
@@ -37,6 +67,30 @@ type VehicleJSON struct {
 	Width           float64 `json:"width"`
 }
 
+// Vehicle is a method that returns the vehicle represented by the JSON
+func (vh VehicleJSON) Vehicle() internal.Vehicle {
+	return internal.Vehicle{
+		Id: vh.Id,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           vh.Brand,
+			Model:           vh.Model,
+			Registration:    vh.Registration,
+			Color:           vh.Color,
+			FabricationYear: vh.FabricationYear,
+			Capacity:        vh.Capacity,
+			MaxSpeed:        vh.MaxSpeed,
+			FuelType:        vh.FuelType,
+			Transmission:    vh.Transmission,
+			Weight:          vh.Weight,
+			Dimensions: internal.Dimensions{
+				Height: vh.Height,
+				Length: vh.Length,
+				Width:  vh.Width,
+			},
+		},
+	}
+}
+
 // Load is a method that loads the vehicles
 func (l *LoaderVehicleJSON) Load() (v map[int]internal.Vehicle, err error) {
 	// open file
@@ -46,37 +100,33 @@ func (l *LoaderVehicleJSON) Load() (v map[int]internal.Vehicle, err error) {
 	}
 	defer file.Close()
 
-	// decode file
-	var vehiclesJSON []VehicleJSON
-	err = json.NewDecoder(file).Decode(&vehiclesJSON)
+	// decode, validate and serialize vehicles
+	c := newVehicleJSONChecker(l.policy, l.validator, l.match)
+	err = decodeArray(json.NewDecoder(file), c)
 	if err != nil {
 		return
 	}
+	v, err = c.result()
 
-	// serialize vehicles
-	v = make(map[int]internal.Vehicle)
-	for _, vh := range vehiclesJSON {
-		v[vh.Id] = internal.Vehicle{
-			Id: vh.Id,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           vh.Brand,
-				Model:           vh.Model,
-				Registration:    vh.Registration,
-				Color:           vh.Color,
-				FabricationYear: vh.FabricationYear,
-				Capacity:        vh.Capacity,
-				MaxSpeed:        vh.MaxSpeed,
-				FuelType:        vh.FuelType,
-				Transmission:    vh.Transmission,
-				Weight:          vh.Weight,
-				Dimensions: internal.Dimensions{
-					Height: vh.Height,
-					Length: vh.Length,
-					Width:  vh.Width,
-				},
-			},
-		}
-	}
+	// save report
+	l.mu.Lock()
+	l.report = c.report
+	l.mu.Unlock()
+
+	return
+}
+
+// Report is a method that returns the report of the last load
+func (l *LoaderVehicleJSON) Report() (r internal.LoadReport) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	r = l.report
+	return
+}
 
+// Ready is a method that returns ErrNotReady if the file of the vehicles can not be opened
+func (l *LoaderVehicleJSON) Ready(ctx context.Context) (err error) {
+	err = readyFile(l.path)
 	return
 }
@@ -0,0 +1,232 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// vehicleJSONFields are the keys of a vehicle in JSON format, all of them are required
+var vehicleJSONFields = []string{
+	"id", "brand", "model", "registration", "color", "year", "passengers",
+	"max_speed", "fuel_type", "transmission", "weight", "height", "length", "width",
+}
+
+// vehicleJSONTargets are the fields of a vehicle each JSON key decodes into
+var vehicleJSONTargets = map[string]func(vh *VehicleJSON) any{
+	"id":           func(vh *VehicleJSON) any { return &vh.Id },
+	"brand":        func(vh *VehicleJSON) any { return &vh.Brand },
+	"model":        func(vh *VehicleJSON) any { return &vh.Model },
+	"registration": func(vh *VehicleJSON) any { return &vh.Registration },
+	"color":        func(vh *VehicleJSON) any { return &vh.Color },
+	"year":         func(vh *VehicleJSON) any { return &vh.FabricationYear },
+	"passengers":   func(vh *VehicleJSON) any { return &vh.Capacity },
+	"max_speed":    func(vh *VehicleJSON) any { return &vh.MaxSpeed },
+	"fuel_type":    func(vh *VehicleJSON) any { return &vh.FuelType },
+	"transmission": func(vh *VehicleJSON) any { return &vh.Transmission },
+	"weight":       func(vh *VehicleJSON) any { return &vh.Weight },
+	"height":       func(vh *VehicleJSON) any { return &vh.Height },
+	"length":       func(vh *VehicleJSON) any { return &vh.Length },
+	"width":        func(vh *VehicleJSON) any { return &vh.Width },
+}
+
+// newVehicleJSONChecker is a function that returns a new instance of vehicleJSONChecker
+// - vv can be nil, in which case only the structure and the ranges of the vehicles are checked
+// - match is the way registrations are compared
+func newVehicleJSONChecker(policy internal.LoadPolicy, vv internal.VehicleValidator, match internal.TextMatch) *vehicleJSONChecker {
+	vehicles := make(map[int]internal.Vehicle)
+	return &vehicleJSONChecker{
+		report:        internal.LoadReport{Policy: policy},
+		validator:     vv,
+		seen:          make(map[int]bool),
+		vehicles:      vehicles,
+		registrations: newRegistrationIndex(vehicles, match),
+	}
+}
+
+// vehicleJSONChecker is a struct that validates raw vehicles one at a time and builds the load report
+type vehicleJSONChecker struct {
+	// report is the report being built
+	report internal.LoadReport
+	// validator checks the domain rules of the vehicles, nil if there are none
+	validator internal.VehicleValidator
+	// seen is the set of ids already read
+	seen map[int]bool
+	// vehicles are the vehicles accepted so far
+	vehicles map[int]internal.Vehicle
+	// registrations index the vehicles accepted so far by registration
+	registrations *registrationIndex
+}
+
+// add is a method that validates the raw vehicle found at index and keeps it according to the policy
+func (c *vehicleJSONChecker) add(index int, raw json.RawMessage) {
+	// decode
+	vh, fields, err := decodeVehicleJSON(raw)
+	if err != nil {
+		c.skip(internal.LoadIssue{Index: index, Kind: internal.LoadIssueInvalid, Message: err.Error()})
+		return
+	}
+
+	c.addVehicle(index, vh, checkVehicleJSON(index, vh, fields))
+}
+
+// skip is a method that reports a vehicle that could not be decoded
+// - an undecodable vehicle can not be kept, whatever the policy
+func (c *vehicleJSONChecker) skip(issues ...internal.LoadIssue) {
+	c.report.Total++
+	c.report.AddIssues(issues...)
+	c.report.Skipped++
+}
+
+// addVehicle is a method that validates the decoded vehicle found at index and keeps it according to the policy
+// - a vehicle with an id already read is never kept, so that it does not overwrite the first one
+// - issues are the ones already found in the source of the vehicle, they count against it like the others
+func (c *vehicleJSONChecker) addVehicle(index int, vh VehicleJSON, issues []internal.LoadIssue) {
+	c.report.Total++
+
+	// validate
+	duplicate := c.seen[vh.Id]
+	if duplicate {
+		issues = append(issues, internal.LoadIssue{Index: index, Id: vh.Id, Kind: internal.LoadIssueDuplicate, Field: "id", Message: fmt.Sprintf("id %d already loaded", vh.Id)})
+	}
+	c.seen[vh.Id] = true
+	v := vh.Vehicle()
+	issues = append(issues, c.validate(index, v, issues)...)
+	c.report.AddIssues(issues...)
+
+	// keep: the vehicle read first keeps its id, whatever the policy
+	if duplicate || (len(issues) > 0 && c.report.Policy == internal.LoadPolicySkip) {
+		c.report.Skipped++
+		return
+	}
+	c.vehicles[v.Id] = v
+	c.registrations.add(v)
+}
+
+// validate is a method that returns the issues of the domain rules broken by the vehicle found at index
+// - fields that already have an issue are not reported twice
+func (c *vehicleJSONChecker) validate(index int, v internal.Vehicle, found []internal.LoadIssue) (issues []internal.LoadIssue) {
+	if c.validator == nil {
+		return
+	}
+
+	err := c.validator.Validate(v, c.registrations)
+	var verr *internal.VehicleValidationError
+	if !errors.As(err, &verr) {
+		if err != nil {
+			issues = append(issues, internal.LoadIssue{Index: index, Id: v.Id, Kind: internal.LoadIssueInvalid, Message: err.Error()})
+		}
+		return
+	}
+
+	reported := make(map[string]bool, len(found))
+	for _, issue := range found {
+		reported[issue.Field] = true
+	}
+	for _, f := range verr.Fields {
+		if reported[f.Field] {
+			continue
+		}
+		issues = append(issues, internal.LoadIssue{Index: index, Id: v.Id, Kind: validationIssueKinds[f.Rule], Field: f.Field, Message: f.Message})
+	}
+	return
+}
+
+// result is a method that returns the accepted vehicles
+// - with the fail policy, any issue makes the load fail
+func (c *vehicleJSONChecker) result() (v map[int]internal.Vehicle, err error) {
+	if c.report.Policy == internal.LoadPolicyFail && c.report.IssueCount() > 0 {
+		err = fmt.Errorf("%w: %d issues found", internal.ErrLoaderInvalidVehicles, c.report.IssueCount())
+		return
+	}
+
+	c.report.Loaded = len(c.vehicles)
+	v = c.vehicles
+	return
+}
+
+// decodeVehicleJSON is a function that decodes a raw vehicle along with its raw fields
+// - the element is parsed once, each known field is then decoded from its raw value
+func decodeVehicleJSON(raw json.RawMessage) (vh VehicleJSON, fields map[string]json.RawMessage, err error) {
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return
+	}
+
+	for _, name := range vehicleJSONFields {
+		value, ok := fields[name]
+		if !ok {
+			continue
+		}
+		err = json.Unmarshal(value, vehicleJSONTargets[name](&vh))
+		if err != nil {
+			err = fmt.Errorf("field %s: %w", name, err)
+			return
+		}
+	}
+	return
+}
+
+// checkVehicleJSON is a function that returns the issues of a decoded vehicle and its raw fields
+func checkVehicleJSON(index int, vh VehicleJSON, fields map[string]json.RawMessage) (issues []internal.LoadIssue) {
+	issue := func(kind internal.LoadIssueKind, field string, message string) {
+		issues = append(issues, internal.LoadIssue{Index: index, Id: vh.Id, Kind: kind, Field: field, Message: message})
+	}
+
+	// missing fields
+	for _, name := range vehicleJSONFields {
+		value, ok := fields[name]
+		if !ok || string(value) == "null" {
+			issue(internal.LoadIssueMissingField, name, "required field is missing")
+		}
+	}
+
+	// unknown fields
+	var unknown []string
+	for name := range fields {
+		if _, ok := vehicleJSONTargets[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		issue(internal.LoadIssueUnknownField, name, "field does not belong to a vehicle")
+	}
+
+	// ranges: only checked for present fields, a missing one is already reported
+	issues = append(issues, checkVehicleRanges(index, vh, func(name string) bool {
+		_, ok := fields[name]
+		return ok
+	})...)
+
+	return
+}
+
+// checkVehicleRanges is a function that returns the issues of the fields of a decoded vehicle that are out of range
+// - present tells the fields read from the source, the others are not checked
+func checkVehicleRanges(index int, vh VehicleJSON, present func(name string) bool) (issues []internal.LoadIssue) {
+	issue := func(field string, message string) {
+		issues = append(issues, internal.LoadIssue{Index: index, Id: vh.Id, Kind: internal.LoadIssueOutOfRange, Field: field, Message: message})
+	}
+
+	positive := map[string]float64{"id": float64(vh.Id), "year": float64(vh.FabricationYear)}
+	nonNegative := map[string]float64{
+		"passengers": float64(vh.Capacity), "max_speed": vh.MaxSpeed, "weight": vh.Weight,
+		"height": vh.Height, "length": vh.Length, "width": vh.Width,
+	}
+	for _, name := range vehicleJSONFields {
+		if !present(name) {
+			continue
+		}
+		if value, ok := positive[name]; ok && value <= 0 {
+			issue(name, fmt.Sprintf("must be greater than 0, got %v", value))
+		}
+		if value, ok := nonNegative[name]; ok && value < 0 {
+			issue(name, fmt.Sprintf("must not be negative, got %v", value))
+		}
+	}
+
+	return
+}
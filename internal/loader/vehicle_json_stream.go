@@ -0,0 +1,172 @@
+package loader
+
+import (
+	"app/internal"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"unicode"
+)
+
+var (
+	// ErrLoaderJSONStreamInvalid is an error that represents a stream that is neither a JSON array nor NDJSON
+	ErrLoaderJSONStreamInvalid = errors.New("loader: invalid json stream")
+)
+
+// NewLoaderVehicleJSONStream is a function that returns a new instance of LoaderVehicleJSONStream
+// - an empty policy defaults to LoadPolicyWarn
+// - vv can be nil, in which case the domain rules of the vehicles are not checked
+// - match is the way the repository the vehicles are loaded into compares texts, registrations are checked the same way
+func NewLoaderVehicleJSONStream(path string, policy internal.LoadPolicy, vv internal.VehicleValidator, match internal.TextMatch) *LoaderVehicleJSONStream {
+	// default policy
+	if policy == "" {
+		policy = internal.LoadPolicyWarn
+	}
+
+	return &LoaderVehicleJSONStream{
+		path:      path,
+		policy:    policy,
+		validator: vv,
+		match:     match,
+	}
+}
+
+// LoaderVehicleJSONStream is a struct that implements the LoaderVehicle, LoaderReporter and ReadinessChecker interfaces
+// - vehicles are decoded one at a time, so the whole file is never held in memory as []VehicleJSON
+// - accepts a JSON array of vehicles or NDJSON (one vehicle per line)
+type LoaderVehicleJSONStream struct {
+	// path is the path to the file that contains the vehicles
+	path string
+	// policy is the policy applied to the vehicles that do not pass validation
+	policy internal.LoadPolicy
+	// validator checks the domain rules of the vehicles, nil if there are none
+	validator internal.VehicleValidator
+	// match is the way registrations are compared
+	match internal.TextMatch
+	// mu guards report
+	mu sync.RWMutex
+	// report is the report of the last load
+	report internal.LoadReport
+}
+
+// Load is a method that loads the vehicles
+func (l *LoaderVehicleJSONStream) Load() (v map[int]internal.Vehicle, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// detect format by the first non space character
+	r := bufio.NewReader(file)
+	first, err := peekNonSpace(r)
+	if err != nil && err != io.EOF {
+		return
+	}
+
+	// decode, validate and serialize vehicles
+	// - an empty file is an empty NDJSON stream
+	c := newVehicleJSONChecker(l.policy, l.validator, l.match)
+	dec := json.NewDecoder(r)
+	switch {
+	case err == io.EOF:
+		err = nil
+	case first == '[':
+		err = decodeArray(dec, c)
+	case first == '{':
+		err = decodeNDJSON(dec, c)
+	default:
+		err = fmt.Errorf("%w: unexpected character %q", ErrLoaderJSONStreamInvalid, first)
+	}
+	if err != nil {
+		return
+	}
+	v, err = c.result()
+
+	// save report
+	l.mu.Lock()
+	l.report = c.report
+	l.mu.Unlock()
+
+	return
+}
+
+// Report is a method that returns the report of the last load
+func (l *LoaderVehicleJSONStream) Report() (r internal.LoadReport) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	r = l.report
+	return
+}
+
+// Ready is a method that returns ErrNotReady if the file of the vehicles can not be opened
+func (l *LoaderVehicleJSONStream) Ready(ctx context.Context) (err error) {
+	err = readyFile(l.path)
+	return
+}
+
+// peekNonSpace is a function that returns the first non space character without consuming it
+func peekNonSpace(r *bufio.Reader) (c rune, err error) {
+	for {
+		c, _, err = r.ReadRune()
+		if err != nil {
+			return
+		}
+		if !unicode.IsSpace(c) {
+			err = r.UnreadRune()
+			return
+		}
+	}
+}
+
+// decodeArray is a function that decodes a JSON array of vehicles element by element
+func decodeArray(dec *json.Decoder, c *vehicleJSONChecker) (err error) {
+	// opening bracket
+	tok, err := dec.Token()
+	if err != nil {
+		return
+	}
+	if tok != json.Delim('[') {
+		err = fmt.Errorf("%w: got %v", ErrLoaderJSONNotArray, tok)
+		return
+	}
+
+	// elements
+	for i := 0; dec.More(); i++ {
+		var raw json.RawMessage
+		err = dec.Decode(&raw)
+		if err != nil {
+			err = fmt.Errorf("element %d: %w", i, err)
+			return
+		}
+		c.add(i, raw)
+	}
+
+	// closing bracket
+	_, err = dec.Token()
+	return
+}
+
+// decodeNDJSON is a function that decodes a stream of vehicles, one JSON object per line
+func decodeNDJSON(dec *json.Decoder, c *vehicleJSONChecker) (err error) {
+	for i := 0; ; i++ {
+		var raw json.RawMessage
+		err = dec.Decode(&raw)
+		if err == io.EOF {
+			err = nil
+			return
+		}
+		if err != nil {
+			err = fmt.Errorf("element %d: %w", i, err)
+			return
+		}
+		c.add(i, raw)
+	}
+}
@@ -0,0 +1,158 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for LoaderVehicleJSONStream
+func TestLoaderVehicleJSONStream_Load(t *testing.T) {
+	expected := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FabricationYear: 2010, Capacity: 5, Dimensions: internal.Dimensions{Height: 1.5}}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", FabricationYear: 2012, Capacity: 4}},
+	}
+
+	t.Run("success - json array", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", ` [
+			{"id":1,"brand":"Ford","year":2010,"passengers":5,"height":1.5},
+			{"id":2,"brand":"Fiat","year":2012,"passengers":4}
+		]`)
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, expected, v)
+	})
+
+	t.Run("success - ndjson", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.ndjson", "{\"id\":1,\"brand\":\"Ford\",\"year\":2010,\"passengers\":5,\"height\":1.5}\n"+
+			"{\"id\":2,\"brand\":\"Fiat\",\"year\":2012,\"passengers\":4}\n")
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, expected, v)
+	})
+
+	t.Run("success - empty file", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.ndjson", "")
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, v)
+	})
+
+	t.Run("error - invalid element with fail policy", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[{"id":1},{"id":"two"}]`)
+		ld := NewLoaderVehicleJSONStream(path, internal.LoadPolicyFail, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrLoaderInvalidVehicles)
+		require.Nil(t, v)
+		require.Equal(t, 1, ld.Report().Count(internal.LoadIssueInvalid))
+	})
+
+	t.Run("error - truncated array", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[{"id":1},`)
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.Error(t, err)
+		require.Nil(t, v)
+	})
+
+	t.Run("error - not json", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `id,brand`)
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, ErrLoaderJSONStreamInvalid)
+		require.Nil(t, v)
+	})
+}
+
+// benchmarkVehicles is the number of vehicles written to the benchmark files
+const benchmarkVehicles = 100000
+
+// writeBenchmarkFile is a function that writes a JSON array of generated vehicles and returns its path
+func writeBenchmarkFile(b *testing.B) string {
+	b.Helper()
+	path := filepath.Join(b.TempDir(), "vehicles.json")
+	file, err := os.Create(path)
+	require.NoError(b, err)
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+	_, err = file.WriteString("[")
+	require.NoError(b, err)
+	for i := 1; i <= benchmarkVehicles; i++ {
+		if i > 1 {
+			_, err = file.WriteString(",")
+			require.NoError(b, err)
+		}
+		err = enc.Encode(VehicleJSON{
+			Id: i, Brand: "Chevrolet", Model: "Cavalier", Registration: fmt.Sprint(i), Color: "Blue",
+			FabricationYear: 1995, Capacity: 2, MaxSpeed: 97, FuelType: "diesel", Transmission: "manual",
+			Weight: 112.69, Height: 9.03, Length: 4.5, Width: 293.53,
+		})
+		require.NoError(b, err)
+	}
+	_, err = file.WriteString("]")
+	require.NoError(b, err)
+	return path
+}
+
+// BenchmarkLoaderVehicleJSON compares the memory and time of the full decode against the streaming decode
+// - go test ./internal/loader -run ^$ -bench LoaderVehicleJSON -benchmem
+func BenchmarkLoaderVehicleJSON(b *testing.B) {
+	path := writeBenchmarkFile(b)
+
+	b.Run("decode all", func(b *testing.B) {
+		ld := NewLoaderVehicleJSON(path, "", nil, internal.TextMatchExact)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := ld.Load()
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		ld := NewLoaderVehicleJSONStream(path, "", nil, internal.TextMatchExact)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err := ld.Load()
+			require.NoError(b, err)
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package loader
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for LoaderVehicleJSON
+func TestLoaderVehicleJSON_Load(t *testing.T) {
+	// a valid vehicle, a duplicate of it, one with out of range values, and one with a missing and an unknown field
+	content := `[
+		{"id":1,"brand":"Ford","model":"Fiesta","registration":"ABC-1234","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1000,"height":1.5,"length":4,"width":1.8},
+		{"id":1,"brand":"Ford","model":"Focus","registration":"ABC-1235","color":"Blue","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1100,"height":1.5,"length":4,"width":1.8},
+		{"id":2,"brand":"Fiat","model":"Uno","registration":"ABC-1236","color":"Red","year":0,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":-1,"height":1.5,"length":4,"width":1.8},
+		{"id":3,"brand":"Fiat","model":"Palio","registration":"ABC-1237","color":"Red","year":2012,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1200,"height":1.5,"width":1.8,"doors":3}
+	]`
+	expectedIssues := []internal.LoadIssue{
+		{Index: 1, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "id", Message: "id 1 already loaded"},
+		{Index: 2, Id: 2, Kind: internal.LoadIssueOutOfRange, Field: "year", Message: "must be greater than 0, got 0"},
+		{Index: 2, Id: 2, Kind: internal.LoadIssueOutOfRange, Field: "weight", Message: "must not be negative, got -1"},
+		{Index: 3, Id: 3, Kind: internal.LoadIssueMissingField, Field: "length", Message: "required field is missing"},
+		{Index: 3, Id: 3, Kind: internal.LoadIssueUnknownField, Field: "doors", Message: "field does not belong to a vehicle"},
+	}
+	expectedCounts := map[internal.LoadIssueKind]int{
+		internal.LoadIssueDuplicate: 1, internal.LoadIssueOutOfRange: 2, internal.LoadIssueMissingField: 1, internal.LoadIssueUnknownField: 1,
+	}
+
+	t.Run("warn policy - loads every vehicle but the duplicates and reports the issues", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", content)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 3)
+		require.Equal(t, "Fiesta", v[1].Model)
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicyWarn, Total: 4, Loaded: 3, Skipped: 1, Issues: expectedIssues, Counts: expectedCounts}, ld.Report())
+	})
+
+	t.Run("skip policy - leaves out the vehicles with issues", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", content)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicySkip, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, "Fiesta", v[1].Model)
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicySkip, Total: 4, Loaded: 1, Skipped: 3, Issues: expectedIssues, Counts: expectedCounts}, ld.Report())
+	})
+
+	t.Run("fail policy - fails the load and keeps the report", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", content)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyFail, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrLoaderInvalidVehicles)
+		require.EqualError(t, err, "loader: invalid vehicles: 5 issues found")
+		require.Nil(t, v)
+		require.Equal(t, expectedIssues, ld.Report().Issues)
+	})
+
+	t.Run("default policy is warn", func(t *testing.T) {
+		// arrange
+		ld := NewLoaderVehicleJSON("vehicles.json", "", nil, internal.TextMatchExact)
+
+		// assert
+		require.Equal(t, internal.LoadPolicyWarn, ld.policy)
+	})
+
+	t.Run("error - not an array", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `{"id":1}`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.ErrorIs(t, err, ErrLoaderJSONNotArray)
+		require.Nil(t, v)
+	})
+
+	t.Run("error - malformed file", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[{"id":1},`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.Error(t, err)
+		require.Nil(t, v)
+	})
+}
+
+// Tests for LoaderVehicleJSON with a validator
+func TestLoaderVehicleJSON_Load_Validator(t *testing.T) {
+	// a valid vehicle, one with a duplicated registration, and one breaking several domain rules of which weight is already out of range
+	content := `[
+		{"id":1,"brand":"Ford","model":"Fiesta","registration":"ABC-1234","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1000,"height":1.5,"length":4,"width":1.8},
+		{"id":2,"brand":"Ford","model":"Focus","registration":"ABC-1234","color":"Blue","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1100,"height":1.5,"length":4,"width":1.8},
+		{"id":3,"brand":"Fiat","model":"Uno","registration":"0","color":"Red","year":1700,"passengers":5,"max_speed":180,"fuel_type":"steam","transmission":"Manual","weight":-1,"height":1.5,"length":4,"width":1.8}
+	]`
+	vv := internal.NewVehicleValidatorRules(
+		internal.RuleRegistrationFormat(map[string]*regexp.Regexp{"AR": regexp.MustCompile(`^[A-Z]{3}-\d{4}$`)}),
+		internal.RuleUniqueRegistration(),
+		internal.RuleYearRange(1886, 2030),
+		internal.RuleNonNegative(),
+		internal.RuleKnownValues([]string{"gasoline", "diesel"}, []string{"manual", "automatic"}),
+	)
+	expectedIssues := []internal.LoadIssue{
+		{Index: 1, Id: 2, Kind: internal.LoadIssueDuplicate, Field: "registration", Message: `"ABC-1234" is already used by vehicle 1`},
+		{Index: 2, Id: 3, Kind: internal.LoadIssueOutOfRange, Field: "weight", Message: "must not be negative, got -1"},
+		{Index: 2, Id: 3, Kind: internal.LoadIssueInvalidValue, Field: "registration", Message: `"0" does not match the format of any country (AR)`},
+		{Index: 2, Id: 3, Kind: internal.LoadIssueOutOfRange, Field: "year", Message: "must be between 1886 and 2030, got 1700"},
+		{Index: 2, Id: 3, Kind: internal.LoadIssueInvalidValue, Field: "fuel_type", Message: `"steam" is not one of gasoline, diesel`},
+	}
+	expectedCounts := map[internal.LoadIssueKind]int{
+		internal.LoadIssueDuplicate: 1, internal.LoadIssueOutOfRange: 2, internal.LoadIssueInvalidValue: 2,
+	}
+
+	t.Run("skip policy - leaves out the vehicles breaking a domain rule", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", content)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicySkip, vv, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, "Fiesta", v[1].Model)
+		require.Equal(t, internal.LoadReport{Policy: internal.LoadPolicySkip, Total: 3, Loaded: 1, Skipped: 2, Issues: expectedIssues, Counts: expectedCounts}, ld.Report())
+	})
+
+	t.Run("warn policy - a duplicated id keeps the first vehicle and does not take its registration", func(t *testing.T) {
+		// arrange: the second vehicle 1 is left out, so its registration can be used by vehicle 2
+		path := writeFile(t, "vehicles.json", `[
+			{"id":1,"brand":"Ford","model":"Fiesta","registration":"ABC-1234","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1000,"height":1.5,"length":4,"width":1.8},
+			{"id":1,"brand":"Ford","model":"Fiesta","registration":"ABC-9999","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1000,"height":1.5,"length":4,"width":1.8},
+			{"id":2,"brand":"Ford","model":"Focus","registration":"ABC-9999","color":"Blue","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1100,"height":1.5,"length":4,"width":1.8}
+		]`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, vv, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 2)
+		require.Equal(t, "ABC-1234", v[1].Registration)
+		require.Equal(t, []internal.LoadIssue{
+			{Index: 1, Id: 1, Kind: internal.LoadIssueDuplicate, Field: "id", Message: "id 1 already loaded"},
+		}, ld.Report().Issues)
+	})
+
+	t.Run("registrations are compared like the repository compares them", func(t *testing.T) {
+		// arrange: the registrations only differ by case and a surrounding space
+		path := writeFile(t, "vehicles.json", `[
+			{"id":1,"brand":"Ford","model":"Fiesta","registration":"ABC-1234","color":"Red","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1000,"height":1.5,"length":4,"width":1.8},
+			{"id":2,"brand":"Ford","model":"Focus","registration":"abc-1234 ","color":"Blue","year":2010,"passengers":5,"max_speed":180,"fuel_type":"gasoline","transmission":"manual","weight":1100,"height":1.5,"length":4,"width":1.8}
+		]`)
+		unique := internal.NewVehicleValidatorRules(internal.RuleUniqueRegistration())
+
+		// act
+		ldExact := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, unique, internal.TextMatchExact)
+		_, errExact := ldExact.Load()
+		ldNormalized := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, unique, internal.TextMatchNormalized)
+		_, errNormalized := ldNormalized.Load()
+
+		// assert
+		require.NoError(t, errExact)
+		require.Empty(t, ldExact.Report().Issues)
+		require.NoError(t, errNormalized)
+		require.Equal(t, 1, ldNormalized.Report().Count(internal.LoadIssueDuplicate))
+		require.Equal(t, "registration", ldNormalized.Report().Issues[0].Field)
+	})
+}
+
+// Tests for the decoding of the vehicles of LoaderVehicleJSON
+func TestLoaderVehicleJSON_Load_Decode(t *testing.T) {
+	t.Run("a field of the wrong type skips the vehicle and names the field", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[{"id":1,"year":"old"}]`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		v, err := ld.Load()
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, v)
+		require.Equal(t, internal.LoadReport{
+			Policy: internal.LoadPolicyWarn, Total: 1, Skipped: 1,
+			Issues: []internal.LoadIssue{{Index: 0, Kind: internal.LoadIssueInvalid, Message: "field year: json: cannot unmarshal string into Go value of type int"}},
+			Counts: map[internal.LoadIssueKind]int{internal.LoadIssueInvalid: 1},
+		}, ld.Report())
+	})
+}
+
+// Tests for the issues kept by the report of LoaderVehicleJSON
+func TestLoaderVehicleJSON_Load_MaxIssues(t *testing.T) {
+	t.Run("keeps the first issues and counts every one", func(t *testing.T) {
+		// arrange: every vehicle misses all of its fields but the id
+		var b strings.Builder
+		b.WriteString("[")
+		for i := 1; i <= internal.LoadReportMaxIssues; i++ {
+			if i > 1 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, `{"id":%d}`, i)
+		}
+		b.WriteString("]")
+		path := writeFile(t, "vehicles.json", b.String())
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyFail, nil, internal.TextMatchExact)
+		missing := internal.LoadReportMaxIssues * (len(vehicleJSONFields) - 1)
+
+		// act
+		_, err := ld.Load()
+
+		// assert
+		require.EqualError(t, err, fmt.Sprintf("loader: invalid vehicles: %d issues found", missing))
+		r := ld.Report()
+		require.Len(t, r.Issues, internal.LoadReportMaxIssues)
+		require.Equal(t, missing-internal.LoadReportMaxIssues, r.Dropped)
+		require.Equal(t, missing, r.Count(internal.LoadIssueMissingField))
+	})
+}
+
+// Tests for the readiness of LoaderVehicleJSON
+func TestLoaderVehicleJSON_Ready(t *testing.T) {
+	t.Run("ready while the file can be opened", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[]`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+
+		// act
+		err := ld.Ready(context.Background())
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("not ready once the file is removed", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "vehicles.json", `[]`)
+		ld := NewLoaderVehicleJSON(path, internal.LoadPolicyWarn, nil, internal.TextMatchExact)
+		require.NoError(t, os.Remove(path))
+
+		// act
+		err := ld.Ready(context.Background())
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrNotReady)
+	})
+}
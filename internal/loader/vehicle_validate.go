@@ -0,0 +1,56 @@
+package loader
+
+import (
+	"app/internal"
+)
+
+// validationIssueKinds are the kinds of the load issues reported for each rule of a validator
+var validationIssueKinds = map[internal.ValidationRule]internal.LoadIssueKind{
+	internal.ValidationRuleRequired: internal.LoadIssueMissingField,
+	internal.ValidationRuleFormat:   internal.LoadIssueInvalidValue,
+	internal.ValidationRuleUnique:   internal.LoadIssueDuplicate,
+	internal.ValidationRuleRange:    internal.LoadIssueOutOfRange,
+	internal.ValidationRuleKnown:    internal.LoadIssueInvalidValue,
+}
+
+// newRegistrationIndex is a function that returns a new instance of registrationIndex
+// - vehicles are the loaded vehicles the ids of the index refer to, they are not copied
+func newRegistrationIndex(vehicles map[int]internal.Vehicle, match internal.TextMatch) *registrationIndex {
+	return &registrationIndex{
+		ids:      make(map[string][]int),
+		vehicles: vehicles,
+		match:    match,
+	}
+}
+
+// registrationIndex is a struct that indexes the loaded vehicles by registration, the registry their uniqueness is checked against
+// - registrations are compared by the key of match, like the repository the vehicles are loaded into does
+type registrationIndex struct {
+	// ids are the ids of the loaded vehicles by registration
+	ids map[string][]int
+	// vehicles are the loaded vehicles by id
+	vehicles map[int]internal.Vehicle
+	// match is the way registrations are compared
+	match internal.TextMatch
+}
+
+// add is a method that indexes a loaded vehicle
+func (ix *registrationIndex) add(v internal.Vehicle) {
+	key := ix.match.Key(v.Registration)
+	ix.ids[key] = append(ix.ids[key], v.Id)
+}
+
+// FindByRegistration is a method that returns a map of the loaded vehicles that match the registration
+func (ix *registrationIndex) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	key := ix.match.Key(registration)
+	for _, id := range ix.ids[key] {
+		if v == nil {
+			v = make(map[int]internal.Vehicle)
+		}
+		v[id] = ix.vehicles[id]
+	}
+	if v == nil {
+		err = internal.ErrRepositoryNotFound
+	}
+	return
+}
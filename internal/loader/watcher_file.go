@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// NewWatcherFilePoll is a function that returns a new instance of WatcherFilePoll
+func NewWatcherFilePoll(path string, interval time.Duration, onChange func()) *WatcherFilePoll {
+	return &WatcherFilePoll{
+		path:     path,
+		interval: interval,
+		onChange: onChange,
+		stop:     make(chan struct{}),
+	}
+}
+
+// WatcherFilePoll is a struct that watches a file for changes by polling its modification time and size
+// - no platform specific notifications are used, so it works on any filesystem
+type WatcherFilePoll struct {
+	// path is the path to the watched file
+	path string
+	// interval is the time between two checks
+	interval time.Duration
+	// onChange is the function called every time the file changes
+	onChange func()
+	// stop is closed to stop watching
+	stop chan struct{}
+	// stopOnce makes Stop safe to call more than once
+	stopOnce sync.Once
+	// done is closed once the watching goroutine returns
+	done chan struct{}
+}
+
+// Start is a method that starts watching the file in a new goroutine
+func (w *WatcherFilePoll) Start() {
+	w.done = make(chan struct{})
+	last, _ := os.Stat(w.path)
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				// a missing file is not a change: it may be in the middle of being replaced
+				info, err := os.Stat(w.path)
+				if err != nil {
+					continue
+				}
+				if last == nil || !info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size() {
+					last = info
+					w.onChange()
+				}
+			}
+		}
+	}()
+}
+
+// Stop is a method that stops watching the file and waits for the watching goroutine to return
+func (w *WatcherFilePoll) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	if w.done != nil {
+		<-w.done
+	}
+}
@@ -0,0 +1,61 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for WatcherFilePoll
+func TestWatcherFilePoll(t *testing.T) {
+	t.Run("calls onChange when the file changes", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+		changes := make(chan struct{}, 1)
+		w := NewWatcherFilePoll(path, 5*time.Millisecond, func() { changes <- struct{}{} })
+		w.Start()
+		defer w.Stop()
+
+		// act
+		require.NoError(t, os.WriteFile(path, []byte(`[{"id":1}]`), 0o644))
+
+		// assert
+		select {
+		case <-changes:
+		case <-time.After(time.Second):
+			t.Fatal("onChange was not called")
+		}
+	})
+
+	t.Run("does not call onChange when the file is untouched or missing", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "vehicles.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+		changes := make(chan struct{}, 1)
+		w := NewWatcherFilePoll(path, 5*time.Millisecond, func() { changes <- struct{}{} })
+		w.Start()
+		defer w.Stop()
+
+		// act
+		time.Sleep(20 * time.Millisecond)
+		require.NoError(t, os.Remove(path))
+		time.Sleep(20 * time.Millisecond)
+
+		// assert
+		require.Empty(t, changes)
+	})
+
+	t.Run("stop is idempotent", func(t *testing.T) {
+		// arrange
+		w := NewWatcherFilePoll(filepath.Join(t.TempDir(), "vehicles.json"), time.Millisecond, func() {})
+		w.Start()
+
+		// act
+		w.Stop()
+		w.Stop()
+	})
+}
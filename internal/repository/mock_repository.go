@@ -17,6 +17,18 @@ func (m *MockRepository) FindAll() (v map[int]internal.Vehicle, err error) {
 	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
 }
 
+// FindByID is a method that returns the vehicle with the id
+func (m *MockRepository) FindByID(id int) (v internal.Vehicle, err error) {
+	args := m.Called(id)
+	return args.Get(0).(internal.Vehicle), args.Error(1)
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (m *MockRepository) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	args := m.Called(registration)
+	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
+}
+
 // FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 func (m *MockRepository) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
 	args := m.Called(color, fabricationYear)
@@ -40,3 +52,79 @@ func (m *MockRepository) FindByWeightRange(fromWeight float64, toWeight float64)
 	args := m.Called(fromWeight, toWeight)
 	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
 }
+
+// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+func (m *MockRepository) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	args := m.Called(filter)
+	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
+}
+
+// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+func (m *MockRepository) FindPage(filter internal.VehicleFilter, page internal.PageQuery) (p internal.VehiclePage, err error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(internal.VehiclePage), args.Error(1)
+}
+
+// Save is a method that saves a new vehicle and sets its id
+// - the check, if any, runs first with the mock as the registry, the call is not recorded if it fails
+func (m *MockRepository) Save(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	if check != nil {
+		err = check(internal.Vehicle{VehicleAttributes: v.VehicleAttributes}, m)
+		if err != nil {
+			return
+		}
+	}
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// Update is a method that replaces an existing vehicle
+// - the check, if any, runs first with the mock as the registry, the call is not recorded if it fails
+func (m *MockRepository) Update(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	if check != nil {
+		err = check(*v, m)
+		if err != nil {
+			return
+		}
+	}
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// Patch is a method that applies a partial update to an existing vehicle and returns the result
+// - the check, if any, runs over the returned vehicle with the mock as the registry
+func (m *MockRepository) Patch(id int, patch internal.VehiclePatch, check internal.VehicleWriteCheck) (v internal.Vehicle, err error) {
+	args := m.Called(id, patch)
+	v, err = args.Get(0).(internal.Vehicle), args.Error(1)
+	if err == nil && check != nil {
+		err = check(v, m)
+		if err != nil {
+			v = internal.Vehicle{}
+		}
+	}
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (m *MockRepository) Delete(id int) (err error) {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// Replace is a method that atomically replaces all the vehicles and reports the differences
+func (m *MockRepository) Replace(v map[int]internal.Vehicle) (r internal.ReloadReport, err error) {
+	args := m.Called(v)
+	return args.Get(0).(internal.ReloadReport), args.Error(1)
+}
+
+// ReplaceUnwritten is a method that replaces all the vehicles, only if none was written since the last replace
+func (m *MockRepository) ReplaceUnwritten(v map[int]internal.Vehicle) (r internal.ReloadReport, err error) {
+	args := m.Called(v)
+	return args.Get(0).(internal.ReloadReport), args.Error(1)
+}
+
+// Search is a method that returns the vehicles that match every word of the text
+func (m *MockRepository) Search(text string, limit int) (r internal.VehicleSearchResult, err error) {
+	args := m.Called(text, limit)
+	return args.Get(0).(internal.VehicleSearchResult), args.Error(1)
+}
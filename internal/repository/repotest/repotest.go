@@ -0,0 +1,582 @@
+// Package repotest provides a conformance test suite for implementations of internal.RepositoryReadVehicle.
+// A new backend proves it behaves like the map repository by running it from its own tests:
+//
+//	func TestRepositoryVehicleFoo_Conformance(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+//			return NewRepositoryVehicleFoo(db)
+//		})
+//	}
+//
+// A backend that can be written runs RunWrite the same way, with a factory returning a Repository.
+package repotest
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory is a function that returns a repository holding exactly the vehicles of db
+// - it is called once per test case, every repository must be independent from the others
+type Factory func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle
+
+// Fixture is a function that returns the vehicles the suite runs against
+// - brands and colors differ only in case, years and weights sit on the boundaries of the queried ranges
+func Fixture() map[int]internal.Vehicle {
+	return map[int]internal.Vehicle{
+		1: vehicle(1, "Ford", "Fiesta", "Red", 2009, 999.99),
+		2: vehicle(2, "Ford", "Focus", "Blue", 2010, 1000),
+		3: vehicle(3, "ford", "Ka", "red", 2010, 1100),
+		4: vehicle(4, "Fiat", "Uno", "Red", 2010, 1200),
+		5: vehicle(5, "Fiat", "Palio", "Red", 2012, 1200.01),
+	}
+}
+
+// vehicle is a function that returns a vehicle of the fixture
+func vehicle(id int, brand, model, color string, year int, weight float64) internal.Vehicle {
+	return internal.Vehicle{
+		Id: id,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           brand,
+			Model:           model,
+			Registration:    "REG-" + model,
+			Color:           color,
+			FabricationYear: year,
+			Capacity:        5,
+			MaxSpeed:        180.5,
+			FuelType:        "Gasoline",
+			Transmission:    "Manual",
+			Weight:          weight,
+			Dimensions: internal.Dimensions{
+				Height: 1.5,
+				Length: 4.2,
+				Width:  1.8,
+			},
+		},
+	}
+}
+
+// pick is a function that returns the vehicles of the fixture with the given ids
+func pick(ids ...int) (v map[int]internal.Vehicle) {
+	fixture := Fixture()
+	v = make(map[int]internal.Vehicle, len(ids))
+	for _, id := range ids {
+		v[id] = fixture[id]
+	}
+	return
+}
+
+// ptr is a function that returns a pointer to v, to build filters
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// TestCase is a struct that represents a conformance test case of a read method
+type TestCase struct {
+	// name is the name of the test case
+	name string
+	// db are the vehicles the repository is created with
+	db map[int]internal.Vehicle
+	// find calls the read method under test
+	find func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error)
+	// expected are the vehicles the read method must return
+	expected map[int]internal.Vehicle
+}
+
+// Run is a function that runs the conformance suite against the repositories built by factory
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	// Create the test cases
+	testCases := []TestCase{
+		// FindAll
+		{
+			name:     "FindAll/should return every vehicle",
+			db:       Fixture(),
+			find:     func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) { return rp.FindAll() },
+			expected: Fixture(),
+		},
+		{
+			name:     "FindAll/should return an empty map when there are no vehicles",
+			find:     func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) { return rp.FindAll() },
+			expected: map[int]internal.Vehicle{},
+		},
+		// FindByColorAndYear
+		{
+			name: "FindByColorAndYear/should return the vehicles that match the color and year",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByColorAndYear("Red", 2010)
+			},
+			expected: pick(4),
+		},
+		{
+			name: "FindByColorAndYear/should match the color case sensitively",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByColorAndYear("red", 2010)
+			},
+			expected: pick(3),
+		},
+		{
+			name: "FindByColorAndYear/should return an empty map when no vehicle matches",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByColorAndYear("Red", 2011)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByColorAndYear/should return an empty map when there are no vehicles",
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByColorAndYear("Red", 2010)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		// FindByBrandAndYearRange
+		{
+			name: "FindByBrandAndYearRange/should include both boundary years",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("Fiat", 2010, 2012)
+			},
+			expected: pick(4, 5),
+		},
+		{
+			name: "FindByBrandAndYearRange/should match a single year range",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("Ford", 2009, 2009)
+			},
+			expected: pick(1),
+		},
+		{
+			name: "FindByBrandAndYearRange/should exclude the years outside the range",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("Fiat", 2011, 2011)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByBrandAndYearRange/should match the brand case sensitively",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("ford", 2000, 2020)
+			},
+			expected: pick(3),
+		},
+		{
+			name: "FindByBrandAndYearRange/should return an empty map when the range is inverted",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("Ford", 2012, 2009)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByBrandAndYearRange/should return an empty map when there are no vehicles",
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("Ford", 2000, 2020)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		// FindByBrand
+		{
+			name: "FindByBrand/should return the vehicles that match the brand",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrand("Ford")
+			},
+			expected: pick(1, 2),
+		},
+		{
+			name: "FindByBrand/should match the brand case sensitively",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrand("FORD")
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByBrand/should return an empty map when there are no vehicles",
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrand("Ford")
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		// FindByWeightRange
+		{
+			name: "FindByWeightRange/should include both boundary weights",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByWeightRange(1000, 1200)
+			},
+			expected: pick(2, 3, 4),
+		},
+		{
+			name: "FindByWeightRange/should match a single weight range",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByWeightRange(1100, 1100)
+			},
+			expected: pick(3),
+		},
+		{
+			name: "FindByWeightRange/should return an empty map when the range is inverted",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByWeightRange(1200, 1000)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByWeightRange/should return an empty map when there are no vehicles",
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByWeightRange(0, 10000)
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		// FindByFilter
+		{
+			name: "FindByFilter/should return every vehicle when the filter is empty",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{})
+			},
+			expected: Fixture(),
+		},
+		{
+			name: "FindByFilter/should combine the criteria",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{Brand: ptr("Ford"), YearMin: ptr(2010), FuelType: ptr("Gasoline")})
+			},
+			expected: pick(2),
+		},
+		{
+			name: "FindByFilter/should match a color and a single year",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{Color: ptr("Red"), YearMin: ptr(2010), YearMax: ptr(2010)})
+			},
+			expected: pick(4),
+		},
+		{
+			name: "FindByFilter/should match the strings case sensitively",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{Brand: ptr("ford"), Color: ptr("red")})
+			},
+			expected: pick(3),
+		},
+		{
+			name: "FindByFilter/should include the lower weight bound of an open range",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{WeightMin: ptr(1000.0)})
+			},
+			expected: pick(2, 3, 4, 5),
+		},
+		{
+			name: "FindByFilter/should include the upper weight bound of an open range",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{WeightMax: ptr(1000.0)})
+			},
+			expected: pick(1, 2),
+		},
+		{
+			name: "FindByFilter/should include both boundary years",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{YearMin: ptr(2009), YearMax: ptr(2010), Model: ptr("Fiesta")})
+			},
+			expected: pick(1),
+		},
+		{
+			name: "FindByFilter/should match the speed and capacity bounds",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{SpeedMin: ptr(180.5), SpeedMax: ptr(180.5), CapacityMin: ptr(5), CapacityMax: ptr(5), Transmission: ptr("Manual")})
+			},
+			expected: Fixture(),
+		},
+		{
+			name: "FindByFilter/should return an empty map when the range is inverted",
+			db:   Fixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{WeightMin: ptr(1200.0), WeightMax: ptr(1000.0)})
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			name: "FindByFilter/should return an empty map when there are no vehicles",
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{Brand: ptr("Ford")})
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+	}
+
+	// Run the test cases
+	runCases(t, factory, testCases)
+	runPage(t, factory)
+}
+
+// runCases is a function that runs conformance test cases of the read methods
+func runCases(t *testing.T, factory Factory, testCases []TestCase) {
+	t.Helper()
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			db := testCase.db
+			if db == nil {
+				db = map[int]internal.Vehicle{}
+			}
+			rp := factory(t, db)
+
+			// Act
+			obtained, err := testCase.find(rp)
+
+			// Assert
+			require.NoError(t, err)
+			assert.NotNil(t, obtained)
+			assert.Equal(t, testCase.expected, obtained)
+
+			// - the returned map must be a copy: mutating it does not change what the repository returns next
+			for id := range obtained {
+				obtained[id] = internal.Vehicle{}
+				delete(obtained, id)
+			}
+			obtained[-1] = internal.Vehicle{Id: -1}
+			again, err := testCase.find(rp)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, again)
+		})
+	}
+}
+
+// NormalizedFixture is a function that returns the vehicles the normalized suite runs against
+// - the fixture plus vehicles with surrounding spaces, other cases and a decomposed diacritic
+func NormalizedFixture() (db map[int]internal.Vehicle) {
+	db = Fixture()
+	db[6] = vehicle(6, " FORD ", "Fiesta ", "Red ", 2010, 1300)
+	db[7] = vehicle(7, "Citroe\u0308n", "C3", "Gr\u00fcn", 2011, 1050)
+	return
+}
+
+// normalizedPick is a function that returns the vehicles of the normalized fixture with the given ids, in their stored form
+func normalizedPick(ids ...int) (v map[int]internal.Vehicle) {
+	fixture := NormalizedFixture()
+	v = make(map[int]internal.Vehicle, len(ids))
+	for _, id := range ids {
+		v[id] = internal.TextMatchNormalized.Vehicle(fixture[id])
+	}
+	return
+}
+
+// RunNormalized is a function that runs the normalized matching suite against the repositories built by factory
+// - factory must build repositories that compare texts with internal.TextMatchNormalized
+func RunNormalized(t *testing.T, factory Factory) {
+	t.Helper()
+
+	// Create the test cases
+	testCases := []TestCase{
+		{
+			name:     "FindAll/should return the vehicles trimmed and in NFC",
+			db:       NormalizedFixture(),
+			find:     func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) { return rp.FindAll() },
+			expected: normalizedPick(1, 2, 3, 4, 5, 6, 7),
+		},
+		{
+			name: "FindByBrand/should match the brand whatever its case and spaces",
+			db:   NormalizedFixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrand("ford ")
+			},
+			expected: normalizedPick(1, 2, 3, 6),
+		},
+		{
+			name: "FindByBrand/should match the brand whatever its Unicode form",
+			db:   NormalizedFixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrand("CITRO\u00cbN")
+			},
+			expected: normalizedPick(7),
+		},
+		{
+			name: "FindByColorAndYear/should match the color whatever its case",
+			db:   NormalizedFixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByColorAndYear("RED", 2010)
+			},
+			expected: normalizedPick(3, 4, 6),
+		},
+		{
+			name: "FindByBrandAndYearRange/should match the brand whatever its case",
+			db:   NormalizedFixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByBrandAndYearRange("FORD", 2010, 2012)
+			},
+			expected: normalizedPick(2, 3, 6),
+		},
+		{
+			name: "FindByFilter/should match every text criteria whatever its case and spaces",
+			db:   NormalizedFixture(),
+			find: func(rp internal.RepositoryReadVehicle) (map[int]internal.Vehicle, error) {
+				return rp.FindByFilter(internal.VehicleFilter{Brand: ptr("ford"), Model: ptr(" FIESTA"), Color: ptr("red"), Transmission: ptr("manual")})
+			},
+			expected: normalizedPick(1, 6),
+		},
+	}
+
+	// Run the test cases
+	runCases(t, factory, testCases)
+}
+
+// PageTestCase is a struct that represents a conformance test case of FindPage
+type PageTestCase struct {
+	// name is the name of the test case
+	name string
+	// db are the vehicles the repository is created with
+	db map[int]internal.Vehicle
+	// filter is the filter of the page
+	filter internal.VehicleFilter
+	// page is the order and window of the page
+	page internal.PageQuery
+	// expectedIds are the ids of the vehicles the page must return, in order
+	expectedIds []int
+	// expectedTotal is the number of vehicles that match the filter
+	expectedTotal int
+	// expectedMore is true if vehicles must follow the window
+	expectedMore bool
+	// isError is true if the page is invalid
+	isError bool
+}
+
+// runPage is a function that runs the conformance test cases of FindPage
+func runPage(t *testing.T, factory Factory) {
+	t.Helper()
+
+	// Create the test cases
+	testCases := []PageTestCase{
+		{
+			name:          "FindPage/should sort by id when no sort is given",
+			db:            Fixture(),
+			expectedIds:   []int{1, 2, 3, 4, 5},
+			expectedTotal: 5,
+		},
+		{
+			name:          "FindPage/should sort by several fields and break ties by id",
+			db:            Fixture(),
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}, {Field: internal.SortByBrand}}},
+			expectedIds:   []int{5, 4, 2, 3, 1},
+			expectedTotal: 5,
+		},
+		{
+			name:          "FindPage/should sort strings case sensitively",
+			db:            Fixture(),
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByColor}, {Field: internal.SortById, Desc: true}}},
+			expectedIds:   []int{2, 5, 4, 1, 3},
+			expectedTotal: 5,
+		},
+		{
+			name:          "FindPage/should return the window of the filtered vehicles",
+			db:            Fixture(),
+			filter:        internal.VehicleFilter{Color: ptr("Red")},
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByWeight, Desc: true}}, Offset: 1, Limit: 2},
+			expectedIds:   []int{4, 1},
+			expectedTotal: 3,
+		},
+		{
+			name:          "FindPage/should tell that vehicles follow the window",
+			db:            Fixture(),
+			page:          internal.PageQuery{Limit: 4},
+			expectedIds:   []int{1, 2, 3, 4},
+			expectedTotal: 5,
+			expectedMore:  true,
+		},
+		{
+			name:          "FindPage/should seek past the last vehicle of the previous page",
+			db:            Fixture(),
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}, {Field: internal.SortByBrand}}, After: ptr(Fixture()[4]), Limit: 2},
+			expectedIds:   []int{2, 3},
+			expectedTotal: 5,
+			expectedMore:  true,
+		},
+		{
+			name:          "FindPage/should seek past a vehicle that is no longer stored",
+			db:            Fixture(),
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}, {Field: internal.SortByBrand}}, After: ptr(vehicle(6, "Fiat", "Tipo", "Red", 2010, 1000)), Limit: 3},
+			expectedIds:   []int{2, 3, 1},
+			expectedTotal: 5,
+		},
+		{
+			name:          "FindPage/should seek past the ties by id",
+			db:            Fixture(),
+			filter:        internal.VehicleFilter{Brand: ptr("Fiat")},
+			page:          internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByColor}}, After: ptr(Fixture()[4])},
+			expectedIds:   []int{5},
+			expectedTotal: 2,
+		},
+		{
+			name:          "FindPage/should return an empty window past the end",
+			db:            Fixture(),
+			page:          internal.PageQuery{Offset: 10, Limit: 2},
+			expectedIds:   []int{},
+			expectedTotal: 5,
+		},
+		{
+			name:          "FindPage/should return an empty page when there are no vehicles",
+			page:          internal.PageQuery{Limit: 2},
+			expectedIds:   []int{},
+			expectedTotal: 0,
+		},
+		{
+			name:    "FindPage/should fail on an unknown sort field",
+			db:      Fixture(),
+			page:    internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: "id; DROP TABLE vehicles"}}},
+			isError: true,
+		},
+		{
+			name:    "FindPage/should fail on a negative offset",
+			db:      Fixture(),
+			page:    internal.PageQuery{Offset: -1},
+			isError: true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			db := testCase.db
+			if db == nil {
+				db = map[int]internal.Vehicle{}
+			}
+			rp := factory(t, db)
+
+			// Act
+			obtained, err := rp.FindPage(testCase.filter, testCase.page)
+
+			// Assert
+			if testCase.isError {
+				assert.ErrorIs(t, err, internal.ErrRepositoryInvalidFind)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedTotal, obtained.Total)
+			assert.Equal(t, testCase.expectedMore, obtained.More)
+			ids := make([]int, 0, len(obtained.Vehicles))
+			for _, v := range obtained.Vehicles {
+				ids = append(ids, v.Id)
+				assert.Equal(t, db[v.Id], v)
+			}
+			assert.Equal(t, testCase.expectedIds, ids)
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package repotest
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repository is an interface that represents a repository that can be written and replaced
+type Repository interface {
+	internal.RepositoryVehicle
+	internal.RepositoryReplaceVehicle
+}
+
+// WriteFactory is a function that returns a writable repository holding exactly the vehicles of db
+// - it is called once per test case, every repository must be independent from the others
+type WriteFactory func(t *testing.T, db map[int]internal.Vehicle) Repository
+
+// WriteTestCase is a struct that represents a conformance test case of a write method
+type WriteTestCase struct {
+	// name is the name of the test case
+	name string
+	// write calls the write method under test, the repository holds the fixture
+	write func(t *testing.T, rp Repository) (err error)
+	// expectedError is the error the write method must wrap, nil if it must succeed
+	expectedError error
+	// expected are the vehicles the repository must hold after the write
+	expected map[int]internal.Vehicle
+}
+
+// RunWrite is a function that runs the write conformance suite against the repositories built by factory
+// - the repositories compare texts with internal.TextMatchExact
+func RunWrite(t *testing.T, factory WriteFactory) {
+	t.Helper()
+
+	saved := vehicle(0, "Chevrolet", "Onix", "White", 2020, 1050)
+	updated := vehicle(1, "Ford", "Fiesta", "Black", 2011, 999.99)
+	patched := Fixture()[2]
+	patched.Color = "Green"
+	fixtureWith := func(v internal.Vehicle) (db map[int]internal.Vehicle) {
+		db = Fixture()
+		db[v.Id] = v
+		return
+	}
+
+	// Create the test cases
+	testCases := []WriteTestCase{
+		// Save
+		{
+			name: "Save/should save the vehicle with the next available id",
+			write: func(t *testing.T, rp Repository) error {
+				v := saved
+				err := rp.Save(&v, nil)
+				assert.Equal(t, 6, v.Id)
+				return err
+			},
+			expected: fixtureWith(vehicle(6, "Chevrolet", "Onix", "White", 2020, 1050)),
+		},
+		// Update
+		{
+			name: "Update/should replace an existing vehicle",
+			write: func(t *testing.T, rp Repository) error {
+				v := updated
+				return rp.Update(&v, nil)
+			},
+			expected: fixtureWith(updated),
+		},
+		{
+			name: "Update/should return ErrRepositoryNotFound when the vehicle does not exist",
+			write: func(t *testing.T, rp Repository) error {
+				v := vehicle(10, "Ford", "Ka", "Red", 2010, 1100)
+				return rp.Update(&v, nil)
+			},
+			expectedError: internal.ErrRepositoryNotFound,
+			expected:      Fixture(),
+		},
+		{
+			name: "Save/should keep the vehicles when the check fails",
+			write: func(t *testing.T, rp Repository) error {
+				v := saved
+				return rp.Save(&v, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+					// the check sees the stored vehicles through the registry
+					_, err := registry.FindByRegistration("REG-Uno")
+					assert.NoError(t, err)
+					assert.Zero(t, v.Id)
+					return internal.ErrValidatorInvalidVehicle
+				})
+			},
+			expectedError: internal.ErrValidatorInvalidVehicle,
+			expected:      Fixture(),
+		},
+		{
+			name: "Update/should keep the vehicle when the check fails",
+			write: func(t *testing.T, rp Repository) error {
+				v := updated
+				return rp.Update(&v, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+					_, err := registry.FindByRegistration("REG-Uno")
+					assert.NoError(t, err)
+					return internal.ErrValidatorInvalidVehicle
+				})
+			},
+			expectedError: internal.ErrValidatorInvalidVehicle,
+			expected:      Fixture(),
+		},
+		{
+			name: "Update/should return ErrRepositoryNotFound before running the check",
+			write: func(t *testing.T, rp Repository) error {
+				v := vehicle(10, "Ford", "Ka", "Red", 2010, 1100)
+				return rp.Update(&v, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+					t.Error("the check must not run for a missing vehicle")
+					return nil
+				})
+			},
+			expectedError: internal.ErrRepositoryNotFound,
+			expected:      Fixture(),
+		},
+		// Patch
+		{
+			name: "Patch/should change only the patched fields",
+			write: func(t *testing.T, rp Repository) error {
+				v, err := rp.Patch(2, internal.VehiclePatch{Color: ptr("Green")}, nil)
+				assert.Equal(t, patched, v)
+				return err
+			},
+			expected: fixtureWith(patched),
+		},
+		{
+			name: "Patch/should keep the vehicle when the check fails",
+			write: func(t *testing.T, rp Repository) error {
+				_, err := rp.Patch(2, internal.VehiclePatch{Color: ptr("Green")}, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+					return internal.ErrValidatorInvalidVehicle
+				})
+				return err
+			},
+			expectedError: internal.ErrValidatorInvalidVehicle,
+			expected:      Fixture(),
+		},
+		{
+			name: "Patch/should return ErrRepositoryNotFound when the vehicle does not exist",
+			write: func(t *testing.T, rp Repository) error {
+				_, err := rp.Patch(10, internal.VehiclePatch{Color: ptr("Green")}, nil)
+				return err
+			},
+			expectedError: internal.ErrRepositoryNotFound,
+			expected:      Fixture(),
+		},
+		// Delete
+		{
+			name:     "Delete/should delete an existing vehicle",
+			write:    func(t *testing.T, rp Repository) error { return rp.Delete(2) },
+			expected: pick(1, 3, 4, 5),
+		},
+		{
+			name:          "Delete/should return ErrRepositoryNotFound when the vehicle does not exist",
+			write:         func(t *testing.T, rp Repository) error { return rp.Delete(10) },
+			expectedError: internal.ErrRepositoryNotFound,
+			expected:      Fixture(),
+		},
+		// Replace
+		{
+			name: "Replace/should swap the vehicles and report added, changed and removed ones",
+			write: func(t *testing.T, rp Repository) error {
+				r, err := rp.Replace(map[int]internal.Vehicle{1: updated, 2: Fixture()[2], 6: vehicle(6, "Chevrolet", "Onix", "White", 2020, 1050)})
+				assert.Equal(t, internal.ReloadReport{Added: 1, Changed: 1, Removed: 3}, r)
+				return err
+			},
+			expected: map[int]internal.Vehicle{1: updated, 2: Fixture()[2], 6: vehicle(6, "Chevrolet", "Onix", "White", 2020, 1050)},
+		},
+		{
+			name: "Replace/should remove every vehicle when the new db is empty",
+			write: func(t *testing.T, rp Repository) error {
+				r, err := rp.Replace(map[int]internal.Vehicle{})
+				assert.Equal(t, internal.ReloadReport{Removed: 5}, r)
+				return err
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		// ReplaceUnwritten
+		{
+			name: "ReplaceUnwritten/should replace the vehicles when none was written",
+			write: func(t *testing.T, rp Repository) error {
+				r, err := rp.ReplaceUnwritten(pick(1))
+				assert.Equal(t, internal.ReloadReport{Removed: 4}, r)
+				return err
+			},
+			expected: pick(1),
+		},
+		{
+			name: "ReplaceUnwritten/should keep the vehicles written by a save",
+			write: func(t *testing.T, rp Repository) error {
+				v := saved
+				if err := rp.Save(&v, nil); err != nil {
+					return err
+				}
+				_, err := rp.ReplaceUnwritten(pick(1))
+				return err
+			},
+			expectedError: internal.ErrRepositoryWritten,
+			expected:      fixtureWith(vehicle(6, "Chevrolet", "Onix", "White", 2020, 1050)),
+		},
+		{
+			name: "ReplaceUnwritten/should keep the vehicles written by an update",
+			write: func(t *testing.T, rp Repository) error {
+				v := updated
+				if err := rp.Update(&v, nil); err != nil {
+					return err
+				}
+				_, err := rp.ReplaceUnwritten(pick(1))
+				return err
+			},
+			expectedError: internal.ErrRepositoryWritten,
+			expected:      fixtureWith(updated),
+		},
+		{
+			name: "ReplaceUnwritten/should keep the vehicles written by a patch",
+			write: func(t *testing.T, rp Repository) error {
+				if _, err := rp.Patch(2, internal.VehiclePatch{Color: ptr("Green")}, nil); err != nil {
+					return err
+				}
+				_, err := rp.ReplaceUnwritten(pick(1))
+				return err
+			},
+			expectedError: internal.ErrRepositoryWritten,
+			expected:      fixtureWith(patched),
+		},
+		{
+			name: "ReplaceUnwritten/should keep the vehicles written by a delete",
+			write: func(t *testing.T, rp Repository) error {
+				if err := rp.Delete(2); err != nil {
+					return err
+				}
+				_, err := rp.ReplaceUnwritten(pick(1))
+				return err
+			},
+			expectedError: internal.ErrRepositoryWritten,
+			expected:      pick(1, 3, 4, 5),
+		},
+		{
+			name: "ReplaceUnwritten/should replace the vehicles once a replace discarded the writes",
+			write: func(t *testing.T, rp Repository) error {
+				if err := rp.Delete(2); err != nil {
+					return err
+				}
+				if _, err := rp.Replace(Fixture()); err != nil {
+					return err
+				}
+				_, err := rp.ReplaceUnwritten(pick(1))
+				return err
+			},
+			expected: pick(1),
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			rp := factory(t, Fixture())
+
+			// Act
+			err := testCase.write(t, rp)
+
+			// Assert
+			if testCase.expectedError != nil {
+				assert.ErrorIs(t, err, testCase.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			obtained, err := rp.FindAll()
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, obtained)
+		})
+	}
+
+	runFind(t, factory)
+}
+
+// runFind is a function that runs the conformance test cases of the lookups by id and registration
+func runFind(t *testing.T, factory WriteFactory) {
+	t.Helper()
+
+	t.Run("FindByID/should return the vehicle with the id", func(t *testing.T) {
+		rp := factory(t, Fixture())
+
+		v, err := rp.FindByID(2)
+
+		require.NoError(t, err)
+		assert.Equal(t, Fixture()[2], v)
+	})
+
+	t.Run("FindByID/should return ErrRepositoryNotFound when the vehicle does not exist", func(t *testing.T) {
+		rp := factory(t, Fixture())
+
+		_, err := rp.FindByID(10)
+
+		assert.ErrorIs(t, err, internal.ErrRepositoryNotFound)
+	})
+
+	t.Run("FindByRegistration/should return every vehicle sharing the registration", func(t *testing.T) {
+		rp := factory(t, Fixture())
+		v := vehicle(0, "Chevrolet", "Uno", "White", 2020, 1050)
+		require.NoError(t, rp.Save(&v, nil))
+
+		obtained, err := rp.FindByRegistration("REG-Uno")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[int]internal.Vehicle{4: Fixture()[4], v.Id: v}, obtained)
+	})
+
+	t.Run("FindByRegistration/should return ErrRepositoryNotFound when no vehicle matches", func(t *testing.T) {
+		rp := factory(t, Fixture())
+
+		obtained, err := rp.FindByRegistration("XYZ-0000")
+
+		assert.ErrorIs(t, err, internal.ErrRepositoryNotFound)
+		assert.Nil(t, obtained)
+	})
+}
+
+// RunWriteNormalized is a function that runs the normalized writing suite against the repositories built by factory
+// - factory must build repositories that compare texts with internal.TextMatchNormalized
+func RunWriteNormalized(t *testing.T, factory WriteFactory) {
+	t.Helper()
+
+	t.Run("Save/should trim the texts and match them whatever their case", func(t *testing.T) {
+		// Arrange
+		rp := factory(t, Fixture())
+		v := vehicle(0, " fiat ", "Strada", "Red ", 2012, 1100)
+		v.Registration = " ABC-1237"
+
+		// Act
+		err := rp.Save(&v, nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "fiat", v.Brand)
+		assert.Equal(t, "ABC-1237", v.Registration)
+		assert.Equal(t, "Red", v.Color)
+		obtained, err := rp.FindByBrand("FIAT")
+		require.NoError(t, err)
+		assert.Len(t, obtained, 3)
+		assert.Equal(t, v, obtained[v.Id])
+		obtained, err = rp.FindByRegistration("abc-1237 ")
+		require.NoError(t, err)
+		assert.Equal(t, map[int]internal.Vehicle{v.Id: v}, obtained)
+	})
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"app/internal"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// NewRepositoryVehicleDataset is a function that returns a new instance of RepositoryVehicleDataset
+// - the summary of the vehicles of the repository is computed right away
+func NewRepositoryVehicleDataset(rp RepositoryVehicleReplaceable) (r *RepositoryVehicleDataset, err error) {
+	r = &RepositoryVehicleDataset{RepositoryVehicleReplaceable: rp}
+	_, err = r.Info()
+	if err != nil {
+		r = nil
+	}
+	return
+}
+
+// RepositoryVehicleDataset is a struct that adds a summary of its vehicles to a vehicle repository, it implements the DatasetVehicle interface
+// - reads and writes are delegated to the repository, writes only count themselves so they stay as cheap and as concurrent as the repository makes them
+// - the summary is recomputed by the first Info after a write or a replace, and read from memory until the next one
+type RepositoryVehicleDataset struct {
+	RepositoryVehicleReplaceable
+	// written is the number of writes and replaces that succeeded
+	written atomic.Uint64
+	// mu guards info, infoWritten and computed, Info holds it while computing so a summary is computed once for concurrent calls
+	mu sync.Mutex
+	// info is the last summary computed
+	info internal.DatasetInfo
+	// infoWritten is the number of writes info was computed after, info is stale once written moves past it
+	infoWritten uint64
+	// computed is set once info has been computed
+	computed bool
+}
+
+// Info is a method that returns the summary of the vehicles currently served
+// - the summary is recomputed only if a write or a replace succeeded since it was last computed
+func (r *RepositoryVehicleDataset) Info() (i internal.DatasetInfo, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// - written is read before the vehicles, so a write racing the computation makes the summary stale rather than lost
+	written := r.written.Load()
+	if r.computed && r.infoWritten == written {
+		i = r.info
+		return
+	}
+
+	v, err := r.RepositoryVehicleReplaceable.FindAll()
+	if err != nil {
+		return
+	}
+	i, err = datasetInfo(v)
+	if err != nil {
+		return
+	}
+	r.info, r.infoWritten, r.computed = i, written, true
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+func (r *RepositoryVehicleDataset) Save(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	err = r.RepositoryVehicleReplaceable.Save(v, check)
+	r.count(err)
+	return
+}
+
+// Update is a method that replaces an existing vehicle
+func (r *RepositoryVehicleDataset) Update(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	err = r.RepositoryVehicleReplaceable.Update(v, check)
+	r.count(err)
+	return
+}
+
+// Patch is a method that applies a partial update to an existing vehicle as a single write and returns the result
+func (r *RepositoryVehicleDataset) Patch(id int, patch internal.VehiclePatch, check internal.VehicleWriteCheck) (v internal.Vehicle, err error) {
+	v, err = r.RepositoryVehicleReplaceable.Patch(id, patch, check)
+	r.count(err)
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (r *RepositoryVehicleDataset) Delete(id int) (err error) {
+	err = r.RepositoryVehicleReplaceable.Delete(id)
+	r.count(err)
+	return
+}
+
+// Replace is a method that atomically replaces all the vehicles and reports the differences
+func (r *RepositoryVehicleDataset) Replace(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.RepositoryVehicleReplaceable.Replace(v)
+	r.count(err)
+	return
+}
+
+// ReplaceUnwritten is a method that replaces all the vehicles, only if none was written since the last replace
+func (r *RepositoryVehicleDataset) ReplaceUnwritten(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.RepositoryVehicleReplaceable.ReplaceUnwritten(v)
+	r.count(err)
+	return
+}
+
+// count is a method that counts a write or a replace once it succeeded, making the summary stale
+func (r *RepositoryVehicleDataset) count(err error) {
+	if err == nil {
+		r.written.Add(1)
+	}
+}
+
+// datasetInfo is a function that returns the summary of the vehicles
+// - the checksum is computed over the vehicles ordered by id, one JSON document each, so it does not depend on the backend
+func datasetInfo(v map[int]internal.Vehicle) (i internal.DatasetInfo, err error) {
+	// order vehicles
+	ids := make([]int, 0, len(v))
+	for id := range v {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	// checksum
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, id := range ids {
+		err = enc.Encode(v[id])
+		if err != nil {
+			return
+		}
+	}
+
+	i = internal.DatasetInfo{Checksum: hex.EncodeToString(h.Sum(nil)), Count: len(v)}
+	return
+}
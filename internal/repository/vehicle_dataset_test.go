@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/repository/repotest"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositoryVehicleDataset_Write is a test function that runs the write conformance suite against RepositoryVehicleDataset
+func TestRepositoryVehicleDataset_Write(t *testing.T) {
+	repotest.RunWrite(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		rp, err := NewRepositoryVehicleDataset(NewRepositoryReadVehicleMap(db, internal.TextMatchExact))
+		require.NoError(t, err)
+		return rp
+	})
+}
+
+// DatasetInfoTestCase is a struct that represents a test case for the Info method
+type DatasetInfoTestCase struct {
+	// name is the name of the test case
+	name string
+	// write changes the vehicles of the repository, nil to keep the loaded ones
+	write func(rp *RepositoryVehicleDataset) error
+	// expected are the vehicles the summary must describe
+	expected map[int]internal.Vehicle
+}
+
+// TestRepositoryVehicleDataset_Info is a test function that tests the Info method
+func TestRepositoryVehicleDataset_Info(t *testing.T) {
+	// Create the test cases
+	testCases := []DatasetInfoTestCase{
+		{
+			// This test evaluates that Info describes the loaded vehicles
+			name:     "should describe the loaded vehicles",
+			expected: fixture(),
+		},
+		{
+			// This test evaluates that Info follows a save
+			name: "should follow a save",
+			write: func(rp *RepositoryVehicleDataset) error {
+				v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Registration: "XYZ-0001"}}
+				return rp.Save(&v, nil)
+			},
+			expected: func() map[int]internal.Vehicle {
+				db := fixture()
+				db[4] = internal.Vehicle{Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Registration: "XYZ-0001"}}
+				return db
+			}(),
+		},
+		{
+			// This test evaluates that Info follows a patch
+			name: "should follow a patch",
+			write: func(rp *RepositoryVehicleDataset) error {
+				color := "Black"
+				_, err := rp.Patch(1, internal.VehiclePatch{Color: &color}, nil)
+				return err
+			},
+			expected: func() map[int]internal.Vehicle {
+				db := fixture()
+				v := db[1]
+				v.Color = "Black"
+				db[1] = v
+				return db
+			}(),
+		},
+		{
+			// This test evaluates that Info follows a delete
+			name:  "should follow a delete",
+			write: func(rp *RepositoryVehicleDataset) error { return rp.Delete(1) },
+			expected: func() map[int]internal.Vehicle {
+				db := fixture()
+				delete(db, 1)
+				return db
+			}(),
+		},
+		{
+			// This test evaluates that Info follows a replace
+			name: "should follow a replace",
+			write: func(rp *RepositoryVehicleDataset) error {
+				_, err := rp.Replace(map[int]internal.Vehicle{})
+				return err
+			},
+			expected: map[int]internal.Vehicle{},
+		},
+		{
+			// This test evaluates that Info is kept when a write fails
+			name: "should be kept when a write fails",
+			write: func(rp *RepositoryVehicleDataset) error {
+				_, err := rp.Patch(1, internal.VehiclePatch{}, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+					return internal.ErrValidatorInvalidVehicle
+				})
+				if !errors.Is(err, internal.ErrValidatorInvalidVehicle) {
+					return errors.New("the check did not fail the patch")
+				}
+				return nil
+			},
+			expected: fixture(),
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			rp, err := NewRepositoryVehicleDataset(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+			require.NoError(t, err)
+
+			// Act
+			if testCase.write != nil {
+				require.NoError(t, testCase.write(rp))
+			}
+			obtained, err := rp.Info()
+
+			// Assert
+			require.NoError(t, err)
+			expected, err := datasetInfo(testCase.expected)
+			require.NoError(t, err)
+			assert.Equal(t, expected, obtained)
+			assert.Equal(t, len(testCase.expected), obtained.Count)
+			assert.Len(t, obtained.Checksum, 64)
+		})
+	}
+
+	// This test evaluates that the checksum depends on the vehicles and not on the order they are returned in
+	t.Run("should checksum the same vehicles the same way", func(t *testing.T) {
+		info := func(v map[int]internal.Vehicle) internal.DatasetInfo {
+			i, err := datasetInfo(v)
+			require.NoError(t, err)
+			return i
+		}
+		a := info(map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}}, 2: {Id: 2}})
+		b := info(map[int]internal.Vehicle{2: {Id: 2}, 1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}}})
+		c := info(map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat"}}, 2: {Id: 2}})
+		assert.Equal(t, a.Checksum, b.Checksum)
+		assert.NotEqual(t, a.Checksum, c.Checksum)
+	})
+
+	// This test evaluates that writes do not read the vehicles, the first Info after them does, once
+	t.Run("should read the vehicles once after writes, not on each write", func(t *testing.T) {
+		rp := &MockRepository{}
+		rp.On("FindAll").Return(map[int]internal.Vehicle{}, nil)
+		rp.On("Delete", 1).Return(nil)
+		ds, err := NewRepositoryVehicleDataset(rp)
+		require.NoError(t, err)
+
+		_, errInfo := ds.Info()
+		errFirst := ds.Delete(1)
+		errSecond := ds.Delete(1)
+		rp.AssertNumberOfCalls(t, "FindAll", 1)
+		_, errStale := ds.Info()
+		_, errFresh := ds.Info()
+
+		assert.NoError(t, errors.Join(errInfo, errFirst, errSecond, errStale, errFresh))
+		rp.AssertNumberOfCalls(t, "FindAll", 2)
+	})
+
+	// This test evaluates that Info returns the error of the repository when the summary can not be computed
+	t.Run("should return the error of the repository", func(t *testing.T) {
+		errRepository := errors.New("repository error")
+		rp := &MockRepository{}
+		rp.On("FindAll").Return(map[int]internal.Vehicle(nil), errRepository)
+
+		_, err := NewRepositoryVehicleDataset(rp)
+
+		assert.ErrorIs(t, err, errRepository)
+	})
+}
@@ -0,0 +1,22 @@
+package repository
+
+import "app/internal"
+
+// diffVehicles is a function that reports the vehicles added, changed and removed from old to new
+func diffVehicles(old map[int]internal.Vehicle, new map[int]internal.Vehicle) (r internal.ReloadReport) {
+	for key, value := range new {
+		previous, ok := old[key]
+		switch {
+		case !ok:
+			r.Added++
+		case previous != value:
+			r.Changed++
+		}
+	}
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			r.Removed++
+		}
+	}
+	return
+}
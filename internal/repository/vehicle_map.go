@@ -1,25 +1,71 @@
 package repository
 
-import "app/internal"
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
 
 // NewRepositoryReadVehicleMap is a function that returns a new instance of RepositoryReadVehicleMap
-func NewRepositoryReadVehicleMap(db map[int]internal.Vehicle) *RepositoryReadVehicleMap {
-	// default db
-	defaultDb := make(map[int]internal.Vehicle)
-	if db != nil {
-		defaultDb = db
+// - match is the way text criteria are compared, the vehicles are stored in the form it says
+func NewRepositoryReadVehicleMap(db map[int]internal.Vehicle, match internal.TextMatch) *RepositoryReadVehicleMap {
+	// default db: copied so the caller can not mutate it behind the lock
+	defaultDb := make(map[int]internal.Vehicle, len(db))
+	for key, value := range db {
+		defaultDb[key] = match.Vehicle(value)
 	}
-	return &RepositoryReadVehicleMap{db: defaultDb}
+
+	// last id
+	var lastId int
+	for key := range defaultDb {
+		if key > lastId {
+			lastId = key
+		}
+	}
+
+	return &RepositoryReadVehicleMap{db: defaultDb, index: newVehicleIndex(defaultDb, match), match: match, lastId: lastId}
 }
 
 // RepositoryReadVehicleMap is a struct that represents a vehicle repository
+// - safe for concurrent use: reads share a read lock, writes take the lock exclusively
+// - finds are served by secondary indexes, kept consistent with db on every write
 type RepositoryReadVehicleMap struct {
+	// mu guards db, index and lastId
+	mu sync.RWMutex
 	// db is a map of vehicles
 	db map[int]internal.Vehicle
+	// index are the secondary indexes of db
+	index *vehicleIndex
+	// match is the way text criteria are compared
+	match internal.TextMatch
+	// lastId is the last id assigned to a vehicle
+	lastId int
+	// written is set by every write and cleared by every replace
+	written bool
+}
+
+// Ready is a method that returns ErrNotReady if reads can not take the lock before ctx is done
+// - a write or a replace of the whole dataset holds the lock, reads wait for them
+func (r *RepositoryReadVehicleMap) Ready(ctx context.Context) (err error) {
+	for !r.mu.TryRLock() {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("%w: vehicles locked by a write: %s", internal.ErrNotReady, ctx.Err().Error())
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+	r.mu.RUnlock()
+	return
 }
 
 // FindAll is a method that returns a map of all vehicles
 func (r *RepositoryReadVehicleMap) FindAll() (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
 	// copy db
@@ -30,15 +76,56 @@ func (r *RepositoryReadVehicleMap) FindAll() (v map[int]internal.Vehicle, err er
 	return
 }
 
+// FindByID is a method that returns the vehicle with the id
+func (r *RepositoryReadVehicleMap) FindByID(id int) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.db[id]
+	if !ok {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+
+	return
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (r *RepositoryReadVehicleMap) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, err = r.findByRegistration(registration)
+	return
+}
+
+// findByRegistration is a method that returns a map of vehicles that match the registration
+// - the caller must hold the lock
+func (r *RepositoryReadVehicleMap) findByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	// lookup index
+	ids := r.index.registration(registration)
+	if len(ids) == 0 {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+	v = make(map[int]internal.Vehicle, len(ids))
+	for key := range ids {
+		v[key] = r.db[key]
+	}
+
+	return
+}
+
 // FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 func (r *RepositoryReadVehicleMap) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
-	// filter db
-	for key, value := range r.db {
-		if value.Color == color && value.FabricationYear == fabricationYear {
-			v[key] = value
-		}
+	// lookup index
+	for key := range r.index.colorAndYear(color, fabricationYear) {
+		v[key] = r.db[key]
 	}
 
 	return
@@ -46,12 +133,27 @@ func (r *RepositoryReadVehicleMap) FindByColorAndYear(color string, fabricationY
 
 // FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
 func (r *RepositoryReadVehicleMap) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
-	// filter db
-	for key, value := range r.db {
-		if value.Brand == brand && value.FabricationYear >= startYear && value.FabricationYear <= endYear {
-			v[key] = value
+	// lookup index: walk the smallest candidate set and filter by the other condition
+	brandIds := r.index.brand(brand)
+	yearEntries := r.index.yearRange(startYear, endYear)
+	if len(brandIds) <= len(yearEntries) {
+		for key := range brandIds {
+			value := r.db[key]
+			if value.FabricationYear >= startYear && value.FabricationYear <= endYear {
+				v[key] = value
+			}
+		}
+	} else {
+		key := r.match.Key(brand)
+		for _, entry := range yearEntries {
+			if value := r.db[entry.id]; r.match.Key(value.Brand) == key {
+				v[entry.id] = value
+			}
 		}
 	}
 
@@ -60,13 +162,14 @@ func (r *RepositoryReadVehicleMap) FindByBrandAndYearRange(brand string, startYe
 
 // FindByBrand is a method that returns a map of vehicles that match the brand
 func (r *RepositoryReadVehicleMap) FindByBrand(brand string) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
-	// filter db
-	for key, value := range r.db {
-		if value.Brand == brand {
-			v[key] = value
-		}
+	// lookup index
+	for key := range r.index.brand(brand) {
+		v[key] = r.db[key]
 	}
 
 	return
@@ -74,14 +177,234 @@ func (r *RepositoryReadVehicleMap) FindByBrand(brand string) (v map[int]internal
 
 // FindByWeightRange is a method that returns a map of vehicles that match the weight range
 func (r *RepositoryReadVehicleMap) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
-	// filter db
-	for key, value := range r.db {
-		if value.Weight >= fromWeight && value.Weight <= toWeight {
+	// lookup index
+	for _, entry := range r.index.weightRange(fromWeight, toWeight) {
+		v[entry.id] = r.db[entry.id]
+	}
+
+	return
+}
+
+// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+func (r *RepositoryReadVehicleMap) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make(map[int]internal.Vehicle)
+
+	// lookup index: narrow the candidates when an index applies
+	ids, ok := r.index.candidates(filter)
+	if !ok {
+		for key, value := range r.db {
+			if filter.Match(value, r.match) {
+				v[key] = value
+			}
+		}
+		return
+	}
+
+	// filter candidates
+	for _, key := range ids {
+		if value := r.db[key]; filter.Match(value, r.match) {
 			v[key] = value
 		}
 	}
 
 	return
-}
\ No newline at end of file
+}
+
+// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+func (r *RepositoryReadVehicleMap) FindPage(filter internal.VehicleFilter, page internal.PageQuery) (p internal.VehiclePage, err error) {
+	err = checkPage(page)
+	if err != nil {
+		return
+	}
+
+	v, err := r.FindByFilter(filter)
+	if err != nil {
+		return
+	}
+
+	p = pageVehicles(v, page)
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+// - the check runs under the write lock, with the repository itself as the registry
+func (r *RepositoryReadVehicleMap) Save(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check vehicle, in its stored form and without an id
+	vh := r.match.Vehicle(internal.Vehicle{VehicleAttributes: v.VehicleAttributes})
+	if check != nil {
+		err = check(vh, vehicleMapRegistry{r: r})
+		if err != nil {
+			return
+		}
+	}
+
+	// set id and stored form
+	r.lastId++
+	vh.Id = r.lastId
+	*v = vh
+
+	// save vehicle
+	r.db[v.Id] = *v
+	r.index.add(v.Id, *v)
+	r.written = true
+
+	return
+}
+
+// Update is a method that replaces an existing vehicle
+// - the check runs under the write lock, with the repository itself as the registry
+func (r *RepositoryReadVehicleMap) Update(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check if vehicle exists
+	previous, ok := r.db[v.Id]
+	if !ok {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+
+	// check vehicle, in its stored form
+	vh := r.match.Vehicle(*v)
+	if check != nil {
+		err = check(vh, vehicleMapRegistry{r: r})
+		if err != nil {
+			return
+		}
+	}
+
+	// update vehicle
+	*v = vh
+	r.db[v.Id] = *v
+	r.index.remove(v.Id, previous)
+	r.index.add(v.Id, *v)
+	r.written = true
+
+	return
+}
+
+// Patch is a method that applies a partial update to an existing vehicle as a single write and returns the result
+// - the check runs under the write lock, with the repository itself as the registry
+func (r *RepositoryReadVehicleMap) Patch(id int, patch internal.VehiclePatch, check internal.VehicleWriteCheck) (v internal.Vehicle, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check if vehicle exists
+	previous, ok := r.db[id]
+	if !ok {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+
+	// apply patch
+	v = previous
+	patch.Apply(&v)
+	v = r.match.Vehicle(v)
+	if check != nil {
+		err = check(v, vehicleMapRegistry{r: r})
+		if err != nil {
+			v = internal.Vehicle{}
+			return
+		}
+	}
+
+	// update vehicle
+	r.db[id] = v
+	r.index.remove(id, previous)
+	r.index.add(id, v)
+	r.written = true
+
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (r *RepositoryReadVehicleMap) Delete(id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check if vehicle exists
+	previous, ok := r.db[id]
+	if !ok {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+
+	// delete vehicle
+	delete(r.db, id)
+	r.index.remove(id, previous)
+	r.written = true
+
+	return
+}
+
+// Replace is a method that atomically replaces all the vehicles and reports the differences
+func (r *RepositoryReadVehicleMap) Replace(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, true)
+	return
+}
+
+// ReplaceUnwritten is a method that replaces all the vehicles, only if none was written since the last replace
+func (r *RepositoryReadVehicleMap) ReplaceUnwritten(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, false)
+	return
+}
+
+// replace is a method that atomically replaces all the vehicles and reports the differences
+// - unless force, returns ErrRepositoryWritten if the vehicles were written since the last replace
+func (r *RepositoryReadVehicleMap) replace(v map[int]internal.Vehicle, force bool) (rp internal.ReloadReport, err error) {
+	// copy new db and build its indexes before taking the lock
+	db := make(map[int]internal.Vehicle, len(v))
+	for key, value := range v {
+		db[key] = r.match.Vehicle(value)
+	}
+	index := newVehicleIndex(db, r.match)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check writes
+	if !force && r.written {
+		err = internal.ErrRepositoryWritten
+		return
+	}
+
+	// diff
+	rp = diffVehicles(r.db, db)
+
+	// ids are never reused, even if the new db has lower ones
+	for key := range db {
+		if key > r.lastId {
+			r.lastId = key
+		}
+	}
+
+	// swap
+	r.db = db
+	r.index = index
+	r.written = false
+
+	return
+}
+
+// vehicleMapRegistry is a struct that implements the VehicleRegistry interface over a map repository whose lock is already held
+type vehicleMapRegistry struct {
+	// r is the repository
+	r *RepositoryReadVehicleMap
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (g vehicleMapRegistry) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	v, err = g.r.findByRegistration(registration)
+	return
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"app/internal"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stressWorkers is the number of goroutines per operation used by the stress tests
+const stressWorkers = 8
+
+// stressIterations is the number of operations each goroutine performs
+const stressIterations = 200
+
+// TestRepository_ConcurrentReadsAndWrites hammers every Find* method in parallel with mutations.
+// It is meant to be run with the race detector: go test -race ./internal/repository/...
+func TestRepository_ConcurrentReadsAndWrites(t *testing.T) {
+	// arrange
+	rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+	var wg sync.WaitGroup
+	readers := []func(){
+		func() { _, _ = rp.FindAll() },
+		func() { _, _ = rp.FindByColorAndYear("Red", 2010) },
+		func() { _, _ = rp.FindByBrandAndYearRange("Ford", 2010, 2012) },
+		func() { _, _ = rp.FindByBrand("Fiat") },
+		func() { _, _ = rp.FindByWeightRange(1000, 1200) },
+	}
+
+	// act
+	// - readers
+	for _, read := range readers {
+		for i := 0; i < stressWorkers; i++ {
+			wg.Add(1)
+			go func(read func()) {
+				defer wg.Done()
+				for j := 0; j < stressIterations; j++ {
+					read()
+				}
+			}(read)
+		}
+	}
+	// - writers: each one saves, updates and deletes its own vehicles
+	for i := 0; i < stressWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < stressIterations; j++ {
+				v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Red", FabricationYear: 2010, Weight: 1100}}
+				if err := rp.Save(&v, nil); err != nil {
+					t.Error(err)
+					return
+				}
+				v.Color = "Blue"
+				if err := rp.Update(&v, nil); err != nil {
+					t.Error(err)
+					return
+				}
+				if err := rp.Delete(v.Id); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	v, err := rp.FindAll()
+	assert.NoError(t, err)
+	assert.Len(t, v, 3)
+	assert.Equal(t, 3+stressWorkers*stressIterations, rp.lastId)
+}
+
+// TestRepository_ConcurrentPatches checks that patches of different fields of the same vehicle are not lost
+func TestRepository_ConcurrentPatches(t *testing.T) {
+	assertConcurrentPatches(t, NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+}
+
+// assertConcurrentPatches is a function that patches a different field of vehicle 1 from each goroutine and checks every patch was kept
+// - a read, then a write of the whole vehicle, would overwrite the fields set by the patches that ran in between
+func assertConcurrentPatches(t *testing.T, rp internal.RepositoryVehicle) {
+	// arrange
+	brand, model, registration, color, fuelType, transmission := "Renault", "Clio", "XYZ-9876", "Green", "diesel", "automatic"
+	year, capacity := 2020, 4
+	patches := []internal.VehiclePatch{
+		{Brand: &brand}, {Model: &model}, {Registration: &registration}, {Color: &color},
+		{FuelType: &fuelType}, {Transmission: &transmission}, {FabricationYear: &year}, {Capacity: &capacity},
+	}
+	var wg sync.WaitGroup
+
+	// act
+	for _, patch := range patches {
+		wg.Add(1)
+		go func(patch internal.VehiclePatch) {
+			defer wg.Done()
+			for j := 0; j < stressIterations/10; j++ {
+				if _, err := rp.Patch(1, patch, nil); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(patch)
+	}
+	wg.Wait()
+
+	// assert
+	v, err := rp.FindByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, internal.VehicleAttributes{
+		Brand: brand, Model: model, Registration: registration, Color: color, FabricationYear: year, Capacity: capacity,
+		MaxSpeed: v.MaxSpeed, FuelType: fuelType, Transmission: transmission, Weight: v.Weight, Dimensions: v.Dimensions,
+	}, v.VehicleAttributes)
+}
+
+// TestRepository_ConcurrentUniqueSaves checks that concurrent saves of the same registration keep only one of them
+func TestRepository_ConcurrentUniqueSaves(t *testing.T) {
+	assertConcurrentUniqueSaves(t, NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+}
+
+// assertConcurrentUniqueSaves is a function that saves a vehicle with the same new registration from each goroutine and checks only one was saved
+// - a check run before the write would let every goroutine see the registration as free
+func assertConcurrentUniqueSaves(t *testing.T, rp internal.RepositoryVehicle) {
+	// arrange
+	check := internal.NewVehicleValidatorRules(internal.RuleUniqueRegistration()).Validate
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	saved := 0
+
+	// act
+	for i := 0; i < stressWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Registration: "NEW-0001"}}
+			err := rp.Save(&v, check)
+			if err != nil {
+				assert.ErrorIs(t, err, internal.ErrValidatorInvalidVehicle)
+				return
+			}
+			mu.Lock()
+			saved++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, 1, saved)
+	v, err := rp.FindByRegistration("NEW-0001")
+	assert.NoError(t, err)
+	assert.Len(t, v, 1)
+}
+
+// TestRepository_Patch checks that a patch is only written when its check passes
+func TestRepository_Patch(t *testing.T) {
+	color := "Black"
+
+	t.Run("should write the patched vehicle when the check passes", func(t *testing.T) {
+		// arrange
+		rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+		var checked internal.Vehicle
+
+		// act
+		v, err := rp.Patch(1, internal.VehiclePatch{Color: &color}, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+			checked = v
+			_, err := registry.FindByRegistration(v.Registration)
+			return err
+		})
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Black", v.Color)
+		assert.Equal(t, v, checked)
+		stored, _ := rp.FindByID(1)
+		assert.Equal(t, v, stored)
+		byColor, _ := rp.FindByColorAndYear("Black", v.FabricationYear)
+		assert.Contains(t, byColor, 1)
+	})
+
+	t.Run("should leave the vehicle untouched when the check fails", func(t *testing.T) {
+		// arrange
+		rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+		errCheck := errors.New("check failed")
+		before, _ := rp.FindByID(1)
+
+		// act
+		_, err := rp.Patch(1, internal.VehiclePatch{Color: &color}, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+			return errCheck
+		})
+
+		// assert
+		assert.ErrorIs(t, err, errCheck)
+		after, _ := rp.FindByID(1)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("should return ErrRepositoryNotFound when the vehicle does not exist", func(t *testing.T) {
+		// arrange
+		rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+
+		// act
+		_, err := rp.Patch(99, internal.VehiclePatch{Color: &color}, nil)
+
+		// assert
+		assert.ErrorIs(t, err, internal.ErrRepositoryNotFound)
+	})
+}
+
+// TestRepository_ReturnedMapsAreIsolated checks that mutating a returned map does not leak into the repository
+// while other goroutines are reading from it
+func TestRepository_ReturnedMapsAreIsolated(t *testing.T) {
+	// arrange
+	rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+	var wg sync.WaitGroup
+
+	// act
+	for i := 0; i < stressWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < stressIterations; j++ {
+				v, _ := rp.FindByBrand("Ford")
+				delete(v, 1)
+				v[99] = internal.Vehicle{Id: 99}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	v, err := rp.FindByBrand("Ford")
+	assert.NoError(t, err)
+	assert.Len(t, v, 2)
+	assert.Contains(t, v, 1)
+	assert.NotContains(t, v, 99)
+}
+
+// TestNewRepositoryReadVehicleMap_CopiesDb checks that the repository does not share its map with the caller
+func TestNewRepositoryReadVehicleMap_CopiesDb(t *testing.T) {
+	// arrange
+	db := map[int]internal.Vehicle{1: {Id: 1}}
+	rp := NewRepositoryReadVehicleMap(db, internal.TextMatchExact)
+
+	// act
+	db[2] = internal.Vehicle{Id: 2}
+
+	// assert
+	v, err := rp.FindAll()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]internal.Vehicle{1: {Id: 1}}, v)
+}
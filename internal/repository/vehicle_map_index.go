@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"app/internal"
+	"math"
+	"sort"
+)
+
+// colorYear is a struct that represents the key of the color and fabrication year index
+type colorYear struct {
+	color string
+	year  int
+}
+
+// yearEntry is a struct that represents an entry of the fabrication year index
+type yearEntry struct {
+	year int
+	id   int
+}
+
+// weightEntry is a struct that represents an entry of the weight index
+type weightEntry struct {
+	weight float64
+	id     int
+}
+
+// newVehicleIndex is a function that returns the secondary indexes of db
+// - text keys are indexed in the form match compares them
+func newVehicleIndex(db map[int]internal.Vehicle, match internal.TextMatch) *vehicleIndex {
+	ix := &vehicleIndex{
+		match:          match,
+		byBrand:        make(map[string]map[int]struct{}),
+		byColorYear:    make(map[colorYear]map[int]struct{}),
+		byRegistration: make(map[string]map[int]struct{}),
+		byYear:         make([]yearEntry, 0, len(db)),
+		byWeight:       make([]weightEntry, 0, len(db)),
+	}
+
+	// hash indexes
+	for id, v := range db {
+		addToSet(ix.byBrand, match.Key(v.Brand), id)
+		addToSet(ix.byColorYear, colorYear{color: match.Key(v.Color), year: v.FabricationYear}, id)
+		addToSet(ix.byRegistration, match.Key(v.Registration), id)
+		ix.byYear = append(ix.byYear, yearEntry{year: v.FabricationYear, id: id})
+		ix.byWeight = append(ix.byWeight, weightEntry{weight: v.Weight, id: id})
+	}
+
+	// sorted indexes: sorted once instead of inserting one by one
+	sort.Slice(ix.byYear, func(i, j int) bool { return ix.byYear[i].less(ix.byYear[j]) })
+	sort.Slice(ix.byWeight, func(i, j int) bool { return ix.byWeight[i].less(ix.byWeight[j]) })
+
+	return ix
+}
+
+// vehicleIndex is a struct that represents the secondary indexes of the map repository
+// - not safe for concurrent use, guarded by the lock of the repository
+type vehicleIndex struct {
+	// match is the way text keys are compared
+	match internal.TextMatch
+	// byBrand is a hash index of the ids by brand
+	byBrand map[string]map[int]struct{}
+	// byColorYear is a hash index of the ids by color and fabrication year
+	byColorYear map[colorYear]map[int]struct{}
+	// byRegistration is a hash index of the ids by registration, which is not unique
+	byRegistration map[string]map[int]struct{}
+	// byYear is the ids sorted by fabrication year and id, for range queries
+	byYear []yearEntry
+	// byWeight is the ids sorted by weight and id, for range queries
+	byWeight []weightEntry
+}
+
+// add is a method that indexes the vehicle stored under id
+func (ix *vehicleIndex) add(id int, v internal.Vehicle) {
+	addToSet(ix.byBrand, ix.match.Key(v.Brand), id)
+	addToSet(ix.byColorYear, colorYear{color: ix.match.Key(v.Color), year: v.FabricationYear}, id)
+	addToSet(ix.byRegistration, ix.match.Key(v.Registration), id)
+
+	// sorted indexes: insert at position
+	ye := yearEntry{year: v.FabricationYear, id: id}
+	i := sort.Search(len(ix.byYear), func(i int) bool { return !ix.byYear[i].less(ye) })
+	ix.byYear = append(ix.byYear, yearEntry{})
+	copy(ix.byYear[i+1:], ix.byYear[i:])
+	ix.byYear[i] = ye
+
+	we := weightEntry{weight: v.Weight, id: id}
+	i = sort.Search(len(ix.byWeight), func(i int) bool { return !ix.byWeight[i].less(we) })
+	ix.byWeight = append(ix.byWeight, weightEntry{})
+	copy(ix.byWeight[i+1:], ix.byWeight[i:])
+	ix.byWeight[i] = we
+}
+
+// remove is a method that removes the vehicle stored under id from the indexes
+// - v must be the vehicle as it was indexed
+func (ix *vehicleIndex) remove(id int, v internal.Vehicle) {
+	removeFromSet(ix.byBrand, ix.match.Key(v.Brand), id)
+	removeFromSet(ix.byColorYear, colorYear{color: ix.match.Key(v.Color), year: v.FabricationYear}, id)
+	removeFromSet(ix.byRegistration, ix.match.Key(v.Registration), id)
+
+	// sorted indexes: entries are unique by id, so the search lands on them
+	ye := yearEntry{year: v.FabricationYear, id: id}
+	i := sort.Search(len(ix.byYear), func(i int) bool { return !ix.byYear[i].less(ye) })
+	if i < len(ix.byYear) && ix.byYear[i] == ye {
+		ix.byYear = append(ix.byYear[:i], ix.byYear[i+1:]...)
+	}
+
+	we := weightEntry{weight: v.Weight, id: id}
+	i = sort.Search(len(ix.byWeight), func(i int) bool { return !ix.byWeight[i].less(we) })
+	if i < len(ix.byWeight) && ix.byWeight[i] == we {
+		ix.byWeight = append(ix.byWeight[:i], ix.byWeight[i+1:]...)
+	}
+}
+
+// brand is a method that returns the ids of the vehicles of a brand
+func (ix *vehicleIndex) brand(brand string) map[int]struct{} {
+	return ix.byBrand[ix.match.Key(brand)]
+}
+
+// colorAndYear is a method that returns the ids of the vehicles of a color and fabrication year
+func (ix *vehicleIndex) colorAndYear(color string, year int) map[int]struct{} {
+	return ix.byColorYear[colorYear{color: ix.match.Key(color), year: year}]
+}
+
+// registration is a method that returns the ids of the vehicles of a registration
+func (ix *vehicleIndex) registration(registration string) map[int]struct{} {
+	return ix.byRegistration[ix.match.Key(registration)]
+}
+
+// yearRange is a method that returns the entries with a fabrication year between from and to, both inclusive
+func (ix *vehicleIndex) yearRange(from int, to int) []yearEntry {
+	lo := sort.Search(len(ix.byYear), func(i int) bool { return ix.byYear[i].year >= from })
+	hi := sort.Search(len(ix.byYear), func(i int) bool { return ix.byYear[i].year > to })
+	if lo >= hi {
+		return nil
+	}
+	return ix.byYear[lo:hi]
+}
+
+// weightRange is a method that returns the entries with a weight between from and to, both inclusive
+func (ix *vehicleIndex) weightRange(from float64, to float64) []weightEntry {
+	lo := sort.Search(len(ix.byWeight), func(i int) bool { return ix.byWeight[i].weight >= from })
+	hi := sort.Search(len(ix.byWeight), func(i int) bool { return ix.byWeight[i].weight > to })
+	if lo >= hi {
+		return nil
+	}
+	return ix.byWeight[lo:hi]
+}
+
+// candidates is a method that returns the ids of the smallest index lookup the filter allows
+// - ok is false when no index applies, so every vehicle is a candidate
+// - the candidates still have to be matched against the whole filter
+func (ix *vehicleIndex) candidates(f internal.VehicleFilter) (ids []int, ok bool) {
+	best := math.MaxInt
+	var collect func() []int
+
+	// hash indexes
+	if f.Brand != nil {
+		set := ix.brand(*f.Brand)
+		if len(set) < best {
+			best, collect = len(set), func() []int { return setIds(set) }
+		}
+	}
+	if f.Color != nil && f.YearMin != nil && f.YearMax != nil && *f.YearMin == *f.YearMax {
+		set := ix.colorAndYear(*f.Color, *f.YearMin)
+		if len(set) < best {
+			best, collect = len(set), func() []int { return setIds(set) }
+		}
+	}
+
+	// sorted indexes: open bounds extend to the ends of the index
+	if f.YearMin != nil || f.YearMax != nil {
+		from, to := math.MinInt, math.MaxInt
+		if f.YearMin != nil {
+			from = *f.YearMin
+		}
+		if f.YearMax != nil {
+			to = *f.YearMax
+		}
+		entries := ix.yearRange(from, to)
+		if len(entries) < best {
+			best, collect = len(entries), func() []int {
+				ids := make([]int, 0, len(entries))
+				for _, entry := range entries {
+					ids = append(ids, entry.id)
+				}
+				return ids
+			}
+		}
+	}
+	if f.WeightMin != nil || f.WeightMax != nil {
+		from, to := math.Inf(-1), math.Inf(1)
+		if f.WeightMin != nil {
+			from = *f.WeightMin
+		}
+		if f.WeightMax != nil {
+			to = *f.WeightMax
+		}
+		entries := ix.weightRange(from, to)
+		if len(entries) < best {
+			collect = func() []int {
+				ids := make([]int, 0, len(entries))
+				for _, entry := range entries {
+					ids = append(ids, entry.id)
+				}
+				return ids
+			}
+		}
+	}
+
+	if collect == nil {
+		return
+	}
+	ids, ok = collect(), true
+	return
+}
+
+// less is a method that orders the entries by fabrication year and id
+func (e yearEntry) less(o yearEntry) bool {
+	if e.year != o.year {
+		return e.year < o.year
+	}
+	return e.id < o.id
+}
+
+// less is a method that orders the entries by weight and id
+func (e weightEntry) less(o weightEntry) bool {
+	if e.weight != o.weight {
+		return e.weight < o.weight
+	}
+	return e.id < o.id
+}
+
+// setIds is a function that returns the ids of a set
+func setIds(set map[int]struct{}) (ids []int) {
+	ids = make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// addToSet is a function that adds an id to the set of key
+func addToSet[K comparable](ix map[K]map[int]struct{}, key K, id int) {
+	set, ok := ix[key]
+	if !ok {
+		set = make(map[int]struct{})
+		ix[key] = set
+	}
+	set[id] = struct{}{}
+}
+
+// removeFromSet is a function that removes an id from the set of key, dropping the set once empty
+func removeFromSet[K comparable](ix map[K]map[int]struct{}, key K, id int) {
+	set, ok := ix[key]
+	if !ok {
+		return
+	}
+	delete(set, id)
+	if len(set) == 0 {
+		delete(ix, key)
+	}
+}
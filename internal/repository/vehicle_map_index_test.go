@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// benchVehicles is the number of vehicles of the benchmark dataset
+const benchVehicles = 300000
+
+// indexBrands, indexColors and indexYears are the values the generated vehicles are drawn from
+var (
+	indexBrands = []string{"Ford", "Fiat", "Chevrolet", "Toyota", "Honda", "Renault", "Peugeot", "Volkswagen", "Nissan", "Kia"}
+	indexColors = []string{"Red", "Blue", "White", "Black", "Gray", "Green"}
+	indexYears  = []int{2000, 2004, 2008, 2012, 2016, 2020}
+)
+
+// randomVehicle is a function that returns a vehicle with random indexed attributes
+func randomVehicle(rnd *rand.Rand, id int, brands int) internal.Vehicle {
+	return internal.Vehicle{
+		Id: id,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           fmt.Sprintf("%s-%d", indexBrands[rnd.Intn(len(indexBrands))], rnd.Intn(brands)),
+			Color:           indexColors[rnd.Intn(len(indexColors))],
+			FabricationYear: indexYears[0] + rnd.Intn(indexYears[len(indexYears)-1]-indexYears[0]+1),
+			Weight:          float64(800 + rnd.Intn(2000)),
+		},
+	}
+}
+
+// randomVehicles is a function that returns n vehicles with random indexed attributes
+func randomVehicles(rnd *rand.Rand, n int, brands int) (db map[int]internal.Vehicle) {
+	db = make(map[int]internal.Vehicle, n)
+	for id := 1; id <= n; id++ {
+		db[id] = randomVehicle(rnd, id, brands)
+	}
+	return
+}
+
+// scan is a function that returns the vehicles of db that match, the way the finds worked before the indexes
+func scan(db map[int]internal.Vehicle, match func(v internal.Vehicle) bool) (v map[int]internal.Vehicle) {
+	v = make(map[int]internal.Vehicle)
+	for key, value := range db {
+		if match(value) {
+			v[key] = value
+		}
+	}
+	return
+}
+
+// assertIndexConsistent is a function that asserts every find returns the same vehicles as a scan
+func assertIndexConsistent(t *testing.T, rp *RepositoryReadVehicleMap) {
+	t.Helper()
+	db, _ := rp.FindAll()
+
+	for _, brand := range indexBrands {
+		brand := brand + "-0"
+		v, _ := rp.FindByBrand(brand)
+		assert.Equal(t, scan(db, func(v internal.Vehicle) bool { return v.Brand == brand }), v)
+
+		v, _ = rp.FindByBrandAndYearRange(brand, 2004, 2012)
+		assert.Equal(t, scan(db, func(v internal.Vehicle) bool {
+			return v.Brand == brand && v.FabricationYear >= 2004 && v.FabricationYear <= 2012
+		}), v)
+	}
+	for _, color := range indexColors {
+		for _, year := range indexYears {
+			v, _ := rp.FindByColorAndYear(color, year)
+			assert.Equal(t, scan(db, func(v internal.Vehicle) bool { return v.Color == color && v.FabricationYear == year }), v)
+		}
+	}
+	v, _ := rp.FindByFilter(internal.VehicleFilter{Color: &indexColors[0], YearMin: &indexYears[1], YearMax: &indexYears[1]})
+	assert.Equal(t, scan(db, func(v internal.Vehicle) bool { return v.Color == indexColors[0] && v.FabricationYear == indexYears[1] }), v)
+	weightMin := 1000.0
+	v, _ = rp.FindByFilter(internal.VehicleFilter{WeightMin: &weightMin})
+	assert.Equal(t, scan(db, func(v internal.Vehicle) bool { return v.Weight >= weightMin }), v)
+
+	v, _ = rp.FindByWeightRange(1000, 1500)
+	assert.Equal(t, scan(db, func(v internal.Vehicle) bool { return v.Weight >= 1000 && v.Weight <= 1500 }), v)
+	assert.Len(t, rp.index.byYear, len(db))
+	assert.Len(t, rp.index.byWeight, len(db))
+}
+
+// TestRepository_IndexConsistency is a test function that checks the indexes against a scan after every kind of write
+func TestRepository_IndexConsistency(t *testing.T) {
+	// arrange
+	rnd := rand.New(rand.NewSource(1))
+	rp := NewRepositoryReadVehicleMap(randomVehicles(rnd, 500, 2), internal.TextMatchExact)
+	assertIndexConsistent(t, rp)
+
+	// act and assert
+	for i := 0; i < 300; i++ {
+		switch rnd.Intn(3) {
+		case 0:
+			v := randomVehicle(rnd, 0, 2)
+			assert.NoError(t, rp.Save(&v, nil))
+		case 1:
+			v := randomVehicle(rnd, 1+rnd.Intn(rp.lastId), 2)
+			_ = rp.Update(&v, nil)
+		case 2:
+			_ = rp.Delete(1 + rnd.Intn(rp.lastId))
+		}
+	}
+	assertIndexConsistent(t, rp)
+
+	_, err := rp.Replace(randomVehicles(rnd, 200, 2))
+	assert.NoError(t, err)
+	assertIndexConsistent(t, rp)
+}
+
+// BenchmarkRepository_Find compares the indexed finds against a full scan over the same dataset.
+// Run with: go test -run=^$ -bench=BenchmarkRepository_Find -benchmem ./internal/repository/...
+func BenchmarkRepository_Find(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	db := randomVehicles(rnd, benchVehicles, 100)
+	rp := NewRepositoryReadVehicleMap(db, internal.TextMatchExact)
+
+	benchmarks := []struct {
+		name    string
+		indexed func()
+		match   func(v internal.Vehicle) bool
+	}{
+		{
+			name:    "FindByBrand",
+			indexed: func() { _, _ = rp.FindByBrand("Ford-7") },
+			match:   func(v internal.Vehicle) bool { return v.Brand == "Ford-7" },
+		},
+		{
+			name:    "FindByColorAndYear",
+			indexed: func() { _, _ = rp.FindByColorAndYear("Red", 2010) },
+			match:   func(v internal.Vehicle) bool { return v.Color == "Red" && v.FabricationYear == 2010 },
+		},
+		{
+			name:    "FindByBrandAndYearRange",
+			indexed: func() { _, _ = rp.FindByBrandAndYearRange("Ford-7", 2004, 2012) },
+			match: func(v internal.Vehicle) bool {
+				return v.Brand == "Ford-7" && v.FabricationYear >= 2004 && v.FabricationYear <= 2012
+			},
+		},
+		{
+			name:    "FindByWeightRange",
+			indexed: func() { _, _ = rp.FindByWeightRange(1000, 1010) },
+			match:   func(v internal.Vehicle) bool { return v.Weight >= 1000 && v.Weight <= 1010 },
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name+"/indexed", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bm.indexed()
+			}
+		})
+		b.Run(bm.name+"/scan", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = scan(db, bm.match)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"app/internal"
+	"app/internal/repository/repotest"
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -83,10 +86,16 @@ func Setup() *TestCaseSetup {
 		},
 	}
 	return &TestCaseSetup{
-		repository: NewRepositoryReadVehicleMap(db),
+		repository: NewRepositoryReadVehicleMap(db, internal.TextMatchExact),
 	}
 }
 
+// fixture is a function that returns the vehicles Setup starts the repository with
+func fixture() (db map[int]internal.Vehicle) {
+	db, _ = Setup().repository.FindAll()
+	return
+}
+
 // FindAllTestCase is a struct that represents a test case for the FindAll method
 type FindAllTestCase struct {
 	setup            *TestCaseSetup
@@ -532,3 +541,56 @@ func TestRepository_FindByWeightRange(t *testing.T) {
 		})
 	}
 }
+
+// TestRepositoryReadVehicleMap_Conformance is a test function that runs the conformance suite against the map repository
+func TestRepositoryReadVehicleMap_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+		return NewRepositoryReadVehicleMap(db, internal.TextMatchExact)
+	})
+}
+
+// TestRepositoryReadVehicleMap_Normalized is a test function that runs the normalized matching suite against the map repository
+func TestRepositoryReadVehicleMap_Normalized(t *testing.T) {
+	repotest.RunNormalized(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+		return NewRepositoryReadVehicleMap(db, internal.TextMatchNormalized)
+	})
+}
+
+// TestRepositoryReadVehicleMap_Write is a test function that runs the write conformance suite against the map repository
+func TestRepositoryReadVehicleMap_Write(t *testing.T) {
+	repotest.RunWrite(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		return NewRepositoryReadVehicleMap(db, internal.TextMatchExact)
+	})
+	repotest.RunWriteNormalized(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		return NewRepositoryReadVehicleMap(db, internal.TextMatchNormalized)
+	})
+}
+
+// TestRepositoryReadVehicleMap_Ready is a test function that tests the Ready method
+func TestRepositoryReadVehicleMap_Ready(t *testing.T) {
+	t.Run("should be ready while reads can take the lock", func(t *testing.T) {
+		// Arrange
+		rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+
+		// Act
+		err := rp.Ready(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not be ready while a write holds the lock past the deadline", func(t *testing.T) {
+		// Arrange
+		rp := NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact)
+		rp.mu.Lock()
+		defer rp.mu.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err := rp.Ready(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, internal.ErrNotReady)
+	})
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"sort"
+)
+
+// checkPage is a function that returns ErrRepositoryInvalidFind if the page can not be served
+// - the first problem found is returned
+func checkPage(page internal.PageQuery) (err error) {
+	switch {
+	case page.Offset < 0:
+		err = fmt.Errorf("%w: negative offset", internal.ErrRepositoryInvalidFind)
+		return
+	case page.Limit < 0:
+		err = fmt.Errorf("%w: negative limit", internal.ErrRepositoryInvalidFind)
+		return
+	}
+	for _, key := range page.Sort {
+		if !key.Field.Valid() {
+			err = fmt.Errorf("%w: unknown sort field %s", internal.ErrRepositoryInvalidFind, key.Field)
+			return
+		}
+	}
+	return
+}
+
+// pageVehicles is a function that sorts the vehicles and returns the window of the page
+func pageVehicles(v map[int]internal.Vehicle, page internal.PageQuery) (p internal.VehiclePage) {
+	vehicles := make([]internal.Vehicle, 0, len(v))
+	for _, value := range v {
+		vehicles = append(vehicles, value)
+	}
+	sort.Slice(vehicles, func(i, j int) bool {
+		return internal.CompareVehicles(vehicles[i], vehicles[j], page.Sort) < 0
+	})
+
+	// window: seek past the last vehicle of the previous page, then skip the offset
+	p.Total = len(vehicles)
+	start := 0
+	if page.After != nil {
+		start = sort.Search(len(vehicles), func(i int) bool {
+			return internal.CompareVehicles(vehicles[i], *page.After, page.Sort) > 0
+		})
+	}
+	start = min(start+page.Offset, len(vehicles))
+	end := len(vehicles)
+	if page.Limit > 0 {
+		end = min(start+page.Limit, len(vehicles))
+	}
+	p.Vehicles = vehicles[start:end]
+	p.More = end < len(vehicles)
+
+	return
+}
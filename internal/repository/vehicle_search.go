@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"app/internal"
+	"sync"
+)
+
+// RepositoryVehicleReplaceable is an interface that represents a vehicle repository whose vehicles can also be swapped at once
+type RepositoryVehicleReplaceable interface {
+	internal.RepositoryVehicle
+	internal.RepositoryReplaceVehicle
+}
+
+// NewRepositoryVehicleSearch is a function that returns a new instance of RepositoryVehicleSearch
+// - the vehicles of the repository are indexed right away
+func NewRepositoryVehicleSearch(rp RepositoryVehicleReplaceable) (r *RepositoryVehicleSearch, err error) {
+	db, err := rp.FindAll()
+	if err != nil {
+		return
+	}
+
+	r = &RepositoryVehicleSearch{
+		RepositoryVehicleReplaceable: rp,
+		index:                        newSearchIndex(db),
+	}
+	return
+}
+
+// RepositoryVehicleSearch is a struct that adds a text search to a vehicle repository
+// - reads are delegated to the repository, writes also keep the inverted index in sync
+type RepositoryVehicleSearch struct {
+	RepositoryVehicleReplaceable
+	// mu guards the index, writes hold it for the write to the repository too so the index follows the same order
+	mu sync.RWMutex
+	// index is the inverted index of the words of the vehicles
+	index *searchIndex
+}
+
+// Search is a method that returns the vehicles that match every word of the text
+func (r *RepositoryVehicleSearch) Search(text string, limit int) (rs internal.VehicleSearchResult, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rs = r.index.search(text, limit)
+	return
+}
+
+// Save is a method that saves a new vehicle and sets its id
+func (r *RepositoryVehicleSearch) Save(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.RepositoryVehicleReplaceable.Save(v, check)
+	if err != nil {
+		return
+	}
+	r.index.add(v.Id, *v)
+
+	return
+}
+
+// Update is a method that replaces an existing vehicle
+func (r *RepositoryVehicleSearch) Update(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.RepositoryVehicleReplaceable.Update(v, check)
+	if err != nil {
+		return
+	}
+	r.index.remove(v.Id)
+	r.index.add(v.Id, *v)
+
+	return
+}
+
+// Patch is a method that applies a partial update to an existing vehicle as a single write and returns the result
+func (r *RepositoryVehicleSearch) Patch(id int, patch internal.VehiclePatch, check internal.VehicleWriteCheck) (v internal.Vehicle, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, err = r.RepositoryVehicleReplaceable.Patch(id, patch, check)
+	if err != nil {
+		return
+	}
+	r.index.remove(id)
+	r.index.add(id, v)
+
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (r *RepositoryVehicleSearch) Delete(id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.RepositoryVehicleReplaceable.Delete(id)
+	if err != nil {
+		return
+	}
+	r.index.remove(id)
+
+	return
+}
+
+// Replace is a method that atomically replaces all the vehicles and reports the differences
+// - the index is rebuilt from the vehicles as the repository stored them
+func (r *RepositoryVehicleSearch) Replace(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, r.RepositoryVehicleReplaceable.Replace)
+	return
+}
+
+// ReplaceUnwritten is a method that replaces all the vehicles, only if none was written since the last replace
+func (r *RepositoryVehicleSearch) ReplaceUnwritten(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, r.RepositoryVehicleReplaceable.ReplaceUnwritten)
+	return
+}
+
+// replace is a method that replaces the vehicles with the replace method of the repository, then rebuilds the index
+func (r *RepositoryVehicleSearch) replace(v map[int]internal.Vehicle, replace func(v map[int]internal.Vehicle) (internal.ReloadReport, error)) (rp internal.ReloadReport, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rp, err = replace(v)
+	if err != nil {
+		return
+	}
+	db, err := r.RepositoryVehicleReplaceable.FindAll()
+	if err != nil {
+		return
+	}
+	r.index = newSearchIndex(db)
+
+	return
+}
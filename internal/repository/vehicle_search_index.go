@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// searchFieldWeights is the weight of a word depending on the field it was read from
+var searchFieldWeights = []struct {
+	weight float64
+	value  func(v internal.Vehicle) string
+}{
+	{3, func(v internal.Vehicle) string { return v.Brand }},
+	{3, func(v internal.Vehicle) string { return v.Model }},
+	{2, func(v internal.Vehicle) string { return v.Registration }},
+	{1, func(v internal.Vehicle) string { return v.Color }},
+	{1, func(v internal.Vehicle) string { return v.FuelType }},
+	{1, func(v internal.Vehicle) string { return v.Transmission }},
+	{1, func(v internal.Vehicle) string { return strconv.Itoa(v.FabricationYear) }},
+}
+
+const (
+	// searchScoreExact is the score of a word of the query that matches a word of a vehicle
+	searchScoreExact = 1.0
+	// searchScorePrefix is the score of a word of the query that is the prefix of a word of a vehicle
+	searchScorePrefix = 0.7
+	// searchScoreFuzzy is the score of a word of the query one typo away from a word of a vehicle, halved on every further typo
+	searchScoreFuzzy = 0.5
+)
+
+// searchIndex is a struct that represents an inverted index of the words of the vehicles
+// - it is not safe for concurrent use, see RepositoryVehicleSearch
+type searchIndex struct {
+	// postings are the vehicles each word appears in, along with the weight of the best field it appears in
+	postings map[string]map[int]float64
+	// grams are the words of postings each bigram appears in, to find the words a word of a query may match without comparing it to all of them
+	grams map[string]map[string]struct{}
+	// vehicles are the vehicles indexed, by id
+	vehicles map[int]internal.Vehicle
+}
+
+// newSearchIndex is a function that returns the index of the vehicles
+func newSearchIndex(db map[int]internal.Vehicle) (ix *searchIndex) {
+	ix = &searchIndex{
+		postings: make(map[string]map[int]float64),
+		grams:    make(map[string]map[string]struct{}),
+		vehicles: make(map[int]internal.Vehicle, len(db)),
+	}
+	for id, v := range db {
+		ix.add(id, v)
+	}
+	return
+}
+
+// words is a function that returns the words of a vehicle along with the weight of the best field they appear in
+func words(v internal.Vehicle) (w map[string]float64) {
+	w = make(map[string]float64)
+	for _, field := range searchFieldWeights {
+		for _, word := range tokenize(field.value(v)) {
+			w[word] = max(w[word], field.weight)
+		}
+	}
+	return
+}
+
+// add is a method that indexes a vehicle
+func (ix *searchIndex) add(id int, v internal.Vehicle) {
+	ix.vehicles[id] = v
+	for word, weight := range words(v) {
+		if ix.postings[word] == nil {
+			ix.postings[word] = make(map[int]float64)
+			for gram := range bigrams(word) {
+				if ix.grams[gram] == nil {
+					ix.grams[gram] = make(map[string]struct{})
+				}
+				ix.grams[gram][word] = struct{}{}
+			}
+		}
+		ix.postings[word][id] = weight
+	}
+}
+
+// remove is a method that removes a vehicle from the index
+func (ix *searchIndex) remove(id int) {
+	v, ok := ix.vehicles[id]
+	if !ok {
+		return
+	}
+	delete(ix.vehicles, id)
+	for word := range words(v) {
+		delete(ix.postings[word], id)
+		if len(ix.postings[word]) == 0 {
+			delete(ix.postings, word)
+			for gram := range bigrams(word) {
+				delete(ix.grams[gram], word)
+				if len(ix.grams[gram]) == 0 {
+					delete(ix.grams, gram)
+				}
+			}
+		}
+	}
+}
+
+// candidates is a method that returns the words of the index a word of a query may match, exactly, as a prefix or with up to edits typos
+// - each typo loses at most three bigrams of the query, so a match shares all but 3*edits of them
+// - every word is a candidate when that leaves no bigram to share, e.g. for words repeating a rune
+func (ix *searchIndex) candidates(query string, edits int) (words []string) {
+	grams := bigrams(query)
+	shared := len(grams) - 3*edits
+	if shared < 1 {
+		words = make([]string, 0, len(ix.postings))
+		for word := range ix.postings {
+			words = append(words, word)
+		}
+		return
+	}
+
+	counts := make(map[string]int)
+	for gram := range grams {
+		for word := range ix.grams[gram] {
+			counts[word]++
+		}
+	}
+	for word, n := range counts {
+		if n >= shared {
+			words = append(words, word)
+		}
+	}
+	return
+}
+
+// match is a method that returns the vehicles that contain a word of a query, along with their score for it
+// - the best match of the vehicle counts: exact, then prefix, then with typos
+// - only the candidates of the word are compared to it
+func (ix *searchIndex) match(query string) (scores map[int]float64) {
+	scores = make(map[int]float64)
+	edits := maxEdits(query)
+	for _, word := range ix.candidates(query, edits) {
+		var score float64
+		switch {
+		case word == query:
+			score = searchScoreExact
+		case strings.HasPrefix(word, query):
+			score = searchScorePrefix
+		default:
+			d := editDistance(query, word, edits)
+			if d > edits {
+				continue
+			}
+			score = searchScoreFuzzy / float64(int(1)<<(d-1))
+		}
+		for id, weight := range ix.postings[word] {
+			scores[id] = max(scores[id], score*weight)
+		}
+	}
+	return
+}
+
+// search is a method that returns the vehicles that match every word of the text, best first
+func (ix *searchIndex) search(text string, limit int) (r internal.VehicleSearchResult) {
+	r.Hits = []internal.VehicleSearchHit{}
+
+	// every word of the query must match, scores add up
+	var scores map[int]float64
+	for i, query := range tokenize(text) {
+		matched := ix.match(query)
+		if i == 0 {
+			scores = matched
+			continue
+		}
+		for id, score := range scores {
+			if s, ok := matched[id]; ok {
+				scores[id] = score + s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	// hits, best first
+	for id, score := range scores {
+		r.Hits = append(r.Hits, internal.VehicleSearchHit{Vehicle: ix.vehicles[id], Score: score})
+	}
+	sort.Slice(r.Hits, func(i, j int) bool {
+		if r.Hits[i].Score != r.Hits[j].Score {
+			return r.Hits[i].Score > r.Hits[j].Score
+		}
+		return r.Hits[i].Vehicle.Id < r.Hits[j].Vehicle.Id
+	})
+
+	r.Total = len(r.Hits)
+	if limit > 0 && limit < len(r.Hits) {
+		r.Hits = r.Hits[:limit]
+	}
+	return
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/repository/repotest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepositoryVehicleSearch_Write is a test function that runs the write conformance suite against the text search repository
+func TestRepositoryVehicleSearch_Write(t *testing.T) {
+	repotest.RunWrite(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		rp, err := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(db, internal.TextMatchExact))
+		require.NoError(t, err)
+		return rp
+	})
+}
+
+// searchIds is a function that returns the ids of the hits of a search, in order
+func searchIds(r internal.VehicleSearchResult) (ids []int) {
+	ids = []int{}
+	for _, hit := range r.Hits {
+		ids = append(ids, hit.Vehicle.Id)
+	}
+	return
+}
+
+// TestFoldText is a test function that tests the foldText function
+func TestFoldText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		// This test evaluates that foldText lower cases the text
+		{name: "should lower case the text", text: "FoRd", expected: "ford"},
+		// This test evaluates that foldText removes the diacritics, composed or not
+		{name: "should remove the diacritics, composed or not", text: "Citroën Škoda", expected: "citroen skoda"},
+		// This test evaluates that foldText folds the case and trims the text like normalized finds compare it
+		{name: "should fold the case and trim the text like normalized finds", text: " Straße ", expected: "strasse"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, foldText(tc.text))
+		})
+	}
+}
+
+// TestEditDistance is a test function that tests the editDistance function
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		a        string
+		b        string
+		max      int
+		expected int
+	}{
+		// This test evaluates that editDistance returns zero for equal words
+		{name: "should return zero for equal words", a: "fiesta", b: "fiesta", max: 2, expected: 0},
+		// This test evaluates that editDistance counts substitutions, insertions and deletions
+		{name: "should count substitutions, insertions and deletions", a: "chevrolet", b: "chevrlot", max: 2, expected: 2},
+		// This test evaluates that editDistance counts runes, not bytes
+		{name: "should count runes, not bytes", a: "grün", b: "grun", max: 2, expected: 1},
+		// This test evaluates that editDistance gives up past the maximum
+		{name: "should give up past the maximum", a: "toyota", b: "honda", max: 1, expected: 2},
+		// This test evaluates that editDistance counts a transposition of adjacent runes as one edit
+		{name: "should count a transposition as one edit", a: "fiat", b: "fait", max: 2, expected: 1},
+		// This test evaluates that editDistance counts a transposition of runes an insertion made adjacent as one edit
+		{name: "should count a transposition across an insertion", a: "fuchsia", b: "fuscia", max: 2, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, editDistance(tc.a, tc.b, tc.max))
+		})
+	}
+}
+
+// TestRepositoryVehicleSearch_Search is a test function that tests the Search method
+func TestRepositoryVehicleSearch_Search(t *testing.T) {
+	db := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta", Registration: "ABC-1234", Color: "Red", FabricationYear: 2010}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", Registration: "ABC-1235", Color: "Blue", FabricationYear: 2012}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Citroën", Model: "Berlingo", Registration: "XYZ-1", Color: "Red", FabricationYear: 2015}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Chevrolet", Model: "Fordson", Registration: "XYZ-2", Color: "Gray", FabricationYear: 2010}},
+		5: {Id: 5, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "Uno", Registration: "XYZ-3", Color: "Fuscia", FabricationYear: 2013}},
+		6: {Id: 6, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "Palio", Registration: "XYZ-4", Color: "Mauv", FabricationYear: 2014}},
+	}
+	rp, err := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(db, internal.TextMatchExact))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		text          string
+		limit         int
+		expectedIds   []int
+		expectedTotal int
+	}{
+		// This test evaluates that Search ranks exact words above prefixes, and brands above other fields
+		{name: "should rank exact words above prefixes", text: "ford", expectedIds: []int{1, 2, 4}, expectedTotal: 3},
+		// This test evaluates that Search requires every word of the text to match
+		{name: "should require every word of the text to match", text: "FORD red", expectedIds: []int{1}, expectedTotal: 1},
+		// This test evaluates that Search ignores case and diacritics
+		{name: "should ignore case and diacritics", text: "citroen", expectedIds: []int{3}, expectedTotal: 1},
+		// This test evaluates that Search tolerates typos in long enough words
+		{name: "should tolerate typos in long enough words", text: "chevrlot", expectedIds: []int{4}, expectedTotal: 1},
+		// This test evaluates that Search tolerates a transposition along with another typo in long words
+		{name: "should match fuchsia with the misspelled fuscia", text: "Fuchsia", expectedIds: []int{5}, expectedTotal: 1},
+		// This test evaluates that Search tolerates a single typo in medium words
+		{name: "should match mauve with the misspelled mauv", text: "mauve", expectedIds: []int{6}, expectedTotal: 1},
+		// This test evaluates that Search does not tolerate typos in short words
+		{name: "should not tolerate typos in short words", text: "rad", expectedIds: []int{}, expectedTotal: 0},
+		// This test evaluates that Search matches words of the registration
+		{name: "should match words of the registration", text: "abc 1235", expectedIds: []int{2}, expectedTotal: 1},
+		// This test evaluates that Search limits the hits but reports the total
+		{name: "should limit the hits but report the total", text: "2010", limit: 1, expectedIds: []int{1}, expectedTotal: 2},
+		// This test evaluates that Search returns no hits for a text without words
+		{name: "should return no hits for a text without words", text: "--", expectedIds: []int{}, expectedTotal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			r, err := rp.Search(tc.text, tc.limit)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedIds, searchIds(r))
+			assert.Equal(t, tc.expectedTotal, r.Total)
+		})
+	}
+}
+
+// TestSearchIndex_Candidates is a test function that tests the candidates method
+func TestSearchIndex_Candidates(t *testing.T) {
+	ix := newSearchIndex(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta", Color: "Fuscia"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "Uno", Color: "Aaa"}},
+	})
+
+	testCases := []struct {
+		name     string
+		query    string
+		edits    int
+		expected []string
+	}{
+		// This test evaluates that candidates drops the words sharing too few bigrams with the query
+		{name: "should drop the words sharing too few bigrams", query: "fuchsia", edits: 2, expected: []string{"fiat", "fiesta", "ford", "fuscia"}},
+		// This test evaluates that candidates keeps the words the query is a prefix of
+		{name: "should keep the words the query is a prefix of", query: "fies", edits: 0, expected: []string{"fiesta"}},
+		// This test evaluates that candidates keeps every word when the query has too few bigrams to share
+		{name: "should keep every word when the query has too few bigrams", query: "aaaa", edits: 1, expected: []string{"0", "aaa", "fiat", "fiesta", "ford", "fuscia", "uno"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obtained := ix.candidates(tc.query, tc.edits)
+
+			assert.ElementsMatch(t, tc.expected, obtained)
+		})
+	}
+}
+
+// TestRepositoryVehicleSearch_Sync is a test function that tests the index follows the writes to the repository
+func TestRepositoryVehicleSearch_Sync(t *testing.T) {
+	t.Run("should index saved vehicles", func(t *testing.T) {
+		// Arrange
+		rp, _ := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Brand: "Peugeot", Model: "Partner"}}
+
+		// Act
+		err := rp.Save(&v, nil)
+		r, _ := rp.Search("peugeot", 0)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int{v.Id}, searchIds(r))
+	})
+
+	t.Run("should reindex updated vehicles", func(t *testing.T) {
+		// Arrange
+		rp, _ := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+		v := fixture()[1]
+		v.Model = "Mustang"
+
+		// Act
+		err := rp.Update(&v, nil)
+		before, _ := rp.Search("fiesta", 0)
+		after, _ := rp.Search("mustang", 0)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int{}, searchIds(before))
+		assert.Equal(t, []int{1}, searchIds(after))
+	})
+
+	t.Run("should unindex deleted vehicles", func(t *testing.T) {
+		// Arrange
+		rp, _ := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+
+		// Act
+		err := rp.Delete(1)
+		r, _ := rp.Search("fiesta", 0)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int{}, searchIds(r))
+	})
+
+	t.Run("should leave the index untouched when the write fails", func(t *testing.T) {
+		// Arrange
+		rp, _ := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+		v := internal.Vehicle{Id: 99, VehicleAttributes: internal.VehicleAttributes{Brand: "Peugeot"}}
+
+		// Act
+		err := rp.Update(&v, nil)
+		r, _ := rp.Search("peugeot", 0)
+
+		// Assert
+		assert.ErrorIs(t, err, internal.ErrRepositoryNotFound)
+		assert.Equal(t, []int{}, searchIds(r))
+	})
+
+	t.Run("should rebuild the index on replace", func(t *testing.T) {
+		// Arrange
+		rp, _ := NewRepositoryVehicleSearch(NewRepositoryReadVehicleMap(fixture(), internal.TextMatchExact))
+		db := map[int]internal.Vehicle{
+			7: {Id: 7, VehicleAttributes: internal.VehicleAttributes{Brand: "Renault", Model: "Clio"}},
+		}
+
+		// Act
+		_, err := rp.Replace(db)
+		before, _ := rp.Search("fiesta", 0)
+		after, _ := rp.Search("clio", 0)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []int{}, searchIds(before))
+		assert.Equal(t, []int{7}, searchIds(after))
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"app/internal"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldText is a function that returns the key of a text under internal.TextMatchNormalized without its diacritics, e.g. "Citroën" to "citroen"
+// - words are compared like normalized finds compare texts, diacritics aside
+func foldText(s string) string {
+	key := internal.TextMatchNormalized.Key(s)
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, key)
+	if err != nil {
+		folded = key
+	}
+	return folded
+}
+
+// tokenize is a function that splits a folded text into its words, made of letters and digits
+func tokenize(s string) []string {
+	return strings.FieldsFunc(foldText(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxEdits is a function that returns the number of typos tolerated in a word of a query
+// - short words and words with digits, like years and registrations, must match exactly
+// - a word tolerates less typos than a third of its runes, so that it shares a bigram with any word it matches, see searchIndex.candidates
+func maxEdits(word string) int {
+	if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return 0
+	}
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	}
+	return 2
+}
+
+// bigrams is a function that returns the distinct pairs of consecutive runes of a word, the first one paired with "^"
+// - a word one edit away from another loses at most three of its bigrams, e.g. a transposition
+func bigrams(word string) (b map[string]struct{}) {
+	r := append([]rune{'^'}, []rune(word)...)
+	b = make(map[string]struct{}, len(r)-1)
+	for i := 1; i < len(r); i++ {
+		b[string(r[i-1:i+1])] = struct{}{}
+	}
+	return
+}
+
+// editDistance is a function that returns the number of single rune edits that turn a into b
+// - edits are insertions, deletions, substitutions and transpositions of adjacent runes, e.g. "fuchsia" is 2 away from "fuscia"
+// - it returns max+1 if the distance exceeds max
+func editDistance(a string, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	// d[i+1][j+1] is the distance between the first i runes of a and the first j runes of b, the first row and column bound the transpositions
+	inf := len(ra) + len(rb)
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+		d[i][0] = inf
+		if i > 0 {
+			d[i][1] = i - 1
+		}
+	}
+	for j := range d[0] {
+		d[0][j] = inf
+		if j > 0 {
+			d[1][j] = j - 1
+		}
+	}
+
+	// last is the last row of a each rune was seen in
+	last := make(map[rune]int)
+	for i := 1; i <= len(ra); i++ {
+		// match is the last column of b that matched the rune of a
+		match := 0
+		for j := 1; j <= len(rb); j++ {
+			i1, j1 := last[rb[j-1]], match
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				match = j
+			}
+			d[i+1][j+1] = min(d[i][j]+cost, d[i+1][j]+1, d[i][j+1]+1, d[i1][j1]+(i-i1-1)+1+(j-j1-1))
+		}
+		last[ra[i-1]] = i
+	}
+	return min(d[len(ra)+1][len(rb)+1], max+1)
+}
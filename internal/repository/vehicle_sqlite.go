@@ -0,0 +1,680 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver is the name of the database/sql driver RepositoryVehicleSQLite must be opened with
+// - it is the SQLite driver with the fold_text function, used to compare normalized texts
+const SQLiteDriver = "sqlite3_vehicles"
+
+func init() {
+	sql.Register(SQLiteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("fold_text", internal.TextMatchNormalized.Key, true)
+		},
+	})
+}
+
+// sqliteMigrations are the schema migrations of RepositoryVehicleSQLite, applied in order
+// - never edit a released migration, append a new one instead
+var sqliteMigrations = []string{
+	// 1: vehicles table
+	`CREATE TABLE vehicles (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		brand        TEXT    NOT NULL,
+		model        TEXT    NOT NULL,
+		registration TEXT    NOT NULL,
+		color        TEXT    NOT NULL,
+		year         INTEGER NOT NULL,
+		passengers   INTEGER NOT NULL,
+		max_speed    REAL    NOT NULL,
+		fuel_type    TEXT    NOT NULL,
+		transmission TEXT    NOT NULL,
+		weight       REAL    NOT NULL,
+		height       REAL    NOT NULL,
+		length       REAL    NOT NULL,
+		width        REAL    NOT NULL
+	)`,
+	// 2: indexes for the static finds
+	`CREATE INDEX idx_vehicles_brand_year ON vehicles (brand, year);
+	CREATE INDEX idx_vehicles_color_year ON vehicles (color, year);
+	CREATE INDEX idx_vehicles_year ON vehicles (year);
+	CREATE INDEX idx_vehicles_weight ON vehicles (weight)`,
+	// 3: index for the find by registration, which is not unique
+	`CREATE INDEX idx_vehicles_registration ON vehicles (registration)`,
+}
+
+// sqliteColumns are the columns of the vehicles table, in the order scanned by scanVehicles
+const sqliteColumns = "id, brand, model, registration, color, year, passengers, max_speed, fuel_type, transmission, weight, height, length, width"
+
+// sqliteUpdate is the statement that replaces the attributes of a vehicle, its arguments are given by sqliteUpdateArgs
+const sqliteUpdate = `UPDATE vehicles SET brand = ?, model = ?, registration = ?, color = ?, year = ?, passengers = ?, max_speed = ?, fuel_type = ?, transmission = ?, weight = ?, height = ?, length = ?, width = ?
+	WHERE id = ?`
+
+// sqliteQuerier is an interface that represents what runs the selects of the repository, a database or a transaction
+type sqliteQuerier interface {
+	// Query is a method that runs a query that returns rows
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// NewRepositoryVehicleSQLite is a function that returns a new instance of RepositoryVehicleSQLite
+// - db must be opened with SQLiteDriver and Migrate must be called before using the repository
+// - match is the way text criteria are compared, the vehicles are stored in the form it says
+func NewRepositoryVehicleSQLite(db *sql.DB, match internal.TextMatch) *RepositoryVehicleSQLite {
+	return &RepositoryVehicleSQLite{db: db, match: match}
+}
+
+// RepositoryVehicleSQLite is a struct that represents a vehicle repository backed by SQLite
+// - normalized text criteria are compared with fold_text, which the indexes do not cover
+type RepositoryVehicleSQLite struct {
+	// db is the database connection pool
+	db *sql.DB
+	// mu serializes the writes of the process, so that a patch is not aborted by a write committed after its read
+	mu sync.Mutex
+	// written is set by every write of the process and cleared by every replace, guarded by mu
+	// - writes of former processes are not tracked, the application never replaces the vehicles of a database it did not import
+	written bool
+	// match is the way text criteria are compared
+	match internal.TextMatch
+}
+
+// Migrate is a method that applies the pending schema migrations
+func (r *RepositoryVehicleSQLite) Migrate() (err error) {
+	_, err = r.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`)
+	if err != nil {
+		return
+	}
+
+	// current version
+	var version int
+	err = r.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return
+	}
+
+	// pending migrations, each one in its own transaction
+	for i := version; i < len(sqliteMigrations); i++ {
+		err = r.inTx(func(tx *sql.Tx) (err error) {
+			_, err = tx.Exec(sqliteMigrations[i])
+			if err != nil {
+				return fmt.Errorf("migration %d: %w", i+1, err)
+			}
+			_, err = tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, i+1)
+			return
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (r *RepositoryVehicleSQLite) FindAll() (v map[int]internal.Vehicle, err error) {
+	v, err = r.query(`SELECT ` + sqliteColumns + ` FROM vehicles`)
+	return
+}
+
+// FindByID is a method that returns the vehicle with the id
+func (r *RepositoryVehicleSQLite) FindByID(id int) (v internal.Vehicle, err error) {
+	vs, err := r.query(`SELECT `+sqliteColumns+` FROM vehicles WHERE id = ?`, id)
+	if err != nil {
+		return
+	}
+
+	v, ok := vs[id]
+	if !ok {
+		err = internal.ErrRepositoryNotFound
+		return
+	}
+
+	return
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (r *RepositoryVehicleSQLite) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	v, err = sqliteRegistry{q: r.db, match: r.match}.FindByRegistration(registration)
+	return
+}
+
+// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
+func (r *RepositoryVehicleSQLite) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = r.query(`SELECT `+sqliteColumns+` FROM vehicles WHERE `+sqliteText("color", r.match)+` = ? AND year = ?`, r.match.Key(color), fabricationYear)
+	return
+}
+
+// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
+func (r *RepositoryVehicleSQLite) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	v, err = r.query(`SELECT `+sqliteColumns+` FROM vehicles WHERE `+sqliteText("brand", r.match)+` = ? AND year BETWEEN ? AND ?`, r.match.Key(brand), startYear, endYear)
+	return
+}
+
+// FindByBrand is a method that returns a map of vehicles that match the brand
+func (r *RepositoryVehicleSQLite) FindByBrand(brand string) (v map[int]internal.Vehicle, err error) {
+	v, err = r.query(`SELECT `+sqliteColumns+` FROM vehicles WHERE `+sqliteText("brand", r.match)+` = ?`, r.match.Key(brand))
+	return
+}
+
+// FindByWeightRange is a method that returns a map of vehicles that match the weight range
+func (r *RepositoryVehicleSQLite) FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]internal.Vehicle, err error) {
+	v, err = r.query(`SELECT `+sqliteColumns+` FROM vehicles WHERE weight BETWEEN ? AND ?`, fromWeight, toWeight)
+	return
+}
+
+// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+func (r *RepositoryVehicleSQLite) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	where, args := sqliteWhere(filter, r.match)
+	v, err = r.query(`SELECT `+sqliteColumns+` FROM vehicles`+where, args...)
+	return
+}
+
+// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+func (r *RepositoryVehicleSQLite) FindPage(filter internal.VehicleFilter, page internal.PageQuery) (p internal.VehiclePage, err error) {
+	err = checkPage(page)
+	if err != nil {
+		return
+	}
+	where, args := sqliteWhere(filter, r.match)
+
+	// total
+	err = r.db.QueryRow(`SELECT COUNT(*) FROM vehicles`+where, args...).Scan(&p.Total)
+	if err != nil {
+		return
+	}
+
+	// window: sort fields are named like the columns, ties broken by id
+	order := make([]string, 0, len(page.Sort)+1)
+	for _, key := range page.Sort {
+		if key.Desc {
+			order = append(order, string(key.Field)+" DESC")
+		} else {
+			order = append(order, string(key.Field))
+		}
+	}
+	order = append(order, "id")
+	if page.After != nil {
+		seek, seekArgs := sqliteSeek(page.Sort, *page.After)
+		if where == "" {
+			where = " WHERE " + seek
+		} else {
+			where += " AND " + seek
+		}
+		args = append(args, seekArgs...)
+	}
+	// - one more vehicle than the limit is read to know whether more follow
+	limit := -1
+	if page.Limit > 0 {
+		limit = page.Limit + 1
+	}
+	rows, err := r.db.Query(`SELECT `+sqliteColumns+` FROM vehicles`+where+` ORDER BY `+strings.Join(order, ", ")+` LIMIT ? OFFSET ?`, append(args, limit, page.Offset)...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	p.Vehicles = make([]internal.Vehicle, 0)
+	for rows.Next() {
+		var vh internal.Vehicle
+		err = scanVehicle(rows, &vh)
+		if err != nil {
+			return
+		}
+		p.Vehicles = append(p.Vehicles, vh)
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+	if page.Limit > 0 && len(p.Vehicles) > page.Limit {
+		p.Vehicles = p.Vehicles[:page.Limit]
+		p.More = true
+	}
+	return
+}
+
+// sqliteSeek is a function that returns the condition of the vehicles sorted after the vehicle after, with its arguments
+// - (k1 > a1) OR (k1 = a1 AND k2 > a2) OR ... OR (k1 = a1 AND ... AND id > a.id), with < for descending keys
+func sqliteSeek(sort []internal.VehicleSortKey, after internal.Vehicle) (cond string, args []any) {
+	keys := append(append([]internal.VehicleSortKey{}, sort...), internal.VehicleSortKey{Field: internal.SortById})
+	terms := make([]string, 0, len(keys))
+	for i, key := range keys {
+		term := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			term = append(term, string(prev.Field)+" = ?")
+			args = append(args, sqliteSortValue(prev.Field, &after))
+		}
+		op := " > ?"
+		if key.Desc {
+			op = " < ?"
+		}
+		term = append(term, string(key.Field)+op)
+		args = append(args, sqliteSortValue(key.Field, &after))
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+	}
+	cond = "(" + strings.Join(terms, " OR ") + ")"
+	return
+}
+
+// sqliteSortValue is a function that returns the value of a sort field of v, as an argument of a query
+func sqliteSortValue(field internal.VehicleSortField, v *internal.Vehicle) any {
+	switch ref := field.Ref(v).(type) {
+	case *int:
+		return *ref
+	case *float64:
+		return *ref
+	case *string:
+		return *ref
+	}
+	return nil
+}
+
+// Save is a method that saves a new vehicle and sets its id
+// - the vehicle is checked and inserted in one transaction, the check sees the vehicles through it
+func (r *RepositoryVehicleSQLite) Save(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vh := r.match.Vehicle(internal.Vehicle{VehicleAttributes: v.VehicleAttributes})
+	err = r.inTx(func(tx *sql.Tx) (err error) {
+		// check vehicle
+		if check != nil {
+			err = check(vh, sqliteRegistry{q: tx, match: r.match})
+			if err != nil {
+				return
+			}
+		}
+
+		// insert vehicle
+		result, err := tx.Exec(`INSERT INTO vehicles (brand, model, registration, color, year, passengers, max_speed, fuel_type, transmission, weight, height, length, width)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			vh.Brand, vh.Model, vh.Registration, vh.Color, vh.FabricationYear, vh.Capacity, vh.MaxSpeed, vh.FuelType, vh.Transmission, vh.Weight, vh.Height, vh.Length, vh.Width,
+		)
+		if err != nil {
+			return
+		}
+
+		// set id
+		id, err := result.LastInsertId()
+		if err != nil {
+			return
+		}
+		vh.Id = int(id)
+		return
+	})
+	if err != nil {
+		return
+	}
+	*v = vh
+	r.written = true
+
+	return
+}
+
+// Update is a method that replaces an existing vehicle
+// - the vehicle is checked and updated in one transaction, the check sees the vehicles through it
+func (r *RepositoryVehicleSQLite) Update(v *internal.Vehicle, check internal.VehicleWriteCheck) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	vh := r.match.Vehicle(*v)
+	err = r.inTx(func(tx *sql.Tx) (err error) {
+		// check if vehicle exists
+		var exists bool
+		err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM vehicles WHERE id = ?)`, vh.Id).Scan(&exists)
+		if err != nil {
+			return
+		}
+		if !exists {
+			err = internal.ErrRepositoryNotFound
+			return
+		}
+
+		// check vehicle
+		if check != nil {
+			err = check(vh, sqliteRegistry{q: tx, match: r.match})
+			if err != nil {
+				return
+			}
+		}
+
+		// update vehicle
+		_, err = tx.Exec(sqliteUpdate, sqliteUpdateArgs(vh)...)
+		return
+	})
+	if err != nil {
+		return
+	}
+	*v = vh
+	r.written = true
+	return
+}
+
+// Patch is a method that applies a partial update to an existing vehicle as a single write and returns the result
+// - the vehicle is read, checked and written in one transaction, the check sees the vehicles through it
+func (r *RepositoryVehicleSQLite) Patch(id int, patch internal.VehiclePatch, check internal.VehicleWriteCheck) (v internal.Vehicle, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.inTx(func(tx *sql.Tx) (err error) {
+		// get vehicle
+		vs, err := queryVehicles(tx, `SELECT `+sqliteColumns+` FROM vehicles WHERE id = ?`, id)
+		if err != nil {
+			return
+		}
+		vh, ok := vs[id]
+		if !ok {
+			err = internal.ErrRepositoryNotFound
+			return
+		}
+
+		// apply patch
+		patch.Apply(&vh)
+		vh = r.match.Vehicle(vh)
+		if check != nil {
+			err = check(vh, sqliteRegistry{q: tx, match: r.match})
+			if err != nil {
+				return
+			}
+		}
+
+		// update vehicle
+		_, err = tx.Exec(sqliteUpdate, sqliteUpdateArgs(vh)...)
+		if err != nil {
+			return
+		}
+		v = vh
+		return
+	})
+	if err != nil {
+		v = internal.Vehicle{}
+		return
+	}
+	r.written = true
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (r *RepositoryVehicleSQLite) Delete(id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, err := r.db.Exec(`DELETE FROM vehicles WHERE id = ?`, id)
+	if err != nil {
+		return
+	}
+
+	err = checkAffected(result)
+	r.written = r.written || err == nil
+	return
+}
+
+// Replace is a method that atomically replaces all the vehicles and reports the differences
+func (r *RepositoryVehicleSQLite) Replace(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, true)
+	return
+}
+
+// ReplaceUnwritten is a method that replaces all the vehicles, only if none was written by the process since the last replace
+func (r *RepositoryVehicleSQLite) ReplaceUnwritten(v map[int]internal.Vehicle) (rp internal.ReloadReport, err error) {
+	rp, err = r.replace(v, false)
+	return
+}
+
+// replace is a method that atomically replaces all the vehicles and reports the differences
+// - unless force, returns ErrRepositoryWritten if the process wrote vehicles since the last replace
+func (r *RepositoryVehicleSQLite) replace(v map[int]internal.Vehicle, force bool) (rp internal.ReloadReport, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check writes
+	if !force && r.written {
+		err = internal.ErrRepositoryWritten
+		return
+	}
+
+	// stored form, compared with the stored vehicles by the diff
+	db := make(map[int]internal.Vehicle, len(v))
+	for key, value := range v {
+		db[key] = r.match.Vehicle(value)
+	}
+
+	err = r.inTx(func(tx *sql.Tx) (err error) {
+		// diff
+		rows, err := tx.Query(`SELECT ` + sqliteColumns + ` FROM vehicles`)
+		if err != nil {
+			return
+		}
+		old, err := scanVehicles(rows)
+		if err != nil {
+			return
+		}
+		rp = diffVehicles(old, db)
+
+		// swap
+		_, err = tx.Exec(`DELETE FROM vehicles`)
+		if err != nil {
+			return
+		}
+		stmt, err := tx.Prepare(`INSERT INTO vehicles (` + sqliteColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return
+		}
+		defer stmt.Close()
+		for _, vh := range db {
+			_, err = stmt.Exec(vh.Id, vh.Brand, vh.Model, vh.Registration, vh.Color, vh.FabricationYear, vh.Capacity, vh.MaxSpeed, vh.FuelType, vh.Transmission, vh.Weight, vh.Height, vh.Length, vh.Width)
+			if err != nil {
+				return
+			}
+		}
+		return
+	})
+	if err != nil {
+		rp = internal.ReloadReport{}
+		return
+	}
+	r.written = false
+	return
+}
+
+// Import is a method that replaces all the vehicles with the ones of the loader
+// - meant to be run once to move an existing file into the database
+func (r *RepositoryVehicleSQLite) Import(ld internal.LoaderVehicle) (rp internal.ReloadReport, err error) {
+	v, err := ld.Load()
+	if err != nil {
+		return
+	}
+
+	rp, err = r.Replace(v)
+	return
+}
+
+// Count is a method that returns the number of vehicles
+func (r *RepositoryVehicleSQLite) Count() (n int, err error) {
+	err = r.db.QueryRow(`SELECT COUNT(*) FROM vehicles`).Scan(&n)
+	return
+}
+
+// Ready is a method that returns ErrNotReady if the database can not be reached or its vehicles table read
+func (r *RepositoryVehicleSQLite) Ready(ctx context.Context) (err error) {
+	err = r.db.PingContext(ctx)
+	if err != nil {
+		err = fmt.Errorf("%w: database: %s", internal.ErrNotReady, err.Error())
+		return
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM vehicles)`).Scan(&exists)
+	if err != nil {
+		err = fmt.Errorf("%w: vehicles table: %s", internal.ErrNotReady, err.Error())
+	}
+	return
+}
+
+// query is a method that runs a select over the vehicles table and returns the rows as a map
+func (r *RepositoryVehicleSQLite) query(query string, args ...any) (v map[int]internal.Vehicle, err error) {
+	v, err = queryVehicles(r.db, query, args...)
+	return
+}
+
+// queryVehicles is a function that runs a select over the vehicles table and returns the rows as a map
+func queryVehicles(q sqliteQuerier, query string, args ...any) (v map[int]internal.Vehicle, err error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return
+	}
+
+	v, err = scanVehicles(rows)
+	return
+}
+
+// sqliteUpdateArgs is a function that returns the arguments of sqliteUpdate for a vehicle
+func sqliteUpdateArgs(v internal.Vehicle) []any {
+	return []any{v.Brand, v.Model, v.Registration, v.Color, v.FabricationYear, v.Capacity, v.MaxSpeed, v.FuelType, v.Transmission, v.Weight, v.Height, v.Length, v.Width, v.Id}
+}
+
+// sqliteRegistry is a struct that implements the VehicleRegistry interface over a database or a transaction
+type sqliteRegistry struct {
+	// q runs the selects
+	q sqliteQuerier
+	// match is the way text criteria are compared
+	match internal.TextMatch
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (g sqliteRegistry) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	v, err = queryVehicles(g.q, `SELECT `+sqliteColumns+` FROM vehicles WHERE `+sqliteText("registration", g.match)+` = ?`, g.match.Key(registration))
+	if err != nil {
+		return
+	}
+
+	if len(v) == 0 {
+		v, err = nil, internal.ErrRepositoryNotFound
+		return
+	}
+
+	return
+}
+
+// inTx is a method that runs fn in a transaction, committing it only if fn succeeds
+func (r *RepositoryVehicleSQLite) inTx(fn func(tx *sql.Tx) (err error)) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+
+	err = fn(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return
+	}
+
+	err = tx.Commit()
+	return
+}
+
+// scanVehicles is a function that scans and closes rows selected with sqliteColumns
+func scanVehicles(rows *sql.Rows) (v map[int]internal.Vehicle, err error) {
+	defer rows.Close()
+
+	vehicles := make(map[int]internal.Vehicle)
+	for rows.Next() {
+		var vh internal.Vehicle
+		err = scanVehicle(rows, &vh)
+		if err != nil {
+			return
+		}
+		vehicles[vh.Id] = vh
+	}
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	v = vehicles
+	return
+}
+
+// sqliteText is a function that returns the expression a text column is compared with, as match says
+func sqliteText(column string, match internal.TextMatch) string {
+	if match == internal.TextMatchNormalized {
+		return "fold_text(" + column + ")"
+	}
+	return column
+}
+
+// sqliteWhere is a function that translates a filter into a where clause and its arguments
+// - an empty filter returns an empty clause
+func sqliteWhere(f internal.VehicleFilter, match internal.TextMatch) (where string, args []any) {
+	var conditions []string
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	text := func(column string, criteria *string) {
+		if criteria != nil {
+			add(sqliteText(column, match)+" = ?", match.Key(*criteria))
+		}
+	}
+
+	text("brand", f.Brand)
+	text("model", f.Model)
+	text("color", f.Color)
+	text("fuel_type", f.FuelType)
+	text("transmission", f.Transmission)
+	if f.YearMin != nil {
+		add("year >= ?", *f.YearMin)
+	}
+	if f.YearMax != nil {
+		add("year <= ?", *f.YearMax)
+	}
+	if f.CapacityMin != nil {
+		add("passengers >= ?", *f.CapacityMin)
+	}
+	if f.CapacityMax != nil {
+		add("passengers <= ?", *f.CapacityMax)
+	}
+	if f.SpeedMin != nil {
+		add("max_speed >= ?", *f.SpeedMin)
+	}
+	if f.SpeedMax != nil {
+		add("max_speed <= ?", *f.SpeedMax)
+	}
+	if f.WeightMin != nil {
+		add("weight >= ?", *f.WeightMin)
+	}
+	if f.WeightMax != nil {
+		add("weight <= ?", *f.WeightMax)
+	}
+
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return
+}
+
+// scanVehicle is a function that scans the current row, selected with sqliteColumns, into vh
+func scanVehicle(rows *sql.Rows, vh *internal.Vehicle) (err error) {
+	err = rows.Scan(&vh.Id, &vh.Brand, &vh.Model, &vh.Registration, &vh.Color, &vh.FabricationYear, &vh.Capacity, &vh.MaxSpeed, &vh.FuelType, &vh.Transmission, &vh.Weight, &vh.Height, &vh.Length, &vh.Width)
+	return
+}
+
+// checkAffected is a function that returns ErrRepositoryNotFound if no row was affected
+func checkAffected(result sql.Result) (err error) {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	if n == 0 {
+		err = internal.ErrRepositoryNotFound
+	}
+	return
+}
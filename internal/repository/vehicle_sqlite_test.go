@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/repository/repotest"
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openSQLite is a function that opens a migrated SQLite repository on a file of a temporary directory
+func openSQLite(t *testing.T, match internal.TextMatch) (rp *RepositoryVehicleSQLite) {
+	db, err := sql.Open(SQLiteDriver, filepath.Join(t.TempDir(), "vehicles.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	rp = NewRepositoryVehicleSQLite(db, match)
+	require.NoError(t, rp.Migrate())
+	return
+}
+
+// openSQLiteWith is a function that opens a migrated SQLite repository holding the vehicles of db
+func openSQLiteWith(t *testing.T, match internal.TextMatch, db map[int]internal.Vehicle) (rp *RepositoryVehicleSQLite) {
+	rp = openSQLite(t, match)
+	_, err := rp.Replace(db)
+	require.NoError(t, err)
+	return
+}
+
+// TestRepositoryVehicleSQLite_Migrate is a test function that tests the Migrate method
+func TestRepositoryVehicleSQLite_Migrate(t *testing.T) {
+	t.Run("should be idempotent and create the indexes", func(t *testing.T) {
+		// Arrange
+		rp := openSQLite(t, internal.TextMatchExact)
+
+		// Act
+		err := rp.Migrate()
+
+		// Assert
+		assert.NoError(t, err)
+		var version int
+		require.NoError(t, rp.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version))
+		assert.Equal(t, len(sqliteMigrations), version)
+		var indexes int
+		require.NoError(t, rp.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = 'vehicles' AND name LIKE 'idx_%'`).Scan(&indexes))
+		assert.Equal(t, 5, indexes)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Import is a test function that tests the Import method
+func TestRepositoryVehicleSQLite_Import(t *testing.T) {
+	t.Run("should import the vehicles of the loader", func(t *testing.T) {
+		// Arrange
+		rp := openSQLite(t, internal.TextMatchExact)
+		ld := new(loader.MockLoader)
+		ld.On("Load").Return(fixture(), nil)
+
+		// Act
+		report, err := rp.Import(ld)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, internal.ReloadReport{Added: 3}, report)
+		n, err := rp.Count()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		ld.AssertExpectations(t)
+	})
+
+	t.Run("should keep the vehicles when the loader fails", func(t *testing.T) {
+		// Arrange
+		rp := openSQLiteWith(t, internal.TextMatchExact, fixture())
+		ld := new(loader.MockLoader)
+		ld.On("Load").Return(map[int]internal.Vehicle(nil), errors.New("load error"))
+
+		// Act
+		_, err := rp.Import(ld)
+
+		// Assert
+		assert.EqualError(t, err, "load error")
+		n, err := rp.Count()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Ready is a test function that tests the Ready method
+func TestRepositoryVehicleSQLite_Ready(t *testing.T) {
+	t.Run("should be ready once migrated", func(t *testing.T) {
+		// Arrange
+		rp := openSQLite(t, internal.TextMatchExact)
+
+		// Act
+		err := rp.Ready(context.Background())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not be ready when the database is closed", func(t *testing.T) {
+		// Arrange
+		rp := openSQLite(t, internal.TextMatchExact)
+		require.NoError(t, rp.db.Close())
+
+		// Act
+		err := rp.Ready(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, internal.ErrNotReady)
+	})
+
+	t.Run("should not be ready without the vehicles table", func(t *testing.T) {
+		// Arrange
+		rp := openSQLite(t, internal.TextMatchExact)
+		_, err := rp.db.Exec(`DROP TABLE vehicles`)
+		require.NoError(t, err)
+
+		// Act
+		err = rp.Ready(context.Background())
+
+		// Assert
+		assert.ErrorIs(t, err, internal.ErrNotReady)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Save is a test function that tests the Save method
+func TestRepositoryVehicleSQLite_Save(t *testing.T) {
+	t.Run("should keep only one of the concurrent saves of a registration", func(t *testing.T) {
+		assertConcurrentUniqueSaves(t, openSQLiteWith(t, internal.TextMatchExact, fixture()))
+	})
+}
+
+// TestRepositoryVehicleSQLite_Patch is a test function that tests the Patch method
+func TestRepositoryVehicleSQLite_Patch(t *testing.T) {
+	t.Run("should not lose concurrent patches", func(t *testing.T) {
+		assertConcurrentPatches(t, openSQLiteWith(t, internal.TextMatchExact, fixture()))
+	})
+
+	t.Run("should roll back the patch when the check fails", func(t *testing.T) {
+		// Arrange
+		rp := openSQLiteWith(t, internal.TextMatchExact, fixture())
+		color := "Black"
+		errCheck := errors.New("check failed")
+		before, err := rp.FindByID(1)
+		require.NoError(t, err)
+
+		// Act
+		_, err = rp.Patch(1, internal.VehiclePatch{Color: &color}, func(v internal.Vehicle, registry internal.VehicleRegistry) error {
+			if _, err := registry.FindByRegistration(v.Registration); err != nil {
+				return err
+			}
+			return errCheck
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, errCheck)
+		after, err := rp.FindByID(1)
+		assert.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Conformance is a test function that runs the conformance suite against the SQLite repository
+func TestRepositoryVehicleSQLite_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+		return openSQLiteWith(t, internal.TextMatchExact, db)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Normalized is a test function that runs the normalized matching suite against the SQLite repository
+func TestRepositoryVehicleSQLite_Normalized(t *testing.T) {
+	repotest.RunNormalized(t, func(t *testing.T, db map[int]internal.Vehicle) internal.RepositoryReadVehicle {
+		return openSQLiteWith(t, internal.TextMatchNormalized, db)
+	})
+}
+
+// TestRepositoryVehicleSQLite_Write is a test function that runs the write conformance suite against the SQLite repository
+func TestRepositoryVehicleSQLite_Write(t *testing.T) {
+	repotest.RunWrite(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		return openSQLiteWith(t, internal.TextMatchExact, db)
+	})
+	repotest.RunWriteNormalized(t, func(t *testing.T, db map[int]internal.Vehicle) repotest.Repository {
+		return openSQLiteWith(t, internal.TextMatchNormalized, db)
+	})
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"app/internal"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAggregator is a struct that implements the AggregatorVehicle interface
+type MockAggregator struct {
+	mock.Mock
+}
+
+// Aggregate is a method that groups the vehicles and computes the metrics of each group
+func (m *MockAggregator) Aggregate(q internal.AggregateQuery) (a []internal.VehicleAggregate, err error) {
+	args := m.Called(q)
+	return args.Get(0).([]internal.VehicleAggregate), args.Error(1)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"app/internal"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDataset is a struct that implements the DatasetVehicle interface
+type MockDataset struct {
+	mock.Mock
+}
+
+// Info is a method that returns the summary of the vehicles currently served
+func (m *MockDataset) Info() (i internal.DatasetInfo, err error) {
+	args := m.Called()
+	return args.Get(0).(internal.DatasetInfo), args.Error(1)
+}
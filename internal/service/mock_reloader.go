@@ -0,0 +1,18 @@
+package service
+
+import (
+	"app/internal"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReloader is a struct that implements the ReloaderVehicle interface
+type MockReloader struct {
+	mock.Mock
+}
+
+// Reload is a method that loads the vehicles again and replaces the current ones
+func (m *MockReloader) Reload(force bool) (r internal.ReloadReport, err error) {
+	args := m.Called(force)
+	return args.Get(0).(internal.ReloadReport), args.Error(1)
+}
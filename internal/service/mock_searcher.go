@@ -0,0 +1,18 @@
+package service
+
+import (
+	"app/internal"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearcher is a struct that implements the SearcherVehicle interface
+type MockSearcher struct {
+	mock.Mock
+}
+
+// Search is a method that returns the vehicles that best match the text
+func (m *MockSearcher) Search(text string, limit int) (r internal.VehicleSearchResult, err error) {
+	args := m.Called(text, limit)
+	return args.Get(0).(internal.VehicleSearchResult), args.Error(1)
+}
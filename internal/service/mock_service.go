@@ -11,6 +11,18 @@ type MockService struct {
 	mock.Mock
 }
 
+// FindByID is a method that returns the vehicle with the id
+func (m *MockService) FindByID(id int) (v internal.Vehicle, err error) {
+	args := m.Called(id)
+	return args.Get(0).(internal.Vehicle), args.Error(1)
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (m *MockService) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	args := m.Called(registration)
+	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
+}
+
 // FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 func (m *MockService) FindByColorAndYear(color string, fabricationYear int) (v map[int]internal.Vehicle, err error) {
 	args := m.Called(color, fabricationYear)
@@ -30,9 +42,15 @@ func (m *MockService) AverageMaxSpeedByBrand(brand string) (a float64, err error
 }
 
 // AverageCapacityByBrand is a method that returns the average capacity of the vehicles by brand
-func (m *MockService) AverageCapacityByBrand(brand string) (a int, err error) {
+func (m *MockService) AverageCapacityByBrand(brand string) (a internal.VehicleAverage, err error) {
 	args := m.Called(brand)
-	return args.Get(0).(int), args.Error(1)
+	return args.Get(0).(internal.VehicleAverage), args.Error(1)
+}
+
+// Stats is a method that returns the statistics of a numeric field, for each group of vehicles sorted by group
+func (m *MockService) Stats(field internal.VehicleNumericField, groupBy internal.VehicleGroupField) (s []internal.VehicleStats, err error) {
+	args := m.Called(field, groupBy)
+	return args.Get(0).([]internal.VehicleStats), args.Error(1)
 }
 
 // SearchByWeightRange is a method that returns a map of vehicles that match the weight range
@@ -40,3 +58,39 @@ func (m *MockService) SearchByWeightRange(query internal.SearchQuery, ok bool) (
 	args := m.Called(query, ok)
 	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
 }
+
+// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+func (m *MockService) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	args := m.Called(filter)
+	return args.Get(0).(map[int]internal.Vehicle), args.Error(1)
+}
+
+// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+func (m *MockService) FindPage(filter internal.VehicleFilter, page internal.PageQuery) (p internal.VehiclePage, err error) {
+	args := m.Called(filter, page)
+	return args.Get(0).(internal.VehiclePage), args.Error(1)
+}
+
+// Save is a method that registers a new vehicle
+func (m *MockService) Save(v *internal.Vehicle) (err error) {
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// Update is a method that replaces an existing vehicle
+func (m *MockService) Update(v *internal.Vehicle) (err error) {
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// Patch is a method that partially updates an existing vehicle and returns the result
+func (m *MockService) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	args := m.Called(id, patch)
+	return args.Get(0).(internal.Vehicle), args.Error(1)
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (m *MockService) Delete(id int) (err error) {
+	args := m.Called(id)
+	return args.Error(0)
+}
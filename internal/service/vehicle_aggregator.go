@@ -0,0 +1,240 @@
+package service
+
+import (
+	"app/internal"
+	"cmp"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// NewAggregatorVehicleDefault is a function that returns a new instance of AggregatorVehicleDefault
+// - match is the way the repository compares texts
+func NewAggregatorVehicleDefault(rp internal.RepositoryReadVehicle, match internal.TextMatch) *AggregatorVehicleDefault {
+	return &AggregatorVehicleDefault{rp: rp, match: match}
+}
+
+// AggregatorVehicleDefault is a struct that implements the AggregatorVehicle interface
+type AggregatorVehicleDefault struct {
+	// rp is the repository the vehicles are read from
+	rp internal.RepositoryReadVehicle
+	// match is the way the repository compares texts, vehicles are grouped the same way
+	match internal.TextMatch
+}
+
+// fieldAccumulator is a struct that accumulates the values of a field of a group in a single pass
+type fieldAccumulator struct {
+	// sum is the sum of the values
+	sum float64
+	// min is the minimum value
+	min float64
+	// max is the maximum value
+	max float64
+}
+
+// groupAccumulator is a struct that accumulates a group of vehicles in a single pass
+type groupAccumulator struct {
+	// group are the values of the grouping fields
+	group []string
+	// count is the number of vehicles
+	count int
+	// fields are the accumulators of the fields used by the metrics
+	fields map[internal.VehicleNumericField]*fieldAccumulator
+}
+
+// add is a method that accumulates a vehicle
+func (g *groupAccumulator) add(v internal.Vehicle) {
+	g.count++
+	for field, acc := range g.fields {
+		value, _ := field.Value(v)
+		if g.count == 1 {
+			acc.min, acc.max = value, value
+		}
+		acc.sum += value
+		acc.min = math.Min(acc.min, value)
+		acc.max = math.Max(acc.max, value)
+	}
+}
+
+// metric is a method that returns the value of a metric of the group
+func (g *groupAccumulator) metric(m internal.VehicleMetric) (value float64) {
+	if m.Func == internal.AggregateCount {
+		value = float64(g.count)
+		return
+	}
+
+	acc := g.fields[m.Field]
+	switch m.Func {
+	case internal.AggregateSum:
+		value = acc.sum
+	case internal.AggregateAvg:
+		value = acc.sum / float64(g.count)
+	case internal.AggregateMin:
+		value = acc.min
+	case internal.AggregateMax:
+		value = acc.max
+	}
+	return
+}
+
+// meets is a method that returns true if the group meets every threshold
+func (g *groupAccumulator) meets(having []internal.VehicleHaving) bool {
+	for _, h := range having {
+		if holds, _ := h.Op.Compare(g.metric(h.Metric), h.Value); !holds {
+			return false
+		}
+	}
+	return true
+}
+
+// Aggregate is a method that groups the vehicles and computes the metrics of each group
+// - values that match as the repository compares texts are one group, see groupName
+func (a *AggregatorVehicleDefault) Aggregate(q internal.AggregateQuery) (ag []internal.VehicleAggregate, err error) {
+	// check query
+	if len(q.Metrics) == 0 {
+		q.Metrics = []internal.VehicleMetric{{Func: internal.AggregateCount}}
+	}
+	err = checkAggregateQuery(q)
+	if err != nil {
+		return
+	}
+
+	// fields used by the metrics and the thresholds
+	fields := make(map[internal.VehicleNumericField]bool)
+	for _, m := range q.Metrics {
+		if m.Field != "" {
+			fields[m.Field] = true
+		}
+	}
+	for _, h := range q.Having {
+		if h.Metric.Field != "" {
+			fields[h.Metric.Field] = true
+		}
+	}
+
+	// get vehicles
+	v, err := a.rp.FindAll()
+	if err != nil {
+		return
+	}
+
+	// accumulate: a single pass over the vehicles
+	groups := make(map[string]*groupAccumulator)
+	for _, vehicle := range v {
+		group := make([]string, len(q.GroupBy))
+		keys := make([]string, len(q.GroupBy))
+		for i, field := range q.GroupBy {
+			group[i], _ = field.Key(vehicle)
+			keys[i] = a.match.Key(group[i])
+		}
+		// - the unit separator can not be part of a value read from the vehicles file
+		key := strings.Join(keys, "\x1f")
+
+		g, ok := groups[key]
+		if !ok {
+			g = &groupAccumulator{group: group, fields: make(map[internal.VehicleNumericField]*fieldAccumulator, len(fields))}
+			for field := range fields {
+				g.fields[field] = &fieldAccumulator{}
+			}
+			groups[key] = g
+		}
+		for i := range group {
+			g.group[i] = groupName(g.group[i], group[i], false)
+		}
+		g.add(vehicle)
+	}
+
+	// metrics of the groups that meet the thresholds
+	ag = make([]internal.VehicleAggregate, 0, len(groups))
+	for _, g := range groups {
+		if !g.meets(q.Having) {
+			continue
+		}
+		metrics := make([]float64, len(q.Metrics))
+		for i, m := range q.Metrics {
+			metrics[i] = g.metric(m)
+		}
+		ag = append(ag, internal.VehicleAggregate{Group: g.group, Metrics: metrics})
+	}
+
+	// sort
+	sortAggregates(ag, q)
+	return
+}
+
+// checkAggregateQuery is a function that returns ErrServiceInvalidAggregate if the query is not valid
+func checkAggregateQuery(q internal.AggregateQuery) (err error) {
+	// grouping fields: known and not repeated
+	grouped := make(map[internal.VehicleGroupField]bool, len(q.GroupBy))
+	for _, field := range q.GroupBy {
+		if !field.Valid() || grouped[field] {
+			err = fmt.Errorf("%w: invalid group_by %s", internal.ErrServiceInvalidAggregate, field)
+			return
+		}
+		grouped[field] = true
+	}
+
+	// metrics: known and not repeated
+	selected := make(map[string]bool, len(q.Metrics))
+	for _, m := range q.Metrics {
+		if !m.Valid() || selected[m.String()] {
+			err = fmt.Errorf("%w: invalid metric %s", internal.ErrServiceInvalidAggregate, m)
+			return
+		}
+		selected[m.String()] = true
+	}
+
+	// thresholds: known metric and operator, finite value
+	for _, h := range q.Having {
+		_, ok := h.Op.Compare(0, 0)
+		if !h.Metric.Valid() || !ok || math.IsNaN(h.Value) || math.IsInf(h.Value, 0) {
+			err = fmt.Errorf("%w: invalid having %s%s%v", internal.ErrServiceInvalidAggregate, h.Metric, h.Op, h.Value)
+			return
+		}
+	}
+
+	// sort keys: a grouping field or a selected metric
+	for _, key := range q.Sort {
+		if !grouped[internal.VehicleGroupField(key.Key)] && !selected[key.Key] {
+			err = fmt.Errorf("%w: invalid sort %s", internal.ErrServiceInvalidAggregate, key.Key)
+			return
+		}
+	}
+	return
+}
+
+// sortAggregates is a function that sorts the groups by the keys of the query, then by the grouping fields
+func sortAggregates(ag []internal.VehicleAggregate, q internal.AggregateQuery) {
+	groupIndex := make(map[string]int, len(q.GroupBy))
+	for i, field := range q.GroupBy {
+		groupIndex[string(field)] = i
+	}
+	metricIndex := make(map[string]int, len(q.Metrics))
+	for i, m := range q.Metrics {
+		metricIndex[m.String()] = i
+	}
+
+	sort.Slice(ag, func(i, j int) bool {
+		for _, key := range q.Sort {
+			var c int
+			if k, ok := groupIndex[key.Key]; ok {
+				c = strings.Compare(ag[i].Group[k], ag[j].Group[k])
+			} else {
+				c = cmp.Compare(ag[i].Metrics[metricIndex[key.Key]], ag[j].Metrics[metricIndex[key.Key]])
+			}
+			if c != 0 {
+				if key.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		for k := range q.GroupBy {
+			if c := strings.Compare(ag[i].Group[k], ag[j].Group[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
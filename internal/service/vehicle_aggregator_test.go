@@ -0,0 +1,206 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AggregateTestCase is a struct that represents a test case for the Aggregate method
+type AggregateTestCase struct {
+	mockRepository     *repository.MockRepository
+	aggregator         *AggregatorVehicleDefault
+	name               string
+	match              internal.TextMatch
+	query              internal.AggregateQuery
+	returnedVehicles   map[int]internal.Vehicle
+	repositoryError    error
+	expectedAggregates []internal.VehicleAggregate
+	expectedError      error
+	obtainedAggregates []internal.VehicleAggregate
+	obtainedError      error
+	mockOnCalled       bool
+	isError            bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *AggregateTestCase) Arrange() {
+	tc.mockRepository = &repository.MockRepository{}
+	tc.aggregator = NewAggregatorVehicleDefault(tc.mockRepository, tc.match)
+	tc.mockRepository.On("FindAll").Return(tc.returnedVehicles, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *AggregateTestCase) Act() {
+	tc.obtainedAggregates, tc.obtainedError = tc.aggregator.Aggregate(tc.query)
+}
+
+// Assert is a method that asserts the test case
+func (tc *AggregateTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.ErrorIs(t, tc.obtainedError, tc.expectedError)
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedAggregates, tc.obtainedAggregates)
+	}
+	if tc.mockOnCalled {
+		assert.True(t, tc.mockRepository.AssertNumberOfCalls(t, "FindAll", 1))
+	} else {
+		assert.True(t, tc.mockRepository.AssertNotCalled(t, "FindAll"))
+	}
+}
+
+// TestAggregator_Aggregate is a function that tests the Aggregate method
+func TestAggregator_Aggregate(t *testing.T) {
+	vehicles := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "gas", MaxSpeed: 100, Weight: 1000}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "gas", MaxSpeed: 200, Weight: 1500}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel", MaxSpeed: 150, Weight: 2000}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", FuelType: "gas", MaxSpeed: 120, Weight: 900}},
+		5: {Id: 5, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", FuelType: "gas", MaxSpeed: 140, Weight: 950}},
+	}
+	count := internal.VehicleMetric{Func: internal.AggregateCount}
+	avgSpeed := internal.VehicleMetric{Func: internal.AggregateAvg, Field: internal.NumericMaxSpeed}
+	sumWeight := internal.VehicleMetric{Func: internal.AggregateSum, Field: internal.NumericWeight}
+	minWeight := internal.VehicleMetric{Func: internal.AggregateMin, Field: internal.NumericWeight}
+	maxWeight := internal.VehicleMetric{Func: internal.AggregateMax, Field: internal.NumericWeight}
+
+	// Create the test cases
+	testCases := []AggregateTestCase{
+		{
+			// This test evaluates that Aggregate returns the metrics of each group sorted by the grouping fields
+			name: "should return the metrics of each group sorted by the grouping fields",
+			query: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByFuelType},
+				Metrics: []internal.VehicleMetric{count, avgSpeed, sumWeight, minWeight, maxWeight},
+			},
+			returnedVehicles: vehicles,
+			expectedAggregates: []internal.VehicleAggregate{
+				{Group: []string{"Fiat", "gas"}, Metrics: []float64{2, 130, 1850, 900, 950}},
+				{Group: []string{"Ford", "diesel"}, Metrics: []float64{1, 150, 2000, 2000, 2000}},
+				{Group: []string{"Ford", "gas"}, Metrics: []float64{2, 150, 2500, 1000, 1500}},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Aggregate returns a single group counting every vehicle when the query is empty
+			name:             "should return a single group counting every vehicle when the query is empty",
+			returnedVehicles: vehicles,
+			expectedAggregates: []internal.VehicleAggregate{
+				{Group: []string{}, Metrics: []float64{5}},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Aggregate returns only the groups that meet every threshold, even on metrics not selected
+			name: "should return only the groups that meet every threshold, even on metrics not selected",
+			query: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByFuelType},
+				Metrics: []internal.VehicleMetric{avgSpeed},
+				Having: []internal.VehicleHaving{
+					{Metric: count, Op: internal.CompareGte, Value: 2},
+					{Metric: sumWeight, Op: internal.CompareLt, Value: 2000},
+				},
+			},
+			returnedVehicles: vehicles,
+			expectedAggregates: []internal.VehicleAggregate{
+				{Group: []string{"Fiat", "gas"}, Metrics: []float64{130}},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Aggregate sorts the groups by the sort keys, then by the grouping fields
+			name: "should sort the groups by the sort keys, then by the grouping fields",
+			query: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByFuelType},
+				Metrics: []internal.VehicleMetric{avgSpeed},
+				Sort:    []internal.AggregateSortKey{{Key: "avg(max_speed)", Desc: true}},
+			},
+			returnedVehicles: vehicles,
+			expectedAggregates: []internal.VehicleAggregate{
+				{Group: []string{"Ford", "diesel"}, Metrics: []float64{150}},
+				{Group: []string{"Ford", "gas"}, Metrics: []float64{150}},
+				{Group: []string{"Fiat", "gas"}, Metrics: []float64{130}},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Aggregate returns no groups when there are no vehicles
+			name:               "should return no groups when there are no vehicles",
+			query:              internal.AggregateQuery{GroupBy: []internal.VehicleGroupField{internal.GroupByBrand}},
+			returnedVehicles:   map[int]internal.Vehicle{},
+			expectedAggregates: []internal.VehicleAggregate{},
+			mockOnCalled:       true,
+		},
+		{
+			// This test evaluates that Aggregate returns ErrServiceInvalidAggregate when a grouping field is repeated
+			name:          "should return ErrServiceInvalidAggregate when a grouping field is repeated",
+			query:         internal.AggregateQuery{GroupBy: []internal.VehicleGroupField{internal.GroupByBrand, internal.GroupByBrand}},
+			expectedError: internal.ErrServiceInvalidAggregate,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Aggregate returns ErrServiceInvalidAggregate when a metric is not valid
+			name:          "should return ErrServiceInvalidAggregate when a metric is not valid",
+			query:         internal.AggregateQuery{Metrics: []internal.VehicleMetric{{Func: internal.AggregateAvg}}},
+			expectedError: internal.ErrServiceInvalidAggregate,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Aggregate returns ErrServiceInvalidAggregate when a threshold operator is unknown
+			name: "should return ErrServiceInvalidAggregate when a threshold operator is unknown",
+			query: internal.AggregateQuery{
+				Having: []internal.VehicleHaving{{Metric: count, Op: "~", Value: 2}},
+			},
+			expectedError: internal.ErrServiceInvalidAggregate,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Aggregate returns ErrServiceInvalidAggregate when a sort key is not selected
+			name: "should return ErrServiceInvalidAggregate when a sort key is not selected",
+			query: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand},
+				Sort:    []internal.AggregateSortKey{{Key: "color"}},
+			},
+			expectedError: internal.ErrServiceInvalidAggregate,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Aggregate groups the values that match as the repository compares texts, named after the smallest one
+			name:  "should group the values that match as the repository compares texts",
+			match: internal.TextMatchNormalized,
+			query: internal.AggregateQuery{
+				GroupBy: []internal.VehicleGroupField{internal.GroupByBrand},
+			},
+			returnedVehicles: map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "ford"}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}},
+				3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "FORD"}},
+				4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat"}},
+			},
+			expectedAggregates: []internal.VehicleAggregate{
+				{Group: []string{"FORD"}, Metrics: []float64{3}},
+				{Group: []string{"Fiat"}, Metrics: []float64{1}},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Aggregate returns the error of the repository
+			name:             "should return the error of the repository",
+			returnedVehicles: map[int]internal.Vehicle(nil),
+			repositoryError:  errRepository,
+			expectedError:    errRepository,
+			mockOnCalled:     true,
+			isError:          true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
@@ -5,12 +5,30 @@ import "app/internal"
 // ServiceVehicleDefault is a struct that represents the default service for vehicles
 type ServiceVehicleDefault struct {
 	// rp is the repository that will be used by the service
-	rp internal.RepositoryReadVehicle
+	rp internal.RepositoryVehicle
+	// vv checks the domain rules of the vehicles written, nil if there are none
+	vv internal.VehicleValidator
+	// match is the way the repository compares texts, vehicles are grouped the same way
+	match internal.TextMatch
 }
 
 // NewServiceVehicleDefault is a function that returns a new instance of ServiceVehicleDefault
-func NewServiceVehicleDefault(rp internal.RepositoryReadVehicle) *ServiceVehicleDefault {
-	return &ServiceVehicleDefault{rp: rp}
+// - vv can be nil, in which case the vehicles are written without checking the domain rules
+// - match is the way the repository compares texts
+func NewServiceVehicleDefault(rp internal.RepositoryVehicle, vv internal.VehicleValidator, match internal.TextMatch) *ServiceVehicleDefault {
+	return &ServiceVehicleDefault{rp: rp, vv: vv, match: match}
+}
+
+// FindByID is a method that returns the vehicle with the id
+func (s *ServiceVehicleDefault) FindByID(id int) (v internal.Vehicle, err error) {
+	v, err = s.rp.FindByID(id)
+	return
+}
+
+// FindByRegistration is a method that returns a map of vehicles that match the registration
+func (s *ServiceVehicleDefault) FindByRegistration(registration string) (v map[int]internal.Vehicle, err error) {
+	v, err = s.rp.FindByRegistration(registration)
+	return
 }
 
 // FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
@@ -20,7 +38,13 @@ func (s *ServiceVehicleDefault) FindByColorAndYear(color string, fabricationYear
 }
 
 // FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
+// - an inverted or negative range returns ErrServiceInvalidFind
 func (s *ServiceVehicleDefault) FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]internal.Vehicle, err error) {
+	err = checkIntRange(internal.ErrServiceInvalidFind, "start_year", &startYear, "end_year", &endYear)
+	if err != nil {
+		return
+	}
+
 	v, err = s.rp.FindByBrandAndYearRange(brand, startYear, endYear)
 	return
 }
@@ -49,7 +73,7 @@ func (s *ServiceVehicleDefault) AverageMaxSpeedByBrand(brand string) (a float64,
 }
 		
 // AverageCapacityByBrand is a method that returns the average capacity of the vehicles by brand
-func (s *ServiceVehicleDefault) AverageCapacityByBrand(brand string) (a int, err error) {
+func (s *ServiceVehicleDefault) AverageCapacityByBrand(brand string) (a internal.VehicleAverage, err error) {
 	// get vehicles by brand
 	v, err := s.rp.FindByBrand(brand)
 	if err != nil {
@@ -67,19 +91,95 @@ func (s *ServiceVehicleDefault) AverageCapacityByBrand(brand string) (a int, err
 		totalCapacity += vehicle.Capacity
 	}
 
-	a = totalCapacity / len(v)
+	a = internal.VehicleAverage{
+		Value: float64(totalCapacity) / float64(len(v)),
+		Count: len(v),
+	}
 	return
 }
 
 // SearchByWeightRange
+// - an inverted, negative, NaN or infinite range returns ErrServiceInvalidSearch
+// - a half-open range is found as a filter on the weight, see FindByFilter
 func (s *ServiceVehicleDefault) SearchByWeightRange(query internal.SearchQuery, ok bool) (v map[int]internal.Vehicle, err error) {
 	// check if query is set
-	if !ok {
+	if !ok || (query.FromWeight == nil && query.ToWeight == nil) {
 		v, err = s.rp.FindAll()
 		return
 	}
 
-	v, err = s.rp.FindByWeightRange(query.FromWeight, query.ToWeight)
+	// check the range
+	err = checkFloatRange(internal.ErrServiceInvalidSearch, "weight_min", query.FromWeight, "weight_max", query.ToWeight)
+	if err != nil {
+		return
+	}
+
+	if query.FromWeight == nil || query.ToWeight == nil {
+		v, err = s.rp.FindByFilter(internal.VehicleFilter{WeightMin: query.FromWeight, WeightMax: query.ToWeight})
+		return
+	}
+	v, err = s.rp.FindByWeightRange(*query.FromWeight, *query.ToWeight)
+	return
+}
+
+// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+// - a range of the filter that can not match any vehicle returns ErrServiceInvalidFind or ErrServiceInvalidSearch
+func (s *ServiceVehicleDefault) FindByFilter(filter internal.VehicleFilter) (v map[int]internal.Vehicle, err error) {
+	err = checkFilter(filter)
+	if err != nil {
+		return
+	}
+
+	v, err = s.rp.FindByFilter(filter)
+	return
+}
+
+// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+// - a range of the filter that can not match any vehicle returns ErrServiceInvalidFind or ErrServiceInvalidSearch
+func (s *ServiceVehicleDefault) FindPage(filter internal.VehicleFilter, page internal.PageQuery) (p internal.VehiclePage, err error) {
+	err = checkFilter(filter)
+	if err != nil {
+		return
+	}
+
+	p, err = s.rp.FindPage(filter, page)
+	return
+}
+
+// Save is a method that registers a new vehicle
+// - the repository checks the vehicle and saves it as a single write, so two vehicles can not take the same registration at once
+func (s *ServiceVehicleDefault) Save(v *internal.Vehicle) (err error) {
+	err = s.rp.Save(v, s.check)
+	return
+}
+
+// Update is a method that replaces an existing vehicle
+// - the repository checks the vehicle and updates it as a single write
+func (s *ServiceVehicleDefault) Update(v *internal.Vehicle) (err error) {
+	err = s.rp.Update(v, s.check)
+	return
+}
+
+// Patch is a method that partially updates an existing vehicle and returns the result
+// - the repository applies the patch and checks the result as a single write, so concurrent patches are not lost
+func (s *ServiceVehicleDefault) Patch(id int, patch internal.VehiclePatch) (v internal.Vehicle, err error) {
+	v, err = s.rp.Patch(id, patch, s.check)
+	return
+}
+
+// Delete is a method that deletes a vehicle by its id
+func (s *ServiceVehicleDefault) Delete(id int) (err error) {
+	err = s.rp.Delete(id)
+	return
+}
+
+// check is a method that returns a *VehicleValidationError if the vehicle breaks any domain rule
+// - registrations are checked unique against the registry given by the repository, inside its write
+func (s *ServiceVehicleDefault) check(v internal.Vehicle, registry internal.VehicleRegistry) (err error) {
+	if s.vv == nil {
+		return
+	}
+
+	err = s.vv.Validate(v, registry)
 	return
 }
-	
\ No newline at end of file
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestCase is an interface that represents a test case
@@ -25,7 +26,7 @@ type TestCaseSetup struct {
 // Setup is a function that returns a new instance of TestCaseSetup
 func Setup() *TestCaseSetup {
 	mockRepository := repository.MockRepository{}
-	service := NewServiceVehicleDefault(&mockRepository)
+	service := NewServiceVehicleDefault(&mockRepository, nil, internal.TextMatchExact)
 
 	return &TestCaseSetup{
 		mockRepository: &mockRepository,
@@ -355,10 +356,10 @@ type AverageCapacityByBrandTestCase struct {
 	name             string
 	brand            string
 	returnedVehicles map[int]internal.Vehicle
-	expectedCapacity int
+	expectedCapacity internal.VehicleAverage
 	expectedError    error
 	repositoryError  error
-	obtainedCapacity int
+	obtainedCapacity internal.VehicleAverage
 	obtainedError    error
 	mockOnCalled     bool
 	isError          bool
@@ -442,7 +443,21 @@ func TestService_AverageCapacityByBrand(t *testing.T) {
 					},
 				},
 			},
-			expectedCapacity: 5,
+			expectedCapacity: internal.VehicleAverage{Value: 5, Count: 2},
+			mockOnCalled:     true,
+		},
+		{
+			// This test evaluates that AverageCapacityByBrand does not truncate the average capacity
+			name:  "should not truncate the average capacity",
+			brand: "Fiat",
+			returnedVehicles: map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Capacity: 4}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Capacity: 4}},
+				3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Capacity: 4}},
+				4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Capacity: 3}},
+				5: {Id: 5, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Capacity: 4}},
+			},
+			expectedCapacity: internal.VehicleAverage{Value: 3.8, Count: 5},
 			mockOnCalled:     true,
 		},
 		{
@@ -455,8 +470,8 @@ func TestService_AverageCapacityByBrand(t *testing.T) {
 			isError:         true,
 		},
 		{
-			// This test evaluates that AverageCapacityByBrand returns 0 and ErrServiceNoVehicles when there are no vehicles that match the brand
-			name:          "should return 0 and ErrServiceNoVehicles when there are no vehicles that match the brand",
+			// This test evaluates that AverageCapacityByBrand returns a zero average and ErrServiceNoVehicles when there are no vehicles that match the brand
+			name:          "should return a zero average and ErrServiceNoVehicles when there are no vehicles that match the brand",
 			brand:         "Ford",
 			expectedError: internal.ErrServiceNoVehicles,
 			mockOnCalled:  true,
@@ -493,7 +508,7 @@ type SearchByWeightRangeTestCase struct {
 func (tc *SearchByWeightRangeTestCase) Arrange() {
 	tc.setup = Setup()
 	tc.setup.mockRepository.On("FindAll").Return(tc.returnedVehiclesFromFindAll, tc.returnedFindAllError)
-	tc.setup.mockRepository.On("FindByWeightRange", tc.query.FromWeight, tc.query.ToWeight).Return(tc.returnedVehiclesFromFindByWeightRange, tc.returnedFindByWeightRangeError)
+	tc.setup.mockRepository.On("FindByWeightRange", mock.Anything, mock.Anything).Return(tc.returnedVehiclesFromFindByWeightRange, tc.returnedFindByWeightRangeError)
 }
 
 // Act is a method that executes the test case
@@ -517,22 +532,24 @@ func (tc *SearchByWeightRangeTestCase) Assert(t *testing.T) {
 		assert.True(t, tc.setup.mockRepository.AssertNotCalled(t, "FindAll"))
 	}
 	if tc.mockOnCalledFindByWeightRange {
-		assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindByWeightRange", tc.query.FromWeight, tc.query.ToWeight))
+		assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindByWeightRange", *tc.query.FromWeight, *tc.query.ToWeight))
 	} else {
-		assert.True(t, tc.setup.mockRepository.AssertNotCalled(t, "FindByWeightRange", tc.query.FromWeight, tc.query.ToWeight))
+		assert.True(t, tc.setup.mockRepository.AssertNotCalled(t, "FindByWeightRange", mock.Anything, mock.Anything))
 	}
 }
 
 // TestService_SearchByWeightRange is a function that tests the SearchByWeightRange method
 func TestService_SearchByWeightRange(t *testing.T) {
+	ptrFloat := func(v float64) *float64 { return &v }
+
 	// Create the test cases
 	testCases := []SearchByWeightRangeTestCase{
 		{
 			// This test evaluates that SearchByWeightRange returns the vehicles that match the weight range
 			name: "should return the vehicles that match the weight range",
 			query: internal.SearchQuery{
-				FromWeight: 1100,
-				ToWeight:   1150,
+				FromWeight: ptrFloat(1100),
+				ToWeight:   ptrFloat(1150),
 			},
 			ok: true,
 			returnedVehiclesFromFindByWeightRange: map[int]internal.Vehicle{
@@ -719,3 +736,365 @@ func TestService_SearchByWeightRange(t *testing.T) {
 		testCase.Assert(t)
 	}
 }
+
+// FindByIDTestCase is a struct that represents a test case for the FindByID method
+type FindByIDTestCase struct {
+	setup           *TestCaseSetup
+	name            string
+	id              int
+	expectedVehicle internal.Vehicle
+	expectedError   error
+	repositoryError error
+	obtainedVehicle internal.Vehicle
+	obtainedError   error
+	isError         bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindByIDTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.mockRepository.On("FindByID", tc.id).Return(tc.expectedVehicle, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *FindByIDTestCase) Act() {
+	tc.obtainedVehicle, tc.obtainedError = tc.setup.service.FindByID(tc.id)
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindByIDTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedVehicle, tc.obtainedVehicle)
+	}
+	assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindByID", tc.id))
+}
+
+// TestService_FindByID is a function that tests the FindByID method
+func TestService_FindByID(t *testing.T) {
+	// Create the test cases
+	testCases := []FindByIDTestCase{
+		{
+			// This test evaluates that FindByID returns the vehicle of the repository
+			name:            "should return the vehicle with the id",
+			id:              1,
+			expectedVehicle: internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta"}},
+		},
+		{
+			// This test evaluates that FindByID returns ErrRepositoryNotFound when the vehicle does not exist
+			name:            "should return ErrRepositoryNotFound when the vehicle does not exist",
+			id:              2,
+			repositoryError: internal.ErrRepositoryNotFound,
+			expectedError:   internal.ErrRepositoryNotFound,
+			isError:         true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
+
+// PatchTestCase is a struct that represents a test case for the Patch method
+type PatchTestCase struct {
+	setup           *TestCaseSetup
+	name            string
+	id              int
+	patch           internal.VehiclePatch
+	returnedVehicle internal.Vehicle
+	repositoryError error
+	expectedVehicle internal.Vehicle
+	expectedError   error
+	obtainedVehicle internal.Vehicle
+	obtainedError   error
+	isError         bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *PatchTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.mockRepository.On("Patch", tc.id, tc.patch).Return(tc.returnedVehicle, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *PatchTestCase) Act() {
+	tc.obtainedVehicle, tc.obtainedError = tc.setup.service.Patch(tc.id, tc.patch)
+}
+
+// Assert is a method that asserts the test case
+func (tc *PatchTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedVehicle, tc.obtainedVehicle)
+	}
+	assert.True(t, tc.setup.mockRepository.AssertCalled(t, "Patch", tc.id, tc.patch))
+	assert.True(t, tc.setup.mockRepository.AssertNotCalled(t, "Update", mock.Anything))
+}
+
+// TestService_Patch is a function that tests the Patch method
+func TestService_Patch(t *testing.T) {
+	color := "Black"
+	year := 2011
+	patched := internal.Vehicle{
+		Id: 1,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           "Ford",
+			Model:           "Fiesta",
+			Registration:    "ABC-1234",
+			Color:           "Black",
+			FabricationYear: 2011,
+			Capacity:        5,
+			MaxSpeed:        180,
+			FuelType:        "Gasoline",
+			Transmission:    "Manual",
+			Weight:          1000,
+		},
+	}
+
+	// Create the test cases
+	testCases := []PatchTestCase{
+		{
+			// This test evaluates that Patch leaves the read, the patch and the write to the repository, as a single write
+			name:            "should patch the vehicle in the repository as a single write",
+			id:              1,
+			patch:           internal.VehiclePatch{Color: &color, FabricationYear: &year},
+			returnedVehicle: patched,
+			expectedVehicle: patched,
+		},
+		{
+			// This test evaluates that Patch returns ErrRepositoryNotFound when the vehicle does not exist
+			name:            "should return ErrRepositoryNotFound when the vehicle does not exist",
+			id:              2,
+			patch:           internal.VehiclePatch{Color: &color},
+			returnedVehicle: internal.Vehicle{},
+			repositoryError: internal.ErrRepositoryNotFound,
+			expectedError:   internal.ErrRepositoryNotFound,
+			isError:         true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
+
+// DeleteTestCase is a struct that represents a test case for the Delete method
+type DeleteTestCase struct {
+	setup           *TestCaseSetup
+	name            string
+	id              int
+	repositoryError error
+	expectedError   error
+	obtainedError   error
+	isError         bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *DeleteTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.mockRepository.On("Delete", tc.id).Return(tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *DeleteTestCase) Act() {
+	tc.obtainedError = tc.setup.service.Delete(tc.id)
+}
+
+// Assert is a method that asserts the test case
+func (tc *DeleteTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+	}
+	assert.True(t, tc.setup.mockRepository.AssertCalled(t, "Delete", tc.id))
+}
+
+// TestService_Delete is a function that tests the Delete method
+func TestService_Delete(t *testing.T) {
+	// Create the test cases
+	testCases := []DeleteTestCase{
+		{
+			// This test evaluates that Delete deletes the vehicle
+			name: "should delete the vehicle",
+			id:   1,
+		},
+		{
+			// This test evaluates that Delete returns an error when the repository returns an error
+			name:            "should return an error when the repository returns an error",
+			id:              1,
+			repositoryError: internal.ErrRepositoryNotFound,
+			expectedError:   internal.ErrRepositoryNotFound,
+			isError:         true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
+
+// FindByFilterTestCase is a struct that represents a test case for the FindByFilter method
+type FindByFilterTestCase struct {
+	setup            *TestCaseSetup
+	name             string
+	filter           internal.VehicleFilter
+	returnedVehicles map[int]internal.Vehicle
+	repositoryError  error
+	expectedVehicles map[int]internal.Vehicle
+	expectedError    error
+	obtainedVehicles map[int]internal.Vehicle
+	obtainedError    error
+	isError          bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindByFilterTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.mockRepository.On("FindByFilter", tc.filter).Return(tc.returnedVehicles, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *FindByFilterTestCase) Act() {
+	tc.obtainedVehicles, tc.obtainedError = tc.setup.service.FindByFilter(tc.filter)
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindByFilterTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedVehicles, tc.obtainedVehicles)
+	}
+	assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindByFilter", tc.filter))
+}
+
+// TestService_FindByFilter is a function that tests the FindByFilter method
+func TestService_FindByFilter(t *testing.T) {
+	brand := "Ford"
+	yearMin := 2010
+
+	// Create the test cases
+	testCases := []FindByFilterTestCase{
+		{
+			// This test evaluates that FindByFilter returns the vehicles the repository matched
+			name:   "should return the vehicles that match the filter",
+			filter: internal.VehicleFilter{Brand: &brand, YearMin: &yearMin},
+			returnedVehicles: map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FabricationYear: 2010}},
+			},
+			expectedVehicles: map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FabricationYear: 2010}},
+			},
+		},
+		{
+			// This test evaluates that FindByFilter returns the error of the repository
+			name:             "should return the error of the repository",
+			filter:           internal.VehicleFilter{},
+			returnedVehicles: map[int]internal.Vehicle(nil),
+			repositoryError:  errors.New("repository error"),
+			expectedError:    errors.New("repository error"),
+			isError:          true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
+
+// FindPageTestCase is a struct that represents a test case for the FindPage method
+type FindPageTestCase struct {
+	setup           *TestCaseSetup
+	name            string
+	filter          internal.VehicleFilter
+	page            internal.PageQuery
+	returnedPage    internal.VehiclePage
+	repositoryError error
+	expectedPage    internal.VehiclePage
+	expectedError   error
+	obtainedPage    internal.VehiclePage
+	obtainedError   error
+	isError         bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *FindPageTestCase) Arrange() {
+	tc.setup = Setup()
+	tc.setup.mockRepository.On("FindPage", tc.filter, tc.page).Return(tc.returnedPage, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *FindPageTestCase) Act() {
+	tc.obtainedPage, tc.obtainedError = tc.setup.service.FindPage(tc.filter, tc.page)
+}
+
+// Assert is a method that asserts the test case
+func (tc *FindPageTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedPage, tc.obtainedPage)
+	}
+	assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindPage", tc.filter, tc.page))
+}
+
+// TestService_FindPage is a function that tests the FindPage method
+func TestService_FindPage(t *testing.T) {
+	brand := "Ford"
+
+	// Create the test cases
+	testCases := []FindPageTestCase{
+		{
+			// This test evaluates that FindPage returns the page of the repository in order
+			name:   "should return the page of the repository in order",
+			filter: internal.VehicleFilter{Brand: &brand},
+			page:   internal.PageQuery{Sort: []internal.VehicleSortKey{{Field: internal.SortByYear, Desc: true}}, Limit: 2},
+			returnedPage: internal.VehiclePage{
+				Vehicles: []internal.Vehicle{{Id: 3}, {Id: 1}},
+				Total:    3,
+			},
+			expectedPage: internal.VehiclePage{
+				Vehicles: []internal.Vehicle{{Id: 3}, {Id: 1}},
+				Total:    3,
+			},
+		},
+		{
+			// This test evaluates that FindPage returns the error of the repository
+			name:            "should return the error of the repository",
+			repositoryError: internal.ErrRepositoryInvalidFind,
+			expectedError:   internal.ErrRepositoryInvalidFind,
+			isError:         true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
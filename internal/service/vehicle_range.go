@@ -0,0 +1,66 @@
+package service
+
+import (
+	"app/internal"
+	"math"
+)
+
+// checkIntRange is a function that returns a *internal.RangeError wrapping sentinel if the range can not match any vehicle
+// - a bound is nil when the range is open on that side
+// - the error names the offending parameter: a negative bound, or the minimum when it is greater than the maximum
+func checkIntRange(sentinel error, minParam string, min *int, maxParam string, max *int) (err error) {
+	switch {
+	case min != nil && *min < 0:
+		err = &internal.RangeError{Err: sentinel, Param: minParam, Value: *min, Reason: "must not be negative"}
+	case max != nil && *max < 0:
+		err = &internal.RangeError{Err: sentinel, Param: maxParam, Value: *max, Reason: "must not be negative"}
+	case min != nil && max != nil && *min > *max:
+		err = &internal.RangeError{Err: sentinel, Param: minParam, Value: *min, MaxParam: maxParam, MaxValue: *max}
+	}
+	return
+}
+
+// checkFloatRange is a function that returns a *internal.RangeError wrapping sentinel if the range can not match any vehicle
+// - a bound is nil when the range is open on that side
+// - NaN and infinite bounds are rejected as well as negative ones
+func checkFloatRange(sentinel error, minParam string, min *float64, maxParam string, max *float64) (err error) {
+	bound := func(param string, v *float64) error {
+		switch {
+		case v == nil:
+			return nil
+		case math.IsNaN(*v) || math.IsInf(*v, 0):
+			return &internal.RangeError{Err: sentinel, Param: param, Value: *v, Reason: "must be a finite number"}
+		case *v < 0:
+			return &internal.RangeError{Err: sentinel, Param: param, Value: *v, Reason: "must not be negative"}
+		}
+		return nil
+	}
+
+	if err = bound(minParam, min); err != nil {
+		return
+	}
+	if err = bound(maxParam, max); err != nil {
+		return
+	}
+	if min != nil && max != nil && *min > *max {
+		err = &internal.RangeError{Err: sentinel, Param: minParam, Value: *min, MaxParam: maxParam, MaxValue: *max}
+	}
+	return
+}
+
+// checkFilter is a function that returns an error if a range of the filter can not match any vehicle
+// - the ranges are named after the query parameters of the filter
+// - a weight range returns ErrServiceInvalidSearch, any other range ErrServiceInvalidFind
+func checkFilter(filter internal.VehicleFilter) (err error) {
+	if err = checkIntRange(internal.ErrServiceInvalidFind, "year_min", filter.YearMin, "year_max", filter.YearMax); err != nil {
+		return
+	}
+	if err = checkIntRange(internal.ErrServiceInvalidFind, "passengers_min", filter.CapacityMin, "passengers_max", filter.CapacityMax); err != nil {
+		return
+	}
+	if err = checkFloatRange(internal.ErrServiceInvalidFind, "speed_min", filter.SpeedMin, "speed_max", filter.SpeedMax); err != nil {
+		return
+	}
+	err = checkFloatRange(internal.ErrServiceInvalidSearch, "weight_min", filter.WeightMin, "weight_max", filter.WeightMax)
+	return
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// RangeTestCase is a struct that represents a test case for the ranges checked by the read methods
+type RangeTestCase struct {
+	name          string
+	find          func(sv *ServiceVehicleDefault) error
+	expectedError error
+	expectedText  string
+}
+
+// TestService_Range is a function that tests the ranges checked by FindByBrandAndYearRange, SearchByWeightRange, FindByFilter and FindPage
+func TestService_Range(t *testing.T) {
+	ptrInt := func(v int) *int { return &v }
+	ptrFloat := func(v float64) *float64 { return &v }
+
+	// Create the test cases
+	testCases := []RangeTestCase{
+		{
+			// This test evaluates that FindByBrandAndYearRange finds a valid range
+			name: "FindByBrandAndYearRange/should find a valid range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindByBrandAndYearRange("Ford", 2010, 2010)
+				return err
+			},
+		},
+		{
+			// This test evaluates that FindByBrandAndYearRange rejects an inverted range
+			name: "FindByBrandAndYearRange/should reject an inverted range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindByBrandAndYearRange("Ford", 2012, 2009)
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidFind,
+			expectedText:  "service: invalid find: start_year 2012 is greater than end_year 2009",
+		},
+		{
+			// This test evaluates that SearchByWeightRange finds a half-open range
+			name: "SearchByWeightRange/should find a half-open range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.SearchByWeightRange(internal.SearchQuery{FromWeight: ptrFloat(1000)}, true)
+				return err
+			},
+		},
+		{
+			// This test evaluates that SearchByWeightRange rejects a negative bound of a half-open range
+			name: "SearchByWeightRange/should reject a negative bound of a half-open range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.SearchByWeightRange(internal.SearchQuery{ToWeight: ptrFloat(-1)}, true)
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidSearch,
+			expectedText:  "service: invalid search: weight_max must not be negative, got -1",
+		},
+		{
+			// This test evaluates that SearchByWeightRange rejects a NaN bound
+			name: "SearchByWeightRange/should reject a NaN bound",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.SearchByWeightRange(internal.SearchQuery{FromWeight: ptrFloat(math.NaN()), ToWeight: ptrFloat(1000)}, true)
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidSearch,
+			expectedText:  "service: invalid search: weight_min must be a finite number, got NaN",
+		},
+		{
+			// This test evaluates that SearchByWeightRange rejects an infinite bound
+			name: "SearchByWeightRange/should reject an infinite bound",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.SearchByWeightRange(internal.SearchQuery{FromWeight: ptrFloat(0), ToWeight: ptrFloat(math.Inf(1))}, true)
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidSearch,
+			expectedText:  "service: invalid search: weight_max must be a finite number, got +Inf",
+		},
+		{
+			// This test evaluates that FindByFilter rejects a negative bound
+			name: "FindByFilter/should reject a negative bound",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindByFilter(internal.VehicleFilter{WeightMin: ptrFloat(-1)})
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidSearch,
+			expectedText:  "service: invalid search: weight_min must not be negative, got -1",
+		},
+		{
+			// This test evaluates that FindPage finds a half-open range
+			name: "FindPage/should find a half-open range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindPage(internal.VehicleFilter{WeightMax: ptrFloat(1000)}, internal.PageQuery{})
+				return err
+			},
+		},
+		{
+			// This test evaluates that FindPage rejects an inverted weight range
+			name: "FindPage/should reject an inverted weight range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindPage(internal.VehicleFilter{WeightMin: ptrFloat(1050.5), WeightMax: ptrFloat(1000)}, internal.PageQuery{})
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidSearch,
+			expectedText:  "service: invalid search: weight_min 1050.5 is greater than weight_max 1000",
+		},
+		{
+			// This test evaluates that FindPage rejects an inverted year range
+			name: "FindPage/should reject an inverted year range",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindPage(internal.VehicleFilter{YearMin: ptrInt(2015), YearMax: ptrInt(2010)}, internal.PageQuery{})
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidFind,
+			expectedText:  "service: invalid find: year_min 2015 is greater than year_max 2010",
+		},
+		{
+			// This test evaluates that FindPage rejects a negative speed
+			name: "FindPage/should reject a negative speed",
+			find: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.FindPage(internal.VehicleFilter{SpeedMax: ptrFloat(-5)}, internal.PageQuery{})
+				return err
+			},
+			expectedError: internal.ErrServiceInvalidFind,
+			expectedText:  "service: invalid find: speed_max must not be negative, got -5",
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			rp := new(repository.MockRepository)
+			rp.On("FindByBrandAndYearRange", mock.Anything, mock.Anything, mock.Anything).Return(map[int]internal.Vehicle{}, nil)
+			rp.On("FindByWeightRange", mock.Anything, mock.Anything).Return(map[int]internal.Vehicle{}, nil)
+			rp.On("FindByFilter", mock.Anything).Return(map[int]internal.Vehicle{}, nil)
+			rp.On("FindPage", mock.Anything, mock.Anything).Return(internal.VehiclePage{}, nil)
+			sv := NewServiceVehicleDefault(rp, nil, internal.TextMatchExact)
+
+			// Act
+			err := testCase.find(sv)
+
+			// Assert
+			if testCase.expectedError != nil {
+				var rerr *internal.RangeError
+				assert.ErrorIs(t, err, testCase.expectedError)
+				assert.ErrorAs(t, err, &rerr)
+				assert.EqualError(t, err, testCase.expectedText)
+				assert.Empty(t, rp.Calls)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, rp.Calls, 1)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"app/internal"
+	"sync"
+)
+
+// NewReloaderVehicleDefault is a function that returns a new instance of ReloaderVehicleDefault
+func NewReloaderVehicleDefault(ld internal.LoaderVehicle, rp internal.RepositoryReplaceVehicle) *ReloaderVehicleDefault {
+	return &ReloaderVehicleDefault{ld: ld, rp: rp}
+}
+
+// ReloaderVehicleDefault is a struct that implements the ReloaderVehicle interface
+type ReloaderVehicleDefault struct {
+	// mu serializes reloads, so the watcher and the admin endpoint do not interleave
+	mu sync.Mutex
+	// ld is the loader used to read the vehicles dataset
+	ld internal.LoaderVehicle
+	// rp is the repository whose vehicles are replaced
+	rp internal.RepositoryReplaceVehicle
+}
+
+// Reload is a method that loads the vehicles again and replaces the current ones
+// - unless force, the vehicles written since the last load are kept and ErrRepositoryWritten returned
+func (r *ReloaderVehicleDefault) Reload(force bool) (rp internal.ReloadReport, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// load vehicles: on error the repository keeps the previous dataset
+	v, err := r.ld.Load()
+	if err != nil {
+		return
+	}
+
+	// replace vehicles
+	if force {
+		rp, err = r.rp.Replace(v)
+		return
+	}
+	rp, err = r.rp.ReplaceUnwritten(v)
+	return
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/repository"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ReloadTestCase is a struct that represents a test case for the Reload method
+type ReloadTestCase struct {
+	mockLoader     *loader.MockLoader
+	mockRepository *repository.MockRepository
+	reloader       *ReloaderVehicleDefault
+	name           string
+	force          bool
+	loadedVehicles map[int]internal.Vehicle
+	loaderError    error
+	returnedReport internal.ReloadReport
+	expectedReport internal.ReloadReport
+	expectedError  error
+	obtainedReport internal.ReloadReport
+	obtainedError  error
+	replaceError   error
+	mockOnCalled   bool
+	isError        bool
+}
+
+// replaceMethod is a method that returns the repository method the reload is expected to replace the vehicles with
+func (tc *ReloadTestCase) replaceMethod() string {
+	if tc.force {
+		return "Replace"
+	}
+	return "ReplaceUnwritten"
+}
+
+// Arrange is a method that sets up the test case
+func (tc *ReloadTestCase) Arrange() {
+	tc.mockLoader = &loader.MockLoader{}
+	tc.mockRepository = &repository.MockRepository{}
+	tc.reloader = NewReloaderVehicleDefault(tc.mockLoader, tc.mockRepository)
+	tc.mockLoader.On("Load").Return(tc.loadedVehicles, tc.loaderError)
+	tc.mockRepository.On(tc.replaceMethod(), tc.loadedVehicles).Return(tc.returnedReport, tc.replaceError)
+}
+
+// Act is a method that executes the test case
+func (tc *ReloadTestCase) Act() {
+	tc.obtainedReport, tc.obtainedError = tc.reloader.Reload(tc.force)
+}
+
+// Assert is a method that asserts the test case
+func (tc *ReloadTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.Error(t, tc.obtainedError)
+		assert.EqualError(t, tc.obtainedError, tc.expectedError.Error())
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedReport, tc.obtainedReport)
+	}
+	if tc.mockOnCalled {
+		assert.True(t, tc.mockRepository.AssertCalled(t, tc.replaceMethod(), tc.loadedVehicles))
+	} else {
+		assert.True(t, tc.mockRepository.AssertNotCalled(t, tc.replaceMethod(), tc.loadedVehicles))
+	}
+}
+
+// TestReloader_Reload is a function that tests the Reload method
+func TestReloader_Reload(t *testing.T) {
+	// Create the test cases
+	testCases := []ReloadTestCase{
+		{
+			// This test evaluates that Reload replaces the repository vehicles with the loaded ones
+			name:           "should replace the repository vehicles with the loaded ones",
+			loadedVehicles: map[int]internal.Vehicle{1: {Id: 1}},
+			returnedReport: internal.ReloadReport{Added: 1, Removed: 2},
+			expectedReport: internal.ReloadReport{Added: 1, Removed: 2},
+			mockOnCalled:   true,
+		},
+		{
+			// This test evaluates that Reload keeps the vehicles written since the last load unless forced
+			name:           "should keep the vehicles written since the last load unless forced",
+			loadedVehicles: map[int]internal.Vehicle{1: {Id: 1}},
+			replaceError:   internal.ErrRepositoryWritten,
+			expectedError:  internal.ErrRepositoryWritten,
+			mockOnCalled:   true,
+			isError:        true,
+		},
+		{
+			// This test evaluates that Reload replaces the vehicles written since the last load when forced
+			name:           "should replace the vehicles written since the last load when forced",
+			force:          true,
+			loadedVehicles: map[int]internal.Vehicle{1: {Id: 1}},
+			returnedReport: internal.ReloadReport{Changed: 1},
+			expectedReport: internal.ReloadReport{Changed: 1},
+			mockOnCalled:   true,
+		},
+		{
+			// This test evaluates that Reload keeps the previous vehicles when the loader fails
+			name:          "should keep the previous vehicles when the loader fails",
+			loaderError:   errors.New("invalid character"),
+			expectedError: errors.New("invalid character"),
+			isError:       true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
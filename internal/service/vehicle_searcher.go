@@ -0,0 +1,35 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+)
+
+// NewSearcherVehicleDefault is a function that returns a new instance of SearcherVehicleDefault
+func NewSearcherVehicleDefault(rp internal.RepositorySearchVehicle) *SearcherVehicleDefault {
+	return &SearcherVehicleDefault{rp: rp}
+}
+
+// SearcherVehicleDefault is a struct that implements the SearcherVehicle interface
+type SearcherVehicleDefault struct {
+	// rp is the repository the vehicles are searched in
+	rp internal.RepositorySearchVehicle
+}
+
+// Search is a method that returns the vehicles that best match the text
+func (s *SearcherVehicleDefault) Search(text string, limit int) (r internal.VehicleSearchResult, err error) {
+	// check query
+	if strings.TrimSpace(text) == "" {
+		err = fmt.Errorf("%w: empty text", internal.ErrServiceInvalidSearch)
+		return
+	}
+	if limit < 0 {
+		err = fmt.Errorf("%w: negative limit", internal.ErrServiceInvalidSearch)
+		return
+	}
+
+	// search
+	r, err = s.rp.Search(text, limit)
+	return
+}
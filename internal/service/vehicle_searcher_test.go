@@ -0,0 +1,105 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// SearchTestCase is a struct that represents a test case for the Search method
+type SearchTestCase struct {
+	mockRepository  *repository.MockRepository
+	searcher        *SearcherVehicleDefault
+	name            string
+	text            string
+	limit           int
+	returnedResult  internal.VehicleSearchResult
+	repositoryError error
+	expectedResult  internal.VehicleSearchResult
+	expectedError   error
+	obtainedResult  internal.VehicleSearchResult
+	obtainedError   error
+	mockOnCalled    bool
+	isError         bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *SearchTestCase) Arrange() {
+	tc.mockRepository = &repository.MockRepository{}
+	tc.searcher = NewSearcherVehicleDefault(tc.mockRepository)
+	tc.mockRepository.On("Search", tc.text, tc.limit).Return(tc.returnedResult, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *SearchTestCase) Act() {
+	tc.obtainedResult, tc.obtainedError = tc.searcher.Search(tc.text, tc.limit)
+}
+
+// Assert is a method that asserts the test case
+func (tc *SearchTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.ErrorIs(t, tc.obtainedError, tc.expectedError)
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Equal(t, tc.expectedResult, tc.obtainedResult)
+	}
+	if tc.mockOnCalled {
+		assert.True(t, tc.mockRepository.AssertCalled(t, "Search", tc.text, tc.limit))
+	} else {
+		assert.True(t, tc.mockRepository.AssertNotCalled(t, "Search", tc.text, tc.limit))
+	}
+}
+
+// TestSearcher_Search is a function that tests the Search method
+func TestSearcher_Search(t *testing.T) {
+	result := internal.VehicleSearchResult{
+		Hits:  []internal.VehicleSearchHit{{Vehicle: internal.Vehicle{Id: 1}, Score: 3}},
+		Total: 4,
+	}
+
+	// Create the test cases
+	testCases := []SearchTestCase{
+		{
+			// This test evaluates that Search returns the result of the repository
+			name:           "should return the result of the repository",
+			text:           "ford",
+			limit:          1,
+			returnedResult: result,
+			expectedResult: result,
+			mockOnCalled:   true,
+		},
+		{
+			// This test evaluates that Search returns ErrServiceInvalidSearch when the text is blank
+			name:          "should return ErrServiceInvalidSearch when the text is blank",
+			text:          "  ",
+			expectedError: internal.ErrServiceInvalidSearch,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Search returns ErrServiceInvalidSearch when the limit is negative
+			name:          "should return ErrServiceInvalidSearch when the limit is negative",
+			text:          "ford",
+			limit:         -1,
+			expectedError: internal.ErrServiceInvalidSearch,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Search returns the error of the repository
+			name:            "should return the error of the repository",
+			text:            "ford",
+			repositoryError: errRepository,
+			expectedError:   errRepository,
+			mockOnCalled:    true,
+			isError:         true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
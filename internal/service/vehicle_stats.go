@@ -0,0 +1,108 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Stats is a method that returns the statistics of a numeric field, for each group of vehicles sorted by group
+// - values that match as the repository compares texts are one group, see groupName
+func (s *ServiceVehicleDefault) Stats(field internal.VehicleNumericField, groupBy internal.VehicleGroupField) (st []internal.VehicleStats, err error) {
+	// check field and grouping
+	if !field.Valid() {
+		err = fmt.Errorf("%w: unknown field %s", internal.ErrServiceInvalidStats, field)
+		return
+	}
+	if groupBy != "" && !groupBy.Valid() {
+		err = fmt.Errorf("%w: unknown group_by %s", internal.ErrServiceInvalidStats, groupBy)
+		return
+	}
+
+	// get vehicles
+	v, err := s.rp.FindAll()
+	if err != nil {
+		return
+	}
+
+	// check if there are vehicles
+	if len(v) == 0 {
+		err = internal.ErrServiceNoVehicles
+		return
+	}
+
+	// group values
+	groups := make(map[string][]float64)
+	names := make(map[string]string)
+	for _, vehicle := range v {
+		var group string
+		if groupBy != "" {
+			group, _ = groupBy.Key(vehicle)
+		}
+		key := s.match.Key(group)
+		names[key] = groupName(names[key], group, len(groups[key]) == 0)
+		value, _ := field.Value(vehicle)
+		groups[key] = append(groups[key], value)
+	}
+
+	// stats by group
+	st = make([]internal.VehicleStats, 0, len(groups))
+	for key, values := range groups {
+		stats := computeStats(values)
+		stats.Group = names[key]
+		st = append(st, stats)
+	}
+	sort.Slice(st, func(i, j int) bool { return st[i].Group < st[j].Group })
+
+	return
+}
+
+// groupName is a function that returns the name of a group once one more of its values is seen
+// - a group is named after the smallest of its values, so that its name does not depend on the order of the vehicles
+func groupName(name string, value string, first bool) string {
+	if first || value < name {
+		return value
+	}
+	return name
+}
+
+// computeStats is a function that returns the statistics of a non empty list of values
+// - values are sorted in place
+func computeStats(values []float64) (st internal.VehicleStats) {
+	sort.Float64s(values)
+
+	// mean
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	// population standard deviation
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+
+	st = internal.VehicleStats{
+		Count:  len(values),
+		Min:    values[0],
+		Max:    values[len(values)-1],
+		Mean:   mean,
+		Median: percentile(values, 50),
+		StdDev: math.Sqrt(squares / float64(len(values))),
+		P90:    percentile(values, 90),
+		P99:    percentile(values, 99),
+	}
+	return
+}
+
+// percentile is a function that returns the p-th percentile of sorted values
+// - linearly interpolated between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"app/internal"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// StatsTestCase is a struct that represents a test case for the Stats method
+type StatsTestCase struct {
+	setup            *TestCaseSetup
+	name             string
+	match            internal.TextMatch
+	field            internal.VehicleNumericField
+	groupBy          internal.VehicleGroupField
+	returnedVehicles map[int]internal.Vehicle
+	repositoryError  error
+	expectedStats    []internal.VehicleStats
+	expectedError    error
+	obtainedStats    []internal.VehicleStats
+	obtainedError    error
+	mockOnCalled     bool
+	isError          bool
+}
+
+// Arrange is a method that sets up the test case
+func (tc *StatsTestCase) Arrange() {
+	tc.setup = Setup()
+	if tc.match != "" {
+		tc.setup.service.match = tc.match
+	}
+	tc.setup.mockRepository.On("FindAll").Return(tc.returnedVehicles, tc.repositoryError)
+}
+
+// Act is a method that executes the test case
+func (tc *StatsTestCase) Act() {
+	tc.obtainedStats, tc.obtainedError = tc.setup.service.Stats(tc.field, tc.groupBy)
+}
+
+// Assert is a method that asserts the test case
+func (tc *StatsTestCase) Assert(t *testing.T) {
+	if tc.isError {
+		assert.ErrorIs(t, tc.obtainedError, tc.expectedError)
+		assert.Nil(t, tc.obtainedStats)
+	} else {
+		assert.NoError(t, tc.obtainedError)
+		assert.Len(t, tc.obtainedStats, len(tc.expectedStats))
+		for i := range tc.expectedStats {
+			assert.Equal(t, tc.expectedStats[i].Group, tc.obtainedStats[i].Group)
+			assert.Equal(t, tc.expectedStats[i].Count, tc.obtainedStats[i].Count)
+			assert.InDelta(t, tc.expectedStats[i].Min, tc.obtainedStats[i].Min, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].Max, tc.obtainedStats[i].Max, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].Mean, tc.obtainedStats[i].Mean, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].Median, tc.obtainedStats[i].Median, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].StdDev, tc.obtainedStats[i].StdDev, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].P90, tc.obtainedStats[i].P90, 1e-9)
+			assert.InDelta(t, tc.expectedStats[i].P99, tc.obtainedStats[i].P99, 1e-9)
+		}
+	}
+	if tc.mockOnCalled {
+		assert.True(t, tc.setup.mockRepository.AssertCalled(t, "FindAll"))
+	} else {
+		assert.True(t, tc.setup.mockRepository.AssertNotCalled(t, "FindAll"))
+	}
+}
+
+// TestService_Stats is a function that tests the Stats method
+func TestService_Stats(t *testing.T) {
+	vehicles := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 100, Capacity: 4}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 200, Capacity: 5}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 300, Capacity: 5}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 400, Capacity: 2}},
+		5: {Id: 5, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", MaxSpeed: 150, Capacity: 4}},
+	}
+
+	// Create the test cases
+	testCases := []StatsTestCase{
+		{
+			// This test evaluates that Stats returns the statistics of each group sorted by group
+			name:             "should return the statistics of each group sorted by group",
+			field:            internal.NumericMaxSpeed,
+			groupBy:          internal.GroupByBrand,
+			returnedVehicles: vehicles,
+			expectedStats: []internal.VehicleStats{
+				{Group: "Fiat", Count: 1, Min: 150, Max: 150, Mean: 150, Median: 150, StdDev: 0, P90: 150, P99: 150},
+				{Group: "Ford", Count: 4, Min: 100, Max: 400, Mean: 250, Median: 250, StdDev: 111.80339887498948, P90: 370, P99: 397},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Stats returns a single group over the whole fleet without grouping
+			name:             "should return a single group over the whole fleet without grouping",
+			field:            internal.NumericCapacity,
+			returnedVehicles: vehicles,
+			expectedStats: []internal.VehicleStats{
+				{Count: 5, Min: 2, Max: 5, Mean: 4, Median: 4, StdDev: 1.0954451150103321, P90: 5, P99: 5},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Stats returns ErrServiceInvalidStats when the field is not numeric
+			name:          "should return ErrServiceInvalidStats when the field is not numeric",
+			field:         "brand",
+			expectedError: internal.ErrServiceInvalidStats,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Stats returns ErrServiceInvalidStats when the grouping is unknown
+			name:          "should return ErrServiceInvalidStats when the grouping is unknown",
+			field:         internal.NumericWeight,
+			groupBy:       "model",
+			expectedError: internal.ErrServiceInvalidStats,
+			isError:       true,
+		},
+		{
+			// This test evaluates that Stats returns ErrServiceNoVehicles when there are no vehicles
+			name:             "should return ErrServiceNoVehicles when there are no vehicles",
+			field:            internal.NumericWeight,
+			returnedVehicles: map[int]internal.Vehicle{},
+			expectedError:    internal.ErrServiceNoVehicles,
+			mockOnCalled:     true,
+			isError:          true,
+		},
+		{
+			// This test evaluates that Stats groups the values that match as the repository compares texts, named after the smallest one
+			name:    "should group the values that match as the repository compares texts",
+			match:   internal.TextMatchNormalized,
+			field:   internal.NumericMaxSpeed,
+			groupBy: internal.GroupByBrand,
+			returnedVehicles: map[int]internal.Vehicle{
+				1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "ford", MaxSpeed: 100}},
+				2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford ", MaxSpeed: 200}},
+				3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", MaxSpeed: 150}},
+			},
+			expectedStats: []internal.VehicleStats{
+				{Group: "Fiat", Count: 1, Min: 150, Max: 150, Mean: 150, Median: 150, P90: 150, P99: 150},
+				{Group: "Ford ", Count: 2, Min: 100, Max: 200, Mean: 150, Median: 150, StdDev: 50, P90: 190, P99: 199},
+			},
+			mockOnCalled: true,
+		},
+		{
+			// This test evaluates that Stats returns the error of the repository
+			name:             "should return the error of the repository",
+			field:            internal.NumericWeight,
+			returnedVehicles: map[int]internal.Vehicle(nil),
+			repositoryError:  errRepository,
+			expectedError:    errRepository,
+			mockOnCalled:     true,
+			isError:          true,
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		testCase.Arrange()
+		testCase.Act()
+		testCase.Assert(t)
+	}
+}
+
+// errRepository is the error returned by the mock repository in the error test cases
+var errRepository = errors.New("repository error")
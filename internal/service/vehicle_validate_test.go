@@ -0,0 +1,144 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ValidateTestCase is a struct that represents a test case for the domain rules checked by the write methods
+type ValidateTestCase struct {
+	name              string
+	write             func(sv *ServiceVehicleDefault) error
+	registered        map[int]internal.Vehicle
+	expectedFields    []internal.VehicleFieldError
+	expectedWriteCall string
+}
+
+// TestService_Validate is a function that tests the domain rules checked by Save, Update and Patch
+func TestService_Validate(t *testing.T) {
+	vv := internal.NewVehicleValidatorRules(
+		internal.RuleUniqueRegistration(),
+		internal.RuleYearRange(1886, 2030),
+		internal.RuleKnownValues([]string{"gasoline"}, []string{"manual"}),
+	)
+	valid := internal.VehicleAttributes{Brand: "Ford", Registration: "ABC-1234", FabricationYear: 2010, FuelType: "Gasoline", Transmission: "Manual"}
+	year := 1700
+	patched := valid
+	patched.FabricationYear = year
+
+	// Create the test cases
+	testCases := []ValidateTestCase{
+		{
+			// This test evaluates that Save writes a vehicle that breaks no rule
+			name: "Save/should save a vehicle that breaks no rule",
+			write: func(sv *ServiceVehicleDefault) error {
+				return sv.Save(&internal.Vehicle{VehicleAttributes: valid})
+			},
+			expectedWriteCall: "Save",
+		},
+		{
+			// This test evaluates that Save rejects a registration used by another vehicle
+			name: "Save/should reject a registration used by another vehicle",
+			write: func(sv *ServiceVehicleDefault) error {
+				return sv.Save(&internal.Vehicle{VehicleAttributes: valid})
+			},
+			registered: map[int]internal.Vehicle{7: {Id: 7, VehicleAttributes: valid}},
+			expectedFields: []internal.VehicleFieldError{
+				{Field: "registration", Rule: internal.ValidationRuleUnique, Message: `"ABC-1234" is already used by vehicle 7`},
+			},
+		},
+		{
+			// This test evaluates that Update does not take the vehicle for a duplicate of itself
+			name: "Update/should update a vehicle keeping its registration",
+			write: func(sv *ServiceVehicleDefault) error {
+				return sv.Update(&internal.Vehicle{Id: 7, VehicleAttributes: valid})
+			},
+			registered:        map[int]internal.Vehicle{7: {Id: 7, VehicleAttributes: valid}},
+			expectedWriteCall: "Update",
+		},
+		{
+			// This test evaluates that Update edits a vehicle loaded with a registration shared by others
+			name: "Update/should update a vehicle keeping a registration shared by other vehicles",
+			write: func(sv *ServiceVehicleDefault) error {
+				v := internal.Vehicle{Id: 7, VehicleAttributes: valid}
+				v.Color = "Red"
+				return sv.Update(&v)
+			},
+			registered:        map[int]internal.Vehicle{7: {Id: 7, VehicleAttributes: valid}, 8: {Id: 8, VehicleAttributes: valid}},
+			expectedWriteCall: "Update",
+		},
+		{
+			// This test evaluates that Update rejects a new registration used by another vehicle
+			name: "Update/should reject a new registration used by another vehicle",
+			write: func(sv *ServiceVehicleDefault) error {
+				return sv.Update(&internal.Vehicle{Id: 1, VehicleAttributes: valid})
+			},
+			registered: map[int]internal.Vehicle{7: {Id: 7, VehicleAttributes: valid}},
+			expectedFields: []internal.VehicleFieldError{
+				{Field: "registration", Rule: internal.ValidationRuleUnique, Message: `"ABC-1234" is already used by vehicle 7`},
+			},
+		},
+		{
+			// This test evaluates that Update returns every field that breaks a rule
+			name: "Update/should return every field that breaks a rule",
+			write: func(sv *ServiceVehicleDefault) error {
+				v := internal.Vehicle{Id: 1, VehicleAttributes: valid}
+				v.FabricationYear, v.FuelType = 1700, "steam"
+				return sv.Update(&v)
+			},
+			expectedFields: []internal.VehicleFieldError{
+				{Field: "year", Rule: internal.ValidationRuleRange, Message: "must be between 1886 and 2030, got 1700"},
+				{Field: "fuel_type", Rule: internal.ValidationRuleKnown, Message: `"steam" is not one of gasoline`},
+			},
+		},
+		{
+			// This test evaluates that Patch checks the vehicle once patched
+			name: "Patch/should reject a patch that breaks a rule",
+			write: func(sv *ServiceVehicleDefault) error {
+				_, err := sv.Patch(1, internal.VehiclePatch{FabricationYear: &year})
+				return err
+			},
+			expectedFields: []internal.VehicleFieldError{
+				{Field: "year", Rule: internal.ValidationRuleRange, Message: "must be between 1886 and 2030, got 1700"},
+			},
+		},
+	}
+
+	// Run the test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Arrange
+			rp := new(repository.MockRepository)
+			if testCase.registered != nil {
+				rp.On("FindByRegistration", mock.Anything).Return(testCase.registered, nil)
+			} else {
+				rp.On("FindByRegistration", mock.Anything).Return(map[int]internal.Vehicle(nil), internal.ErrRepositoryNotFound)
+			}
+			rp.On("FindByID", 1).Return(internal.Vehicle{Id: 1, VehicleAttributes: valid}, nil)
+			rp.On("Patch", 1, mock.Anything).Return(internal.Vehicle{Id: 1, VehicleAttributes: patched}, nil)
+			rp.On("Save", mock.Anything).Return(nil)
+			rp.On("Update", mock.Anything).Return(nil)
+			sv := NewServiceVehicleDefault(rp, vv, internal.TextMatchExact)
+
+			// Act
+			err := testCase.write(sv)
+
+			// Assert
+			if testCase.expectedFields != nil {
+				var verr *internal.VehicleValidationError
+				assert.ErrorIs(t, err, internal.ErrValidatorInvalidVehicle)
+				assert.ErrorAs(t, err, &verr)
+				assert.Equal(t, testCase.expectedFields, verr.Fields)
+				rp.AssertNotCalled(t, "Save", mock.Anything)
+				rp.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			rp.AssertCalled(t, testCase.expectedWriteCall, mock.Anything)
+		})
+	}
+}
@@ -44,3 +44,77 @@ type Vehicle struct {
 	// VehicleAttribue is the attributes of a vehicle
 	VehicleAttributes
 }
+
+// VehiclePatch is a struct that represents a partial update of a vehicle
+// - nil fields are left untouched
+type VehiclePatch struct {
+	// Brand is the brand of the vehicle
+	Brand *string
+	// Model is the model of the vehicle
+	Model *string
+	// Registration is the registration of the vehicle
+	Registration *string
+	// Color is the color of the vehicle
+	Color *string
+	// FabricationYear is the fabrication year of the vehicle
+	FabricationYear *int
+	// Capacity is the capacity of people of the vehicle
+	Capacity *int
+	// MaxSpeed is the maximum speed of the vehicle
+	MaxSpeed *float64
+	// FuelType is the fuel type of the vehicle
+	FuelType *string
+	// Transmission is the transmission of the vehicle
+	Transmission *string
+	// Weight is the weight of the vehicle
+	Weight *float64
+	// Height is the height of the vehicle
+	Height *float64
+	// Length is the length of the vehicle
+	Length *float64
+	// Width is the width of the vehicle
+	Width *float64
+}
+
+// Apply is a method that applies the patch over a vehicle
+func (p VehiclePatch) Apply(v *Vehicle) {
+	if p.Brand != nil {
+		v.Brand = *p.Brand
+	}
+	if p.Model != nil {
+		v.Model = *p.Model
+	}
+	if p.Registration != nil {
+		v.Registration = *p.Registration
+	}
+	if p.Color != nil {
+		v.Color = *p.Color
+	}
+	if p.FabricationYear != nil {
+		v.FabricationYear = *p.FabricationYear
+	}
+	if p.Capacity != nil {
+		v.Capacity = *p.Capacity
+	}
+	if p.MaxSpeed != nil {
+		v.MaxSpeed = *p.MaxSpeed
+	}
+	if p.FuelType != nil {
+		v.FuelType = *p.FuelType
+	}
+	if p.Transmission != nil {
+		v.Transmission = *p.Transmission
+	}
+	if p.Weight != nil {
+		v.Weight = *p.Weight
+	}
+	if p.Height != nil {
+		v.Height = *p.Height
+	}
+	if p.Length != nil {
+		v.Length = *p.Length
+	}
+	if p.Width != nil {
+		v.Width = *p.Width
+	}
+}
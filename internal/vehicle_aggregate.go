@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrServiceInvalidAggregate is an error that represents an invalid aggregation query
+	ErrServiceInvalidAggregate = errors.New("service: invalid aggregate")
+)
+
+// AggregateFunc is a function that reduces a field of a group of vehicles to a single value
+type AggregateFunc string
+
+const (
+	// AggregateCount is the number of vehicles, it takes no field
+	AggregateCount AggregateFunc = "count"
+	// AggregateSum is the sum of the field
+	AggregateSum AggregateFunc = "sum"
+	// AggregateAvg is the mean of the field
+	AggregateAvg AggregateFunc = "avg"
+	// AggregateMin is the minimum of the field
+	AggregateMin AggregateFunc = "min"
+	// AggregateMax is the maximum of the field
+	AggregateMax AggregateFunc = "max"
+)
+
+// VehicleMetric is a struct that represents an aggregate function applied to a numeric field
+// - written as count, or as the function followed by the field in parentheses, e.g. avg(max_speed)
+type VehicleMetric struct {
+	// Func is the aggregate function
+	Func AggregateFunc
+	// Field is the field it is applied to, empty for count
+	Field VehicleNumericField
+}
+
+// ParseVehicleMetric is a function that decodes a metric from its written form
+// - ok is false if the function or the field is unknown
+func ParseVehicleMetric(s string) (m VehicleMetric, ok bool) {
+	name, rest, found := strings.Cut(s, "(")
+	if found {
+		field, closed := strings.CutSuffix(rest, ")")
+		if !closed {
+			return
+		}
+		m = VehicleMetric{Func: AggregateFunc(name), Field: VehicleNumericField(field)}
+	} else {
+		m = VehicleMetric{Func: AggregateFunc(name)}
+	}
+	ok = m.Valid()
+	return
+}
+
+// String is a method that returns the written form of the metric
+func (m VehicleMetric) String() string {
+	if m.Func == AggregateCount {
+		return string(m.Func)
+	}
+	return string(m.Func) + "(" + string(m.Field) + ")"
+}
+
+// Valid is a method that returns true if the function is known and takes the field
+func (m VehicleMetric) Valid() bool {
+	switch m.Func {
+	case AggregateCount:
+		return m.Field == ""
+	case AggregateSum, AggregateAvg, AggregateMin, AggregateMax:
+		return m.Field.Valid()
+	}
+	return false
+}
+
+// CompareOp is an operator that compares the value of a metric against a threshold
+type CompareOp string
+
+const (
+	// CompareGt is greater than
+	CompareGt CompareOp = ">"
+	// CompareGte is greater than or equal
+	CompareGte CompareOp = ">="
+	// CompareLt is less than
+	CompareLt CompareOp = "<"
+	// CompareLte is less than or equal
+	CompareLte CompareOp = "<="
+	// CompareEq is equal
+	CompareEq CompareOp = "="
+	// CompareNe is not equal
+	CompareNe CompareOp = "!="
+)
+
+// Compare is a method that returns true if a holds against b
+// - ok is false if the operator is unknown
+func (o CompareOp) Compare(a float64, b float64) (holds bool, ok bool) {
+	ok = true
+	switch o {
+	case CompareGt:
+		holds = a > b
+	case CompareGte:
+		holds = a >= b
+	case CompareLt:
+		holds = a < b
+	case CompareLte:
+		holds = a <= b
+	case CompareEq:
+		holds = a == b
+	case CompareNe:
+		holds = a != b
+	default:
+		ok = false
+	}
+	return
+}
+
+// VehicleHaving is a struct that represents a threshold a group must meet to be part of an aggregation
+type VehicleHaving struct {
+	// Metric is the metric compared, it does not need to be one of the selected metrics
+	Metric VehicleMetric
+	// Op is the comparison operator
+	Op CompareOp
+	// Value is the threshold
+	Value float64
+}
+
+// AggregateSortKey is a struct that represents a key an aggregation is sorted by
+type AggregateSortKey struct {
+	// Key is either one of the grouping fields or the written form of one of the selected metrics
+	Key string
+	// Desc is true if the key is sorted in descending order
+	Desc bool
+}
+
+// AggregateQuery is a struct that represents an aggregation of the vehicles
+type AggregateQuery struct {
+	// GroupBy are the fields the vehicles are grouped by, empty means a single group with every vehicle
+	GroupBy []VehicleGroupField
+	// Metrics are the metrics computed for each group, empty means count
+	Metrics []VehicleMetric
+	// Having are the thresholds every group must meet, combined with AND
+	Having []VehicleHaving
+	// Sort are the keys the groups are sorted by, ties are sorted by the grouping fields in ascending order
+	Sort []AggregateSortKey
+}
+
+// VehicleAggregate is a struct that represents the metrics of a group of vehicles
+type VehicleAggregate struct {
+	// Group are the values of the grouping fields, in the order of the query
+	Group []string
+	// Metrics are the values of the metrics, in the order of the query
+	Metrics []float64
+}
+
+// AggregatorVehicle is an interface that represents an aggregator of the vehicles
+type AggregatorVehicle interface {
+	// Aggregate is a method that groups the vehicles and computes the metrics of each group
+	// - the vehicles are read in a single pass, an invalid query returns ErrServiceInvalidAggregate
+	Aggregate(q AggregateQuery) (a []VehicleAggregate, err error)
+}
@@ -0,0 +1,73 @@
+package internal
+
+import "math"
+
+// VehicleAverage is a struct that represents the average of a field over a sample of vehicles
+type VehicleAverage struct {
+	// Value is the exact average, not rounded
+	Value float64
+	// Count is the number of vehicles the average was computed over
+	Count int
+}
+
+// RoundingMode is the way a value is rounded to a number of decimal places
+type RoundingMode string
+
+const (
+	// RoundingNone keeps the value as is
+	RoundingNone RoundingMode = "none"
+	// RoundingHalfUp rounds to the nearest value, halves away from zero
+	RoundingHalfUp RoundingMode = "half_up"
+	// RoundingHalfEven rounds to the nearest value, halves to the even neighbour
+	RoundingHalfEven RoundingMode = "half_even"
+	// RoundingDown rounds towards zero
+	RoundingDown RoundingMode = "down"
+	// RoundingUp rounds away from zero
+	RoundingUp RoundingMode = "up"
+)
+
+// Valid is a method that returns true if the rounding mode is known
+func (m RoundingMode) Valid() bool {
+	switch m {
+	case RoundingNone, RoundingHalfUp, RoundingHalfEven, RoundingDown, RoundingUp:
+		return true
+	}
+	return false
+}
+
+// Rounding is a struct that represents how a value is rounded
+// - the zero value keeps the value as is
+type Rounding struct {
+	// Mode is the rounding mode, empty means none
+	Mode RoundingMode
+	// Places is the number of decimal places kept
+	Places int
+}
+
+// Round is a method that returns the value rounded to the configured decimal places
+func (r Rounding) Round(x float64) (y float64) {
+	if r.Mode == "" || r.Mode == RoundingNone || math.IsNaN(x) || math.IsInf(x, 0) {
+		y = x
+		return
+	}
+
+	scale := math.Pow(10, float64(r.Places))
+	switch r.Mode {
+	case RoundingHalfUp:
+		y = math.Round(x*scale) / scale
+	case RoundingHalfEven:
+		y = math.RoundToEven(x*scale) / scale
+	case RoundingDown:
+		y = math.Trunc(x*scale) / scale
+	case RoundingUp:
+		s := x * scale
+		if s < 0 {
+			y = math.Floor(s) / scale
+		} else {
+			y = math.Ceil(s) / scale
+		}
+	default:
+		y = x
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package internal
+
+// VehicleFilter is a struct that represents the criteria a vehicle must match
+// - method: dynamic. nil criteria are not applied, set criteria are combined with AND
+// - strings match as the TextMatch of the repository says, ranges include both bounds
+type VehicleFilter struct {
+	// Brand is the brand of the vehicle
+	Brand *string
+	// Model is the model of the vehicle
+	Model *string
+	// Color is the color of the vehicle
+	Color *string
+	// FuelType is the fuel type of the vehicle
+	FuelType *string
+	// Transmission is the transmission of the vehicle
+	Transmission *string
+	// YearMin is the minimum fabrication year
+	YearMin *int
+	// YearMax is the maximum fabrication year
+	YearMax *int
+	// CapacityMin is the minimum capacity of people
+	CapacityMin *int
+	// CapacityMax is the maximum capacity of people
+	CapacityMax *int
+	// SpeedMin is the minimum maximum speed
+	SpeedMin *float64
+	// SpeedMax is the maximum maximum speed
+	SpeedMax *float64
+	// WeightMin is the minimum weight
+	WeightMin *float64
+	// WeightMax is the maximum weight
+	WeightMax *float64
+}
+
+// Match is a method that returns true if the vehicle matches every criteria of the filter
+// - text criteria are compared as m says
+func (f VehicleFilter) Match(v Vehicle, m TextMatch) bool {
+	text := func(criteria *string, value string) bool {
+		return criteria == nil || m.Key(value) == m.Key(*criteria)
+	}
+
+	switch {
+	case !text(f.Brand, v.Brand),
+		!text(f.Model, v.Model),
+		!text(f.Color, v.Color),
+		!text(f.FuelType, v.FuelType),
+		!text(f.Transmission, v.Transmission),
+		f.YearMin != nil && v.FabricationYear < *f.YearMin,
+		f.YearMax != nil && v.FabricationYear > *f.YearMax,
+		f.CapacityMin != nil && v.Capacity < *f.CapacityMin,
+		f.CapacityMax != nil && v.Capacity > *f.CapacityMax,
+		f.SpeedMin != nil && v.MaxSpeed < *f.SpeedMin,
+		f.SpeedMax != nil && v.MaxSpeed > *f.SpeedMax,
+		f.WeightMin != nil && v.Weight < *f.WeightMin,
+		f.WeightMax != nil && v.Weight > *f.WeightMax:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNotReady is an error that represents a dependency that can not serve requests yet, or any longer
+	ErrNotReady = errors.New("not ready")
+)
+
+// ReadinessChecker is an interface that represents a dependency that can tell whether it is able to serve requests
+// - loaders and repositories implement it when they have something worth checking, the application plugs them in
+type ReadinessChecker interface {
+	// Ready is a method that returns nil if the dependency is ready, or an error saying why it is not
+	// - implementations should return promptly once ctx is done
+	Ready(ctx context.Context) (err error)
+}
+
+// ReadinessFunc is a function that implements the ReadinessChecker interface
+type ReadinessFunc func(ctx context.Context) (err error)
+
+// Ready is a method that calls the function
+func (f ReadinessFunc) Ready(ctx context.Context) (err error) {
+	return f(ctx)
+}
+
+// ReadinessCheck is a struct that represents a readiness checker along with the name it is reported under
+type ReadinessCheck struct {
+	// Name is the name of the check, e.g. loader or repository
+	Name string
+	// Checker is the dependency checked
+	Checker ReadinessChecker
+}
+
+// DatasetInfo is a struct that represents a summary of the vehicles being served
+type DatasetInfo struct {
+	// Checksum is the hex encoded SHA-256 of the vehicles ordered by id, it changes with any reload or write
+	Checksum string
+	// Count is the number of vehicles
+	Count int
+}
+
+// DatasetVehicle is an interface that represents a describer of the vehicles dataset
+type DatasetVehicle interface {
+	// Info is a method that returns the summary of the vehicles currently served
+	Info() (d DatasetInfo, err error)
+}
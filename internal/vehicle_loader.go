@@ -1,7 +1,115 @@
 package internal
 
+import "errors"
+
+var (
+	// ErrLoaderInvalidVehicles is an error that represents a load that failed because of invalid vehicles
+	ErrLoaderInvalidVehicles = errors.New("loader: invalid vehicles")
+	// ErrLoaderUnknownPolicy is an error that represents an unknown load policy
+	ErrLoaderUnknownPolicy = errors.New("loader: unknown policy")
+)
+
 // LoaderVehicle is an interface that represents the loader for vehicles
 type LoaderVehicle interface {
 	// Load is a method that loads the vehicles
 	Load() (v map[int]Vehicle, err error)
+}
+
+// LoadPolicy is the policy applied by a loader to the vehicles that do not pass validation
+type LoadPolicy string
+
+const (
+	// LoadPolicyFail makes the whole load fail if any vehicle has issues
+	LoadPolicyFail LoadPolicy = "fail"
+	// LoadPolicySkip leaves out the vehicles with issues
+	LoadPolicySkip LoadPolicy = "skip"
+	// LoadPolicyWarn loads every vehicle, issues are only reported
+	LoadPolicyWarn LoadPolicy = "warn"
+)
+
+// LoadIssueKind is the kind of a problem found in a vehicle while loading
+type LoadIssueKind string
+
+const (
+	// LoadIssueDuplicate is a vehicle whose id was already loaded
+	LoadIssueDuplicate LoadIssueKind = "duplicate"
+	// LoadIssueMissingField is a required field that is not present
+	LoadIssueMissingField LoadIssueKind = "missing_field"
+	// LoadIssueOutOfRange is a field with a value outside of its valid range
+	LoadIssueOutOfRange LoadIssueKind = "out_of_range"
+	// LoadIssueUnknownField is a field that does not belong to a vehicle
+	LoadIssueUnknownField LoadIssueKind = "unknown_field"
+	// LoadIssueInvalidValue is a field with a value that breaks a rule of the vehicle validator
+	LoadIssueInvalidValue LoadIssueKind = "invalid_value"
+	// LoadIssueInvalid is a vehicle that could not be decoded
+	LoadIssueInvalid LoadIssueKind = "invalid"
+)
+
+// LoadIssue is a struct that represents a problem found in a vehicle while loading
+type LoadIssue struct {
+	// Index is the position of the vehicle in the source, starting at 0
+	Index int
+	// Id is the id of the vehicle, zero if it could not be read
+	Id int
+	// Kind is the kind of problem
+	Kind LoadIssueKind
+	// Field is the name of the field with the problem, empty if it concerns the whole vehicle
+	Field string
+	// Message is a human readable description of the problem
+	Message string
+}
+
+// LoadReportMaxIssues is the number of issues a load report keeps, the ones found after them are only counted
+// - it bounds the memory of the report, and the size of its response, whatever the size of the source
+const LoadReportMaxIssues = 1000
+
+// LoadReport is a struct that represents the result of validating the vehicles of a load
+type LoadReport struct {
+	// Policy is the policy applied to the vehicles with issues
+	Policy LoadPolicy
+	// Total is the number of vehicles read from the source
+	Total int
+	// Loaded is the number of vehicles returned by the load
+	Loaded int
+	// Skipped is the number of vehicles left out because of their issues
+	Skipped int
+	// Issues are the first LoadReportMaxIssues problems found, in source order
+	Issues []LoadIssue
+	// Dropped is the number of problems found after the first LoadReportMaxIssues, counted but not kept
+	Dropped int
+	// Counts is the number of problems found by kind, the dropped ones included
+	Counts map[LoadIssueKind]int
+}
+
+// AddIssues is a method that counts the issues and keeps them while the report has room for them
+func (r *LoadReport) AddIssues(issues ...LoadIssue) {
+	for _, issue := range issues {
+		if r.Counts == nil {
+			r.Counts = make(map[LoadIssueKind]int)
+		}
+		r.Counts[issue.Kind]++
+		if len(r.Issues) >= LoadReportMaxIssues {
+			r.Dropped++
+			continue
+		}
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// Count is a method that returns the number of issues of a kind, the dropped ones included
+func (r LoadReport) Count(kind LoadIssueKind) (n int) {
+	n = r.Counts[kind]
+	return
+}
+
+// IssueCount is a method that returns the number of issues found, the dropped ones included
+func (r LoadReport) IssueCount() (n int) {
+	n = len(r.Issues) + r.Dropped
+	return
+}
+
+// LoaderReporter is an interface that represents a loader that reports on the validation of its last load
+type LoaderReporter interface {
+	// Report is a method that returns the report of the last load
+	Report() (r LoadReport)
 }
\ No newline at end of file
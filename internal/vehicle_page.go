@@ -0,0 +1,178 @@
+package internal
+
+import "strings"
+
+// VehicleSortField is a field vehicles can be sorted by, named like the fields of the vehicles file
+type VehicleSortField string
+
+const (
+	// SortById sorts by the id
+	SortById VehicleSortField = "id"
+	// SortByBrand sorts by the brand
+	SortByBrand VehicleSortField = "brand"
+	// SortByModel sorts by the model
+	SortByModel VehicleSortField = "model"
+	// SortByRegistration sorts by the registration
+	SortByRegistration VehicleSortField = "registration"
+	// SortByColor sorts by the color
+	SortByColor VehicleSortField = "color"
+	// SortByYear sorts by the fabrication year
+	SortByYear VehicleSortField = "year"
+	// SortByCapacity sorts by the capacity of people
+	SortByCapacity VehicleSortField = "passengers"
+	// SortByMaxSpeed sorts by the maximum speed
+	SortByMaxSpeed VehicleSortField = "max_speed"
+	// SortByFuelType sorts by the fuel type
+	SortByFuelType VehicleSortField = "fuel_type"
+	// SortByTransmission sorts by the transmission
+	SortByTransmission VehicleSortField = "transmission"
+	// SortByWeight sorts by the weight
+	SortByWeight VehicleSortField = "weight"
+	// SortByHeight sorts by the height
+	SortByHeight VehicleSortField = "height"
+	// SortByLength sorts by the length
+	SortByLength VehicleSortField = "length"
+	// SortByWidth sorts by the width
+	SortByWidth VehicleSortField = "width"
+)
+
+// Valid is a method that returns true if vehicles can be sorted by the field
+func (f VehicleSortField) Valid() bool {
+	switch f {
+	case SortById, SortByBrand, SortByModel, SortByRegistration, SortByColor, SortByYear, SortByCapacity,
+		SortByMaxSpeed, SortByFuelType, SortByTransmission, SortByWeight, SortByHeight, SortByLength, SortByWidth:
+		return true
+	}
+	return false
+}
+
+// VehicleSortKey is a struct that represents a sort key
+type VehicleSortKey struct {
+	// Field is the field to sort by
+	Field VehicleSortField
+	// Desc is true to sort in descending order
+	Desc bool
+}
+
+// PageQuery is a struct that represents the order and the window of a list of vehicles
+type PageQuery struct {
+	// Sort are the sort keys, applied in order
+	// - ties are always broken by ascending id, so the order is stable between pages
+	Sort []VehicleSortKey
+	// After is the last vehicle of the previous page, nil to start from the first one
+	// - the vehicles sorted at or before it are skipped, only its sort fields and id are compared
+	// - it keeps pages stable while vehicles are added or removed, unlike Offset
+	After *Vehicle
+	// Offset is the number of vehicles skipped, after seeking past After
+	Offset int
+	// Limit is the maximum number of vehicles returned
+	// - zero means no limit
+	Limit int
+}
+
+// VehiclePage is a struct that represents an ordered window of a list of vehicles
+type VehiclePage struct {
+	// Vehicles are the vehicles of the window, in order
+	Vehicles []Vehicle
+	// Total is the number of vehicles of the whole list
+	Total int
+	// More is true if vehicles follow the window
+	More bool
+}
+
+// CompareVehicles is a function that compares two vehicles by the sort keys, then by id
+// - returns a negative number when a goes first, a positive one when b goes first
+func CompareVehicles(a Vehicle, b Vehicle, sort []VehicleSortKey) int {
+	for _, key := range sort {
+		c := compareField(a, b, key.Field)
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareOrdered(a.Id, b.Id)
+}
+
+// compareField is a function that compares a field of two vehicles
+func compareField(a Vehicle, b Vehicle, field VehicleSortField) int {
+	switch field {
+	case SortById:
+		return compareOrdered(a.Id, b.Id)
+	case SortByBrand:
+		return strings.Compare(a.Brand, b.Brand)
+	case SortByModel:
+		return strings.Compare(a.Model, b.Model)
+	case SortByRegistration:
+		return strings.Compare(a.Registration, b.Registration)
+	case SortByColor:
+		return strings.Compare(a.Color, b.Color)
+	case SortByYear:
+		return compareOrdered(a.FabricationYear, b.FabricationYear)
+	case SortByCapacity:
+		return compareOrdered(a.Capacity, b.Capacity)
+	case SortByMaxSpeed:
+		return compareOrdered(a.MaxSpeed, b.MaxSpeed)
+	case SortByFuelType:
+		return strings.Compare(a.FuelType, b.FuelType)
+	case SortByTransmission:
+		return strings.Compare(a.Transmission, b.Transmission)
+	case SortByWeight:
+		return compareOrdered(a.Weight, b.Weight)
+	case SortByHeight:
+		return compareOrdered(a.Height, b.Height)
+	case SortByLength:
+		return compareOrdered(a.Length, b.Length)
+	case SortByWidth:
+		return compareOrdered(a.Width, b.Width)
+	}
+	return 0
+}
+
+// Ref is a method that returns a pointer to the field of v, nil if vehicles can not be sorted by the field
+// - the pointer is a *int, a *float64 or a *string
+func (f VehicleSortField) Ref(v *Vehicle) any {
+	switch f {
+	case SortById:
+		return &v.Id
+	case SortByBrand:
+		return &v.Brand
+	case SortByModel:
+		return &v.Model
+	case SortByRegistration:
+		return &v.Registration
+	case SortByColor:
+		return &v.Color
+	case SortByYear:
+		return &v.FabricationYear
+	case SortByCapacity:
+		return &v.Capacity
+	case SortByMaxSpeed:
+		return &v.MaxSpeed
+	case SortByFuelType:
+		return &v.FuelType
+	case SortByTransmission:
+		return &v.Transmission
+	case SortByWeight:
+		return &v.Weight
+	case SortByHeight:
+		return &v.Height
+	case SortByLength:
+		return &v.Length
+	case SortByWidth:
+		return &v.Width
+	}
+	return nil
+}
+
+// compareOrdered is a function that compares two numbers
+func compareOrdered[T int | float64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
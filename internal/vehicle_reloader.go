@@ -0,0 +1,36 @@
+package internal
+
+import "errors"
+
+var (
+	// ErrRepositoryWritten is an error that represents vehicles written since they were last replaced, replacing them would discard the writes
+	ErrRepositoryWritten = errors.New("repository: vehicles written since the last replace")
+)
+
+// ReloadReport is a struct that represents the changes applied by a reload of the vehicles
+type ReloadReport struct {
+	// Added is the number of vehicles that were not present before the reload
+	Added int
+	// Changed is the number of vehicles whose attributes changed with the reload
+	Changed int
+	// Removed is the number of vehicles that are no longer present after the reload
+	Removed int
+}
+
+// RepositoryReplaceVehicle is an interface that represents a repository whose vehicles can be swapped at once
+type RepositoryReplaceVehicle interface {
+	// Replace is a method that atomically replaces all the vehicles and reports the differences
+	Replace(v map[int]Vehicle) (r ReloadReport, err error)
+
+	// ReplaceUnwritten is a method that replaces all the vehicles like Replace, only if none was written since the last replace
+	// - returns ErrRepositoryWritten without replacing anything otherwise, the check and the replace are a single write
+	ReplaceUnwritten(v map[int]Vehicle) (r ReloadReport, err error)
+}
+
+// ReloaderVehicle is an interface that represents a reloader of the vehicles dataset
+type ReloaderVehicle interface {
+	// Reload is a method that loads the vehicles again and replaces the current ones
+	// - on error the current vehicles are left untouched
+	// - unless force, returns ErrRepositoryWritten if the vehicles were written since the last load, the writes would be lost
+	Reload(force bool) (r ReloadReport, err error)
+}
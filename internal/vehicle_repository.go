@@ -5,6 +5,8 @@ import "errors"
 var (
 	// ErrRepositoryInvalidFind is an error that represents an invalid find
 	ErrRepositoryInvalidFind = errors.New("repository: invalid find")
+	// ErrRepositoryNotFound is an error that represents a vehicle not found
+	ErrRepositoryNotFound = errors.New("repository: vehicle not found")
 )
 
 // RepositoryReadVehicle is an interface that represents a vehicle repository
@@ -13,6 +15,15 @@ type RepositoryReadVehicle interface {
 	// FindAll is a method that returns a map of all vehicles
 	FindAll() (v map[int]Vehicle, err error)
 
+	// FindByID is a method that returns the vehicle with the id
+	// - returns ErrRepositoryNotFound if there is no such vehicle
+	FindByID(id int) (v Vehicle, err error)
+
+	// FindByRegistration is a method that returns a map of vehicles that match the registration
+	// - registrations are not unique, so several vehicles can match
+	// - returns ErrRepositoryNotFound if no vehicle matches
+	FindByRegistration(registration string) (v map[int]Vehicle, err error)
+
 	// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 	FindByColorAndYear(color string, fabricationYear int) (v map[int]Vehicle, err error)
 
@@ -24,4 +35,43 @@ type RepositoryReadVehicle interface {
 
 	// FindByWeightRange is a method that returns a map of vehicles that match the weight range
 	FindByWeightRange(fromWeight float64, toWeight float64) (v map[int]Vehicle, err error)
-}
\ No newline at end of file
+
+	// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+	FindByFilter(filter VehicleFilter) (v map[int]Vehicle, err error)
+
+	// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+	FindPage(filter VehicleFilter, page PageQuery) (p VehiclePage, err error)
+}
+
+// VehicleWriteCheck is a function that checks a vehicle about to be written, run by the repository inside the write
+// - registry sees the vehicles as they are before the write, the vehicle checked is not written yet
+type VehicleWriteCheck func(v Vehicle, registry VehicleRegistry) (err error)
+
+// RepositoryWriteVehicle is an interface that represents a vehicle repository with write operations
+type RepositoryWriteVehicle interface {
+	// Save is a method that saves a new vehicle and sets its id
+	// - the vehicle is checked, without an id, and stored without any other write in between
+	// - check can be nil, an error it returns aborts the write and is returned as is
+	Save(v *Vehicle, check VehicleWriteCheck) (err error)
+
+	// Update is a method that replaces an existing vehicle
+	// - the vehicle is checked and stored without any other write in between
+	// - check can be nil, an error it returns aborts the write and is returned as is
+	// - returns ErrRepositoryNotFound if there is no such vehicle, before running the check
+	Update(v *Vehicle, check VehicleWriteCheck) (err error)
+
+	// Patch is a method that applies a partial update to an existing vehicle as a single write and returns the result
+	// - the vehicle is read, patched, checked and stored without any other write in between, so concurrent patches are not lost
+	// - check can be nil, an error it returns aborts the write and is returned as is
+	// - returns ErrRepositoryNotFound if there is no such vehicle
+	Patch(id int, patch VehiclePatch, check VehicleWriteCheck) (v Vehicle, err error)
+
+	// Delete is a method that deletes a vehicle by its id
+	Delete(id int) (err error)
+}
+
+// RepositoryVehicle is an interface that represents a vehicle repository with read and write operations
+type RepositoryVehicle interface {
+	RepositoryReadVehicle
+	RepositoryWriteVehicle
+}
@@ -0,0 +1,32 @@
+package internal
+
+// VehicleSearchHit is a struct that represents a vehicle matched by a text search
+type VehicleSearchHit struct {
+	// Vehicle is the vehicle matched
+	Vehicle Vehicle
+	// Score is the relevance of the vehicle, the higher the better
+	Score float64
+}
+
+// VehicleSearchResult is a struct that represents the result of a text search
+type VehicleSearchResult struct {
+	// Hits are the best vehicles matched, sorted by score in descending order then by id
+	Hits []VehicleSearchHit
+	// Total is the number of vehicles matched, before applying the limit
+	Total int
+}
+
+// RepositorySearchVehicle is an interface that represents a repository whose vehicles can be searched by text
+type RepositorySearchVehicle interface {
+	// Search is a method that returns the vehicles that match every word of the text
+	// - words are case and diacritic insensitive, and match by prefix or with a few typos
+	// - limit zero returns every vehicle matched
+	Search(text string, limit int) (r VehicleSearchResult, err error)
+}
+
+// SearcherVehicle is an interface that represents a text searcher of the vehicles
+type SearcherVehicle interface {
+	// Search is a method that returns the vehicles that best match the text
+	// - an empty text or a negative limit returns ErrServiceInvalidSearch
+	Search(text string, limit int) (r VehicleSearchResult, err error)
+}
@@ -1,6 +1,9 @@
 package internal
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrServiceInvalidFind is an error that represents an invalid find
@@ -9,34 +12,118 @@ var (
 	ErrServiceInvalidSearch = errors.New("service: invalid search")
 	// ErrServiceNoVehicles is an error that represents no vehicles
 	ErrServiceNoVehicles = errors.New("service: no vehicles")
+	// ErrServiceInvalidStats is an error that represents statistics over an unknown field or grouping
+	ErrServiceInvalidStats = errors.New("service: invalid stats")
 )
 
+// RangeError is an error that represents a range of a query that can not match any vehicle
+// - it wraps ErrServiceInvalidFind or ErrServiceInvalidSearch
+// - its message names the offending parameters, a caller exposing the range under other names can rename them
+type RangeError struct {
+	// Err is the sentinel the error wraps
+	Err error
+	// Param is the name of the offending parameter, the minimum of an inverted range
+	Param string
+	// Value is the value of the offending parameter
+	Value any
+	// Reason is why the value is rejected, empty for an inverted range
+	Reason string
+	// MaxParam is the name of the maximum of an inverted range, empty otherwise
+	MaxParam string
+	// MaxValue is the value of the maximum of an inverted range
+	MaxValue any
+}
+
+// Error is a method that returns the error message
+func (e *RangeError) Error() string {
+	if e.MaxParam != "" {
+		return fmt.Sprintf("%s: %s %v is greater than %s %v", e.Err.Error(), e.Param, e.Value, e.MaxParam, e.MaxValue)
+	}
+	return fmt.Sprintf("%s: %s %s, got %v", e.Err.Error(), e.Param, e.Reason, e.Value)
+}
+
+// Unwrap is a method that returns the sentinel the error wraps
+func (e *RangeError) Unwrap() error {
+	return e.Err
+}
+
+// Rename is a method that returns a copy of the error with its parameters named as names says
+// - a parameter missing from names keeps its name
+func (e *RangeError) Rename(names map[string]string) *RangeError {
+	r := *e
+	if name, ok := names[r.Param]; ok {
+		r.Param = name
+	}
+	if name, ok := names[r.MaxParam]; ok {
+		r.MaxParam = name
+	}
+	return &r
+}
+
 // SearchQuery is a struct that represents a search query
 type SearchQuery struct {
-	// FromWeight is the minimum weight
-	FromWeight float64
-	// ToWeight is the maximum weight
-	ToWeight float64
+	// FromWeight is the minimum weight, nil when the range is open below
+	FromWeight *float64
+	// ToWeight is the maximum weight, nil when the range is open above
+	ToWeight *float64
 }
 
 // ServiceVehicle is an interface that represents a vehicle service
 type ServiceVehicle interface {
+	// FindByID is a method that returns the vehicle with the id
+	// - returns ErrRepositoryNotFound if there is no such vehicle
+	FindByID(id int) (v Vehicle, err error)
+
+	// FindByRegistration is a method that returns a map of vehicles that match the registration
+	// - registrations are not unique, so several vehicles can match
+	// - returns ErrRepositoryNotFound if no vehicle matches
+	FindByRegistration(registration string) (v map[int]Vehicle, err error)
+
 	// FindByColorAndYear is a method that returns a map of vehicles that match the color and fabrication year
 	FindByColorAndYear(color string, fabricationYear int) (v map[int]Vehicle, err error)
 
 	// FindByBrandAndYearRange is a method that returns a map of vehicles that match the brand and a range of fabrication years
+	// - returns ErrServiceInvalidFind naming the parameter if startYear is greater than endYear or either is negative
 	FindByBrandAndYearRange(brand string, startYear int, endYear int) (v map[int]Vehicle, err error)
 
 	// AverageMaxSpeedByBrand is a method that returns the average speed of the vehicles by brand
 	AverageMaxSpeedByBrand(brand string) (a float64, err error)
 
 	// AverageCapacityByBrand is a method that returns the average capacity of the vehicles by brand
-	AverageCapacityByBrand(brand string) (a int, err error)
+	// - the average is exact, along with the number of vehicles it was computed over
+	AverageCapacityByBrand(brand string) (a VehicleAverage, err error)
+
+	// Stats is a method that returns the statistics of a numeric field, for each group of vehicles sorted by group
+	// - an empty groupBy computes a single group over the whole fleet
+	Stats(field VehicleNumericField, groupBy VehicleGroupField) (s []VehicleStats, err error)
 
 	// SearchByWeightRange
 	// - method: hybrid. usage of static procedure and static optional (not dynamic types such as maps or slices)
 	// - query:
 	// 	 !ok -> will return all vehicles
-	// 	 ok  -> will return filtered vehicles
+	// 	 ok  -> will return filtered vehicles, the range is half-open when only one bound is set
+	// - returns ErrServiceInvalidSearch naming the parameter if the range is inverted, negative, NaN or infinite
 	SearchByWeightRange(query SearchQuery, ok bool) (v map[int]Vehicle, err error)
-}
\ No newline at end of file
+
+	// FindByFilter is a method that returns a map of vehicles that match every criteria of the filter
+	// - method: dynamic. generalizes SearchByWeightRange to any combination of criteria
+	// - a range that can not match any vehicle (inverted, negative, NaN or infinite) returns an error naming its parameter:
+	//   ErrServiceInvalidSearch for the weight, ErrServiceInvalidFind for any other
+	FindByFilter(filter VehicleFilter) (v map[int]Vehicle, err error)
+
+	// FindPage is a method that returns the sorted window of the vehicles that match every criteria of the filter
+	// - the ranges of the filter are checked as FindByFilter does
+	FindPage(filter VehicleFilter, page PageQuery) (p VehiclePage, err error)
+
+	// Save is a method that registers a new vehicle
+	Save(v *Vehicle) (err error)
+
+	// Update is a method that replaces an existing vehicle
+	Update(v *Vehicle) (err error)
+
+	// Patch is a method that partially updates an existing vehicle and returns the result
+	Patch(id int, patch VehiclePatch) (v Vehicle, err error)
+
+	// Delete is a method that deletes a vehicle by its id
+	Delete(id int) (err error)
+}
@@ -0,0 +1,121 @@
+package internal
+
+import "strconv"
+
+// VehicleNumericField is a numeric field of a vehicle statistics can be computed over, named like the fields of the vehicles file
+type VehicleNumericField string
+
+const (
+	// NumericMaxSpeed is the maximum speed
+	NumericMaxSpeed VehicleNumericField = "max_speed"
+	// NumericWeight is the weight
+	NumericWeight VehicleNumericField = "weight"
+	// NumericCapacity is the capacity of people
+	NumericCapacity VehicleNumericField = "passengers"
+	// NumericCapacityAlias is the capacity of people, named like the field of the vehicle
+	NumericCapacityAlias VehicleNumericField = "capacity"
+	// NumericHeight is the height
+	NumericHeight VehicleNumericField = "height"
+	// NumericLength is the length
+	NumericLength VehicleNumericField = "length"
+	// NumericWidth is the width
+	NumericWidth VehicleNumericField = "width"
+	// NumericYear is the fabrication year
+	NumericYear VehicleNumericField = "year"
+)
+
+// Value is a method that returns the value of the field of a vehicle
+// - ok is false if the field is unknown
+func (f VehicleNumericField) Value(v Vehicle) (n float64, ok bool) {
+	ok = true
+	switch f {
+	case NumericMaxSpeed:
+		n = v.MaxSpeed
+	case NumericWeight:
+		n = v.Weight
+	case NumericCapacity, NumericCapacityAlias:
+		n = float64(v.Capacity)
+	case NumericHeight:
+		n = v.Height
+	case NumericLength:
+		n = v.Length
+	case NumericWidth:
+		n = v.Width
+	case NumericYear:
+		n = float64(v.FabricationYear)
+	default:
+		ok = false
+	}
+	return
+}
+
+// Valid is a method that returns true if statistics can be computed over the field
+func (f VehicleNumericField) Valid() bool {
+	_, ok := f.Value(Vehicle{})
+	return ok
+}
+
+// VehicleGroupField is a categorical field of a vehicle vehicles can be grouped by, named like the fields of the vehicles file
+type VehicleGroupField string
+
+const (
+	// GroupByBrand groups by the brand
+	GroupByBrand VehicleGroupField = "brand"
+	// GroupByColor groups by the color
+	GroupByColor VehicleGroupField = "color"
+	// GroupByFuelType groups by the fuel type
+	GroupByFuelType VehicleGroupField = "fuel_type"
+	// GroupByTransmission groups by the transmission
+	GroupByTransmission VehicleGroupField = "transmission"
+	// GroupByYear groups by the fabrication year
+	GroupByYear VehicleGroupField = "year"
+)
+
+// Key is a method that returns the group of a vehicle
+// - ok is false if the field is unknown
+func (f VehicleGroupField) Key(v Vehicle) (key string, ok bool) {
+	ok = true
+	switch f {
+	case GroupByBrand:
+		key = v.Brand
+	case GroupByColor:
+		key = v.Color
+	case GroupByFuelType:
+		key = v.FuelType
+	case GroupByTransmission:
+		key = v.Transmission
+	case GroupByYear:
+		key = strconv.Itoa(v.FabricationYear)
+	default:
+		ok = false
+	}
+	return
+}
+
+// Valid is a method that returns true if vehicles can be grouped by the field
+func (f VehicleGroupField) Valid() bool {
+	_, ok := f.Key(Vehicle{})
+	return ok
+}
+
+// VehicleStats is a struct that represents the statistics of a numeric field over a group of vehicles
+type VehicleStats struct {
+	// Group is the value of the grouping field, empty when the whole fleet is used
+	Group string
+	// Count is the number of vehicles
+	Count int
+	// Min is the minimum value
+	Min float64
+	// Max is the maximum value
+	Max float64
+	// Mean is the arithmetic mean
+	Mean float64
+	// Median is the 50th percentile
+	Median float64
+	// StdDev is the population standard deviation
+	StdDev float64
+	// P90 is the 90th percentile
+	P90 float64
+	// P99 is the 99th percentile
+	P99 float64
+}
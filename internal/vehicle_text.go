@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// textFolder is the case folder of foldText, stateless and safe for concurrent use
+var textFolder = cases.Fold()
+
+// TextMatch is the way the text criteria of a find are compared with the text fields of the vehicles
+type TextMatch string
+
+const (
+	// TextMatchExact compares texts byte by byte and stores them as given, the legacy behaviour
+	TextMatchExact TextMatch = "exact"
+	// TextMatchNormalized stores texts trimmed and in Unicode NFC, and compares them case folded
+	// - "ford", "FORD" and "Ford " match the same vehicles
+	TextMatchNormalized TextMatch = "normalized"
+)
+
+// Valid is a method that returns true if the text match is known
+func (m TextMatch) Valid() bool {
+	return m == TextMatchExact || m == TextMatchNormalized
+}
+
+// Key is a method that returns the form of a text that is compared
+// - two texts match if their keys are equal
+func (m TextMatch) Key(s string) string {
+	if m == TextMatchNormalized {
+		return foldText(s)
+	}
+	return s
+}
+
+// Vehicle is a method that returns the vehicle with its text fields in the form they are stored
+func (m TextMatch) Vehicle(v Vehicle) Vehicle {
+	if m == TextMatchNormalized {
+		v.Brand = NormalizeText(v.Brand)
+		v.Model = NormalizeText(v.Model)
+		v.Registration = NormalizeText(v.Registration)
+		v.Color = NormalizeText(v.Color)
+		v.FuelType = NormalizeText(v.FuelType)
+		v.Transmission = NormalizeText(v.Transmission)
+	}
+	return v
+}
+
+// NormalizeText is a function that returns a text without surrounding spaces and in Unicode NFC
+func NormalizeText(s string) string {
+	return norm.NFC.String(strings.TrimSpace(s))
+}
+
+// foldText is a function that returns a normalized text case folded, e.g. "Straße " to "strasse"
+func foldText(s string) string {
+	return norm.NFC.String(textFolder.String(NormalizeText(s)))
+}
@@ -0,0 +1,256 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrValidatorInvalidVehicle is an error that represents a vehicle that breaks a validation rule
+	ErrValidatorInvalidVehicle = errors.New("validator: invalid vehicle")
+)
+
+// ValidationRule is the name of the rule a field breaks
+type ValidationRule string
+
+const (
+	// ValidationRuleRequired is a field that can not be blank
+	ValidationRuleRequired ValidationRule = "required"
+	// ValidationRuleFormat is a field that does not match its format
+	ValidationRuleFormat ValidationRule = "format"
+	// ValidationRuleUnique is a field whose value is already used by another vehicle
+	ValidationRuleUnique ValidationRule = "unique"
+	// ValidationRuleRange is a field with a value outside of its plausible range
+	ValidationRuleRange ValidationRule = "range"
+	// ValidationRuleKnown is a field with a value that is not one of the known ones
+	ValidationRuleKnown ValidationRule = "known"
+)
+
+// VehicleFieldError is a struct that represents a field of a vehicle that breaks a rule
+type VehicleFieldError struct {
+	// Field is the name of the field, as in the JSON representation of a vehicle
+	Field string
+	// Rule is the rule the field breaks
+	Rule ValidationRule
+	// Message is a human readable description of the problem
+	Message string
+}
+
+// Error is a method that returns the error message
+func (e VehicleFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// VehicleValidationError is an error that represents every field of a vehicle that breaks a rule
+type VehicleValidationError struct {
+	// Fields are the field errors, in the order the rules were checked
+	Fields []VehicleFieldError
+}
+
+// Error is a method that returns the error message
+func (e *VehicleValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		messages = append(messages, f.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrValidatorInvalidVehicle.Error(), strings.Join(messages, "; "))
+}
+
+// Unwrap is a method that returns ErrValidatorInvalidVehicle
+func (e *VehicleValidationError) Unwrap() error {
+	return ErrValidatorInvalidVehicle
+}
+
+// VehicleRegistry is an interface that represents the vehicles a registration must be unique among
+// - RepositoryReadVehicle satisfies it
+type VehicleRegistry interface {
+	// FindByRegistration is a method that returns a map of vehicles that match the registration
+	// - returns ErrRepositoryNotFound if no vehicle matches
+	FindByRegistration(registration string) (v map[int]Vehicle, err error)
+}
+
+// VehicleValidator is an interface that represents a validator of vehicles
+type VehicleValidator interface {
+	// Validate is a method that returns a *VehicleValidationError if the vehicle breaks any rule
+	// - registry can be nil, in which case uniqueness is not checked
+	// - the vehicle with the same id in registry is the vehicle itself, not a duplicate
+	Validate(v Vehicle, registry VehicleRegistry) (err error)
+}
+
+// VehicleRule is a function that returns the field errors of a vehicle for one rule
+// - err is only returned when the rule could not be checked
+type VehicleRule func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error)
+
+// NewVehicleValidatorRules is a function that returns a new instance of VehicleValidatorRules
+func NewVehicleValidatorRules(rules ...VehicleRule) *VehicleValidatorRules {
+	return &VehicleValidatorRules{rules: rules}
+}
+
+// VehicleValidatorRules is a struct that implements VehicleValidator by checking every rule in order
+type VehicleValidatorRules struct {
+	// rules are the rules checked
+	rules []VehicleRule
+}
+
+// Validate is a method that returns a *VehicleValidationError if the vehicle breaks any rule
+func (vv *VehicleValidatorRules) Validate(v Vehicle, registry VehicleRegistry) (err error) {
+	var fields []VehicleFieldError
+	for _, rule := range vv.rules {
+		var f []VehicleFieldError
+		f, err = rule(v, registry)
+		if err != nil {
+			return
+		}
+		fields = append(fields, f...)
+	}
+
+	if len(fields) > 0 {
+		err = &VehicleValidationError{Fields: fields}
+	}
+	return
+}
+
+// RuleRegistrationFormat is a function that returns the rule of a non blank registration matching the format of a country
+// - formats are the registration formats by country code, a registration must match any of them
+// - with no formats any non blank registration is valid
+func RuleRegistrationFormat(formats map[string]*regexp.Regexp) VehicleRule {
+	// countries in a stable order for the message
+	countries := make([]string, 0, len(formats))
+	for country := range formats {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+
+	return func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error) {
+		registration := strings.TrimSpace(v.Registration)
+		if registration == "" {
+			fields = append(fields, VehicleFieldError{Field: "registration", Rule: ValidationRuleRequired, Message: "must not be blank"})
+			return
+		}
+		if len(countries) == 0 {
+			return
+		}
+
+		for _, country := range countries {
+			if formats[country].MatchString(registration) {
+				return
+			}
+		}
+		fields = append(fields, VehicleFieldError{
+			Field:   "registration",
+			Rule:    ValidationRuleFormat,
+			Message: fmt.Sprintf("%q does not match the format of any country (%s)", registration, strings.Join(countries, ", ")),
+		})
+		return
+	}
+}
+
+// RuleUniqueRegistration is a function that returns the rule of a registration not used by any other vehicle of the registry
+// - a vehicle that keeps its registration is not checked, so that vehicles loaded with duplicate registrations can still be edited
+func RuleUniqueRegistration() VehicleRule {
+	return func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error) {
+		if registry == nil || strings.TrimSpace(v.Registration) == "" {
+			return
+		}
+
+		vs, err := registry.FindByRegistration(v.Registration)
+		if err != nil {
+			if errors.Is(err, ErrRepositoryNotFound) {
+				err = nil
+			}
+			return
+		}
+
+		// registration unchanged: the registry holds the vehicle itself with it
+		if _, ok := vs[v.Id]; ok && v.Id != 0 {
+			return
+		}
+
+		// smallest other id, for a stable message
+		other := 0
+		for id := range vs {
+			if id != v.Id && (other == 0 || id < other) {
+				other = id
+			}
+		}
+		if other != 0 {
+			fields = append(fields, VehicleFieldError{
+				Field:   "registration",
+				Rule:    ValidationRuleUnique,
+				Message: fmt.Sprintf("%q is already used by vehicle %d", v.Registration, other),
+			})
+		}
+		return
+	}
+}
+
+// RuleYearRange is a function that returns the rule of a fabrication year between min and max, both inclusive
+func RuleYearRange(min int, max int) VehicleRule {
+	return func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error) {
+		if v.FabricationYear < min || v.FabricationYear > max {
+			fields = append(fields, VehicleFieldError{
+				Field:   "year",
+				Rule:    ValidationRuleRange,
+				Message: fmt.Sprintf("must be between %d and %d, got %d", min, max, v.FabricationYear),
+			})
+		}
+		return
+	}
+}
+
+// RuleNonNegative is a function that returns the rule of non negative capacity, speed, weight and dimensions
+func RuleNonNegative() VehicleRule {
+	return func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error) {
+		values := []struct {
+			field string
+			value float64
+		}{
+			{field: "passengers", value: float64(v.Capacity)},
+			{field: "max_speed", value: v.MaxSpeed},
+			{field: "weight", value: v.Weight},
+			{field: "height", value: v.Height},
+			{field: "length", value: v.Length},
+			{field: "width", value: v.Width},
+		}
+		for _, value := range values {
+			if value.value < 0 {
+				fields = append(fields, VehicleFieldError{
+					Field:   value.field,
+					Rule:    ValidationRuleRange,
+					Message: fmt.Sprintf("must not be negative, got %v", value.value),
+				})
+			}
+		}
+		return
+	}
+}
+
+// RuleKnownValues is a function that returns the rule of a fuel type and a transmission among the known ones
+// - values are compared case insensitively, an empty list accepts any value
+func RuleKnownValues(fuelTypes []string, transmissions []string) VehicleRule {
+	known := func(field string, value string, values []string) (fields []VehicleFieldError) {
+		if len(values) == 0 {
+			return
+		}
+		for _, v := range values {
+			if TextMatchNormalized.Key(v) == TextMatchNormalized.Key(value) {
+				return
+			}
+		}
+		fields = append(fields, VehicleFieldError{
+			Field:   field,
+			Rule:    ValidationRuleKnown,
+			Message: fmt.Sprintf("%q is not one of %s", value, strings.Join(values, ", ")),
+		})
+		return
+	}
+
+	return func(v Vehicle, registry VehicleRegistry) (fields []VehicleFieldError, err error) {
+		fields = append(fields, known("fuel_type", v.FuelType, fuelTypes)...)
+		fields = append(fields, known("transmission", v.Transmission, transmissions)...)
+		return
+	}
+}
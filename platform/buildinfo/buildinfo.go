@@ -0,0 +1,53 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Commit and Time are set at build time, e.g.
+// go build -ldflags "-X app/platform/buildinfo.Commit=$(git rev-parse HEAD) -X app/platform/buildinfo.Time=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+// - when empty, the version control settings stamped by the go command are used instead
+var (
+	// Commit is the git commit the binary was built from
+	Commit string
+	// Time is the time the binary was built at, in RFC 3339
+	Time string
+)
+
+// Info is a struct that represents the build of the running binary
+type Info struct {
+	// Commit is the git commit, empty if unknown
+	Commit string
+	// Time is the build time, or the commit time when the build time was not set, empty if unknown
+	Time string
+	// Modified is true if the working tree had uncommitted changes, as stamped by the go command
+	Modified bool
+	// GoVersion is the version of Go the binary was built with
+	GoVersion string
+}
+
+// Read is a function that returns the build of the running binary
+func Read() (i Info) {
+	i = Info{Commit: Commit, Time: Time, GoVersion: runtime.Version()}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if i.Commit == "" {
+				i.Commit = s.Value
+			}
+		case "vcs.time":
+			if i.Time == "" {
+				i.Time = s.Value
+			}
+		case "vcs.modified":
+			i.Modified = s.Value == "true"
+		}
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRead is a function that tests the Read function
+func TestRead(t *testing.T) {
+	t.Run("should prefer the values set at build time", func(t *testing.T) {
+		// Arrange
+		commit, time := Commit, Time
+		t.Cleanup(func() { Commit, Time = commit, time })
+		Commit, Time = "a1c2108", "2026-10-17T10:00:00Z"
+
+		// Act
+		i := Read()
+
+		// Assert
+		assert.Equal(t, "a1c2108", i.Commit)
+		assert.Equal(t, "2026-10-17T10:00:00Z", i.Time)
+		assert.Equal(t, runtime.Version(), i.GoVersion)
+	})
+}
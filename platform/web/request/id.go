@@ -0,0 +1,46 @@
+package request
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID is the header that carries the id of a request, in the request and in its response
+const HeaderRequestID = "X-Request-Id"
+
+// contextKeyRequestID is the key of the request id in the gin context
+const contextKeyRequestID = "request_id"
+
+// validRequestID matches the request ids accepted from clients, anything else is replaced
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// IDGin returns a middleware that gives every request an id
+// - the id sent by the client in HeaderRequestID is kept if it is valid, otherwise a random one is generated
+// - the id is set in HeaderRequestID of the response and can be read with IDFromGin
+func IDGin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = NewID()
+		}
+
+		ctx.Set(contextKeyRequestID, id)
+		ctx.Header(HeaderRequestID, id)
+		ctx.Next()
+	}
+}
+
+// IDFromGin returns the id of the request, empty if IDGin did not run
+func IDFromGin(ctx *gin.Context) string {
+	return ctx.GetString(contextKeyRequestID)
+}
+
+// NewID returns a random request id of 16 hexadecimal characters
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
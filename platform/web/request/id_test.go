@@ -0,0 +1,68 @@
+package request_test
+
+import (
+	"app/platform/web/request"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for IDGin function
+func TestIDGin(t *testing.T) {
+	// serve is a function that returns the response and the id seen by the handler of a request with the header id
+	serve := func(id string) (rr *httptest.ResponseRecorder, seen string) {
+		server := gin.New()
+		server.Use(request.IDGin())
+		server.GET("/", func(ctx *gin.Context) {
+			seen = request.IDFromGin(ctx)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if id != "" {
+			req.Header.Set(request.HeaderRequestID, id)
+		}
+		rr = httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return
+	}
+
+	t.Run("success - keeps the id of the client", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr, seen := serve("client-id.1")
+
+		// assert
+		require.Equal(t, "client-id.1", seen)
+		require.Equal(t, "client-id.1", rr.Header().Get(request.HeaderRequestID))
+	})
+
+	t.Run("success - generates an id when there is none", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr, seen := serve("")
+
+		// assert
+		require.Len(t, seen, 16)
+		require.Equal(t, seen, rr.Header().Get(request.HeaderRequestID))
+	})
+
+	t.Run("success - replaces an invalid id", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr, seen := serve("bad id" + strings.Repeat("x", 200))
+
+		// assert
+		require.Len(t, seen, 16)
+		require.Equal(t, seen, rr.Header().Get(request.HeaderRequestID))
+	})
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentTypeProblem is the content type of a problem details body (RFC 7807)
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is a struct that represents a problem details body (RFC 7807)
+// - Code, RequestID and Errors are extension members
+type Problem struct {
+	// Type is a URI that identifies the kind of problem, about:blank when there is none
+	Type string `json:"type"`
+	// Title is the short summary of the kind of problem, the status text when empty
+	Title string `json:"title"`
+	// Status is the HTTP status code
+	Status int `json:"status"`
+	// Detail is the explanation of this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is a URI that identifies this occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+	// Code is the machine readable code of the problem
+	Code string `json:"code"`
+	// RequestID is the id of the request that caused the problem
+	RequestID string `json:"request_id,omitempty"`
+	// Errors are the details of the problem by item, such as the invalid fields of a body
+	Errors any `json:"errors,omitempty"`
+}
+
+// ProblemJSON writes a problem details response
+// - an invalid status is written as 500, empty Type and Title take their defaults
+func ProblemJSON(w http.ResponseWriter, p Problem) {
+	p = problemDefaults(p)
+	bytes, err := json.Marshal(p)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// write response
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(p.Status)
+	w.Write(bytes)
+}
+
+// problemDefaults returns the problem with the defaults of its empty members
+func problemDefaults(p Problem) Problem {
+	if p.Status < 400 || p.Status > 599 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	return p
+}
@@ -0,0 +1,29 @@
+package response
+
+import (
+	"app/platform/web/request"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemGin writes a problem details response
+// - an invalid status is written as 500, empty Type and Title take their defaults
+// - an empty RequestID is the id given to the request by request.IDGin
+func ProblemGin(ctx *gin.Context, p Problem) {
+	p = problemDefaults(p)
+	if p.RequestID == "" {
+		p.RequestID = request.IDFromGin(ctx)
+	}
+	bytes, err := json.Marshal(p)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// write response
+	ctx.Header("Content-Type", ContentTypeProblem)
+	ctx.Status(p.Status)
+	ctx.Writer.Write(bytes)
+}
@@ -0,0 +1,70 @@
+package response_test
+
+import (
+	"app/platform/web/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ProblemJSON function
+func TestProblemJSON(t *testing.T) {
+	t.Run("404 - defaults of type and title", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr := httptest.NewRecorder()
+		p := response.Problem{Status: http.StatusNotFound, Code: "vehicle_not_found", Detail: "vehicle not found"}
+		response.ProblemJSON(rr, p)
+
+		// assert
+		expectedHeader := http.Header{"Content-Type": []string{"application/problem+json"}}
+		expectedCode := http.StatusNotFound
+		expectedBody := `{"type":"about:blank","title":"Not Found","status":404,"detail":"vehicle not found","code":"vehicle_not_found"}`
+		require.Equal(t, expectedHeader, rr.Header())
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+
+	t.Run("422 - extension members", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr := httptest.NewRecorder()
+		p := response.Problem{
+			Type:      "https://example.com/problems/invalid-vehicle",
+			Title:     "Invalid vehicle",
+			Status:    http.StatusUnprocessableEntity,
+			Code:      "invalid_vehicle",
+			RequestID: "abc",
+			Errors:    []string{"year"},
+		}
+		response.ProblemJSON(rr, p)
+
+		// assert
+		expectedCode := http.StatusUnprocessableEntity
+		expectedBody := `{"type":"https://example.com/problems/invalid-vehicle","title":"Invalid vehicle","status":422,"code":"invalid_vehicle","request_id":"abc","errors":["year"]}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+
+	t.Run("500 - invalid status", func(t *testing.T) {
+		// arrange
+		// ...
+
+		// act
+		rr := httptest.NewRecorder()
+		p := response.Problem{Status: http.StatusOK, Code: "internal_error"}
+		response.ProblemJSON(rr, p)
+
+		// assert
+		expectedCode := http.StatusInternalServerError
+		expectedBody := `{"type":"about:blank","title":"Internal Server Error","status":500,"code":"internal_error"}`
+		require.Equal(t, expectedCode, rr.Code)
+		require.JSONEq(t, expectedBody, rr.Body.String())
+	})
+}